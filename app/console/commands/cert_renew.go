@@ -2,8 +2,6 @@ package commands
 
 import (
 	"context"
-	"crypto/x509"
-	"encoding/pem"
 
 	"github.com/goravel/framework/contracts/console"
 	"github.com/goravel/framework/contracts/console/command"
@@ -49,34 +47,45 @@ func (receiver *CertRenew) Handle(console.Context) error {
 		return err
 	}
 
+	certService := services.NewCertImpl()
 	for _, cert := range certs {
-		if !cert.AutoRenew {
+		// 只续签通过 ACME 签发的证书
+		if !cert.AutoRenew || cert.CertURL == nil || cert.User == nil {
 			continue
 		}
 
-		block, _ := pem.Decode([]byte(cert.Cert))
-		if block != nil {
-			data, err := x509.ParseCertificate(block.Bytes)
-			if err != nil {
-				continue
+		// 到期查询 ARI 续签信息，更新续签时间
+		if cert.RenewCheckAt == nil || carbon.Now().Gte(cert.RenewCheckAt.Carbon) {
+			if err = certService.RefreshRenewal(cert.ID); err != nil {
+				facades.Log().Tags("面板", "证书管理").With(map[string]any{
+					"cert_id": cert.ID,
+					"error":   err.Error(),
+				}).Infof("更新证书续签计划失败")
 			}
-
-			// 结束时间大于 7 天的证书不续签
-			endTime := carbon.FromStdTime(data.NotAfter)
-			if endTime.Gt(carbon.Now().AddDays(7)) {
+			if err = facades.Orm().Query().Where("id = ?", cert.ID).First(&cert); err != nil {
 				continue
 			}
 		}
 
-		certService := services.NewCertImpl()
-		_, err = certService.Renew(cert.ID)
-		if err != nil {
+		if cert.RenewAt == nil || carbon.Now().Lt(cert.RenewAt.Carbon) {
+			continue
+		}
+
+		// 续签作为后台任务运行，步骤日志和失败原因记录在任务和证书上
+		if _, err = certService.RenewTask(cert.ID); err != nil {
 			facades.Log().Tags("面板", "证书管理").With(map[string]any{
 				"cert_id": cert.ID,
 				"error":   err.Error(),
-			}).Infof("证书续签失败")
+			}).Infof("创建证书续签任务失败")
 		}
 	}
 
+	// 私有 CA 的吊销列表有有效期，需要定时重新生成
+	if err = services.NewCertAuthorityImpl().RefreshCRL(); err != nil {
+		facades.Log().Tags("面板", "证书管理").With(map[string]any{
+			"error": err.Error(),
+		}).Infof("更新吊销列表失败")
+	}
+
 	return nil
 }
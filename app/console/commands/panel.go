@@ -2,11 +2,14 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/gookit/color"
 	"github.com/goravel/framework/contracts/console"
@@ -18,6 +21,8 @@ import (
 	"panel/app/models"
 	"panel/internal"
 	"panel/internal/services"
+	"panel/pkg/encryption"
+	"panel/pkg/retention"
 	"panel/pkg/tools"
 )
 
@@ -253,11 +258,26 @@ func (receiver *Panel) Handle(ctx console.Context) error {
 		name := arg2
 		path := arg3
 		save := arg4
+		// 保留策略，可以是保留数量，也可以是 last=N,daily=N,weekly=N,monthly=N
+		// 远程存储 ID，以逗号分隔，local 表示只保留本地备份，为空时上传到所有启用的存储
+		var storages []uint
+		if arg5 == "local" {
+			storages = []uint{}
+		} else if len(arg5) > 0 {
+			for _, id := range strings.Split(arg5, ",") {
+				storages = append(storages, cast.ToUint(id))
+			}
+		}
 		hr := `+----------------------------------------------------`
 		if len(backupType) == 0 || len(name) == 0 || len(path) == 0 || len(save) == 0 {
 			color.Redln(translate.Get("commands.panel.backup.paramFail"))
 			return nil
 		}
+		policy, err := retention.Parse(save)
+		if err != nil {
+			color.Redln(translate.Get("commands.panel.backup.policyFail") + ": " + err.Error())
+			return err
+		}
 
 		color.Greenln(hr)
 		color.Greenln("★ " + translate.Get("commands.panel.backup.start") + " [" + carbon.Now().ToDateTimeString() + "]")
@@ -266,26 +286,36 @@ func (receiver *Panel) Handle(ctx console.Context) error {
 		if !tools.Exists(path) {
 			if err := tools.Mkdir(path, 0644); err != nil {
 				color.Redln("|-" + translate.Get("commands.panel.backup.backupDirFail") + ": " + err.Error())
-				return nil
+				return err
 			}
 		}
 
+		start := time.Now()
+		var uploadFile string
+		// 备份失败时写入备份记录，并返回错误使定时任务中后续的校验不再执行
+		backupFail := func(file string, err error) error {
+			if recordErr := services.NewBackupImpl().RecordFailure(backupType, name, file, time.Since(start), err); recordErr != nil {
+				color.Redln("|-" + translate.Get("commands.panel.backup.recordFail") + ": " + recordErr.Error())
+			}
+			return err
+		}
 		switch backupType {
 		case "website":
 			color.Yellowln("|-" + translate.Get("commands.panel.backup.targetSite") + ": " + name)
+			backupFile := path + "/" + name + "_" + carbon.Now().ToShortDateTimeString() + ".zip"
 			var website models.Website
 			if err := facades.Orm().Query().Where("name", name).FirstOrFail(&website); err != nil {
 				color.Redln("|-" + translate.Get("commands.panel.backup.siteNotExist"))
 				color.Greenln(hr)
-				return nil
+				return backupFail(backupFile, errors.New(translate.Get("commands.panel.backup.siteNotExist")))
 			}
 
-			backupFile := path + "/" + website.Name + "_" + carbon.Now().ToShortDateTimeString() + ".zip"
 			if _, err := tools.Exec(`cd '` + website.Path + `' && zip -r '` + backupFile + `' .`); err != nil {
 				color.Redln("|-" + translate.Get("commands.panel.backup.backupFail") + ": " + err.Error())
-				return nil
+				return backupFail(backupFile, err)
 			}
 			color.Greenln("|-" + translate.Get("commands.panel.backup.backupSuccess"))
+			uploadFile = backupFile
 
 		case "mysql":
 			rootPassword := services.NewSettingImpl().Get(models.SettingKeyMysqlRootPassword)
@@ -295,32 +325,33 @@ func (receiver *Panel) Handle(ctx console.Context) error {
 			if err != nil {
 				color.Redln("|-" + translate.Get("commands.panel.backup.mysqlBackupFail") + ": " + err.Error())
 				color.Greenln(hr)
-				return nil
+				return backupFail(path+"/"+backupFile+".zip", err)
 			}
 
 			color.Greenln("|-" + translate.Get("commands.panel.backup.targetMysql") + ": " + name)
 			color.Greenln("|-" + translate.Get("commands.panel.backup.startExport"))
 			if _, err = tools.Exec(`mysqldump -uroot ` + name + ` > /tmp/` + backupFile + ` 2>&1`); err != nil {
 				color.Redln("|-" + translate.Get("commands.panel.backup.exportFail") + ": " + err.Error())
-				return nil
+				return backupFail(path+"/"+backupFile+".zip", err)
 			}
 			color.Greenln("|-" + translate.Get("commands.panel.backup.exportSuccess"))
 			color.Greenln("|-" + translate.Get("commands.panel.backup.startCompress"))
 			if _, err = tools.Exec("cd /tmp && zip -r " + backupFile + ".zip " + backupFile); err != nil {
 				color.Redln("|-" + translate.Get("commands.panel.backup.compressFail") + ": " + err.Error())
-				return nil
+				return backupFail(path+"/"+backupFile+".zip", err)
 			}
 			if err := tools.Remove("/tmp/" + backupFile); err != nil {
 				color.Redln("|-" + translate.Get("commands.panel.backup.deleteFail") + ": " + err.Error())
-				return nil
+				return backupFail(path+"/"+backupFile+".zip", err)
 			}
 			color.Greenln("|-" + translate.Get("commands.panel.backup.compressSuccess"))
 			color.Greenln("|-" + translate.Get("commands.panel.backup.startMove"))
 			if err := tools.Mv("/tmp/"+backupFile+".zip", path+"/"+backupFile+".zip"); err != nil {
 				color.Redln("|-" + translate.Get("commands.panel.backup.moveFail") + ": " + err.Error())
-				return nil
+				return backupFail(path+"/"+backupFile+".zip", err)
 			}
 			color.Greenln("|-" + translate.Get("commands.panel.backup.moveSuccess"))
+			uploadFile = path + "/" + backupFile + ".zip"
 			_ = os.Unsetenv("MYSQL_PWD")
 			color.Greenln("|-" + translate.Get("commands.panel.backup.success"))
 
@@ -330,72 +361,273 @@ func (receiver *Panel) Handle(ctx console.Context) error {
 			if err != nil {
 				color.Redln("|-" + translate.Get("commands.panel.backup.databaseGetFail") + ": " + err.Error())
 				color.Greenln(hr)
-				return nil
+				return backupFail(path+"/"+backupFile+".zip", err)
 			}
 			if !strings.Contains(check, name) {
 				color.Redln("|-" + translate.Get("commands.panel.backup.databaseNotExist"))
 				color.Greenln(hr)
-				return nil
+				return backupFail(path+"/"+backupFile+".zip", errors.New(translate.Get("commands.panel.backup.databaseNotExist")))
 			}
 
 			color.Greenln("|-" + translate.Get("commands.panel.backup.targetPostgres") + ": " + name)
 			color.Greenln("|-" + translate.Get("commands.panel.backup.startExport"))
 			if _, err = tools.Exec(`su - postgres -c "pg_dump '` + name + `'" > /tmp/` + backupFile + ` 2>&1`); err != nil {
 				color.Redln("|-" + translate.Get("commands.panel.backup.exportFail") + ": " + err.Error())
-				return nil
+				return backupFail(path+"/"+backupFile+".zip", err)
 			}
 			color.Greenln("|-" + translate.Get("commands.panel.backup.exportSuccess"))
 			color.Greenln("|-" + translate.Get("commands.panel.backup.startCompress"))
 			if _, err = tools.Exec("cd /tmp && zip -r " + backupFile + ".zip " + backupFile); err != nil {
 				color.Redln("|-" + translate.Get("commands.panel.backup.compressFail") + ": " + err.Error())
-				return nil
+				return backupFail(path+"/"+backupFile+".zip", err)
 			}
 			if err := tools.Remove("/tmp/" + backupFile); err != nil {
 				color.Redln("|-" + translate.Get("commands.panel.backup.deleteFail") + ": " + err.Error())
-				return nil
+				return backupFail(path+"/"+backupFile+".zip", err)
 			}
 			color.Greenln("|-" + translate.Get("commands.panel.backup.compressSuccess"))
 			color.Greenln("|-" + translate.Get("commands.panel.backup.startMove"))
 			if err := tools.Mv("/tmp/"+backupFile+".zip", path+"/"+backupFile+".zip"); err != nil {
 				color.Redln("|-" + translate.Get("commands.panel.backup.moveFail") + ": " + err.Error())
-				return nil
+				return backupFail(path+"/"+backupFile+".zip", err)
 			}
 			color.Greenln("|-" + translate.Get("commands.panel.backup.moveSuccess"))
+			uploadFile = path + "/" + backupFile + ".zip"
 			color.Greenln("|-" + translate.Get("commands.panel.backup.success"))
+
+		case "redis":
+			backupFile := path + "/" + name + "_" + carbon.Now().ToShortDateTimeString() + ".zip"
+			color.Greenln("|-" + translate.Get("commands.panel.backup.targetRedis"))
+			color.Greenln("|-" + translate.Get("commands.panel.backup.startBgsave"))
+			if err := services.NewBackupImpl().RedisDump(backupFile); err != nil {
+				color.Redln("|-" + translate.Get("commands.panel.backup.backupFail") + ": " + err.Error())
+				return backupFail(backupFile, err)
+			}
+			uploadFile = backupFile
+			color.Greenln("|-" + translate.Get("commands.panel.backup.success"))
+		}
+
+		if len(uploadFile) > 0 && services.NewBackupImpl().Encryption() > 0 {
+			color.Greenln("|-" + translate.Get("commands.panel.backup.startEncrypt"))
+			encryptedFile, err := services.NewBackupImpl().Encrypt(uploadFile)
+			if err != nil {
+				color.Redln("|-" + translate.Get("commands.panel.backup.encryptFail") + ": " + err.Error())
+				return backupFail(uploadFile, err)
+			}
+			uploadFile = encryptedFile
+			color.Greenln("|-" + translate.Get("commands.panel.backup.encryptSuccess"))
+		}
+		if len(uploadFile) > 0 {
+			color.Greenln("|-" + translate.Get("commands.panel.backup.startUpload"))
+			uploaded, err := services.NewBackupImpl().Upload(backupType, uploadFile, storages)
+			if err != nil {
+				color.Redln("|-" + translate.Get("commands.panel.backup.uploadFail") + ": " + err.Error())
+			} else {
+				color.Greenln("|-" + translate.Get("commands.panel.backup.uploadSuccess"))
+			}
+			record, err := services.NewBackupImpl().Record(backupType, name, uploadFile, time.Since(start), uploaded)
+			if err != nil {
+				color.Redln("|-" + translate.Get("commands.panel.backup.recordFail") + ": " + err.Error())
+			} else {
+				color.Greenln("|-SHA-256: " + record.SHA256)
+			}
 		}
 
 		color.Greenln(hr)
-		files, err := os.ReadDir(path)
+		pruned, err := services.NewBackupImpl().Prune(backupType, path, name, policy, false)
+		for _, item := range pruned {
+			if !item.Keep {
+				color.Yellowln("|-" + translate.Get("commands.panel.backup.cleanBackup") + ": " + item.Name + " [" + strings.Join(item.Storages, ", ") + "]")
+			}
+		}
 		if err != nil {
 			color.Redln("|-" + translate.Get("commands.panel.backup.cleanupFail") + ": " + err.Error())
 			return nil
 		}
-		var filteredFiles []os.FileInfo
-		for _, file := range files {
-			if strings.HasPrefix(file.Name(), name) && strings.HasSuffix(file.Name(), ".zip") {
-				fileInfo, err := os.Stat(filepath.Join(path, file.Name()))
-				if err != nil {
-					continue
-				}
-				filteredFiles = append(filteredFiles, fileInfo)
-			}
+		color.Greenln("|-" + translate.Get("commands.panel.backup.cleanupSuccess"))
+		color.Greenln(hr)
+		color.Greenln("☆ " + translate.Get("commands.panel.backup.success") + " [" + carbon.Now().ToDateTimeString() + "]")
+		color.Greenln(hr)
+
+	case "snapshot":
+		name := arg1
+		save := arg2
+		hr := `+----------------------------------------------------`
+		if len(name) == 0 || len(save) == 0 {
+			color.Redln(translate.Get("commands.panel.snapshot.paramFail"))
+			return nil
 		}
-		sort.Slice(filteredFiles, func(i, j int) bool {
-			return filteredFiles[i].ModTime().After(filteredFiles[j].ModTime())
-		})
-		for i := cast.ToInt(save); i < len(filteredFiles); i++ {
-			fileToDelete := filepath.Join(path, filteredFiles[i].Name())
-			color.Yellowln("|-" + translate.Get("commands.panel.backup.cleanBackup") + ": " + fileToDelete)
-			if err := tools.Remove(fileToDelete); err != nil {
-				color.Redln("|-" + translate.Get("commands.panel.backup.cleanupFail") + ": " + err.Error())
+		policy, err := retention.Parse(save)
+		if err != nil {
+			color.Redln(translate.Get("commands.panel.snapshot.policyFail") + ": " + err.Error())
+			return nil
+		}
+
+		color.Greenln(hr)
+		color.Greenln("★ " + translate.Get("commands.panel.snapshot.start") + " [" + carbon.Now().ToDateTimeString() + "]")
+		color.Greenln(hr)
+
+		color.Yellowln("|-" + translate.Get("commands.panel.snapshot.targetSite") + ": " + name)
+		var website models.Website
+		if err := facades.Orm().Query().Where("name", name).FirstOrFail(&website); err != nil {
+			color.Redln("|-" + translate.Get("commands.panel.snapshot.siteNotExist"))
+			color.Greenln(hr)
+			return nil
+		}
+
+		backup := services.NewBackupImpl()
+		snapshot, err := backup.WebsiteSnapshot(website)
+		if err != nil {
+			color.Redln("|-" + translate.Get("commands.panel.snapshot.fail") + ": " + err.Error())
+			color.Greenln(hr)
+			return nil
+		}
+		color.Greenln("|-" + translate.Get("commands.panel.snapshot.success") + ": " + snapshot.ID)
+		color.Greenln(fmt.Sprintf("|-%s: %d, %s: %s", translate.Get("commands.panel.snapshot.files"), snapshot.Stats.Files, translate.Get("commands.panel.snapshot.size"), tools.FormatBytes(float64(snapshot.Stats.Size))))
+		color.Greenln(fmt.Sprintf("|-%s: %s (%d), %s: %s", translate.Get("commands.panel.snapshot.newData"), tools.FormatBytes(float64(snapshot.Stats.NewSize)), snapshot.Stats.NewChunks, translate.Get("commands.panel.snapshot.storedData"), tools.FormatBytes(float64(snapshot.Stats.StoredSize))))
+		color.Greenln(fmt.Sprintf("|-%s: %s (%d)", translate.Get("commands.panel.snapshot.reusedData"), tools.FormatBytes(float64(snapshot.Stats.ReusedSize)), snapshot.Stats.ReusedChunks))
+
+		color.Greenln(hr)
+		snapshots, err := backup.WebsiteSnapshotList(website)
+		if err != nil {
+			color.Redln("|-" + translate.Get("commands.panel.snapshot.cleanupFail") + ": " + err.Error())
+			return nil
+		}
+		items := make([]retention.Item, len(snapshots))
+		for i, item := range snapshots {
+			items[i] = retention.Item{Name: item.ID, Time: item.Time}
+		}
+		for _, decision := range policy.Apply(items) {
+			if decision.Keep {
+				continue
+			}
+			color.Yellowln("|-" + translate.Get("commands.panel.snapshot.cleanSnapshot") + ": " + decision.Name)
+			if err = backup.WebsiteSnapshotDelete(website, decision.Name); err != nil {
+				color.Redln("|-" + translate.Get("commands.panel.snapshot.cleanupFail") + ": " + err.Error())
 				return nil
 			}
 		}
-		color.Greenln("|-" + translate.Get("commands.panel.backup.cleanupSuccess"))
+		pruned, err := backup.SnapshotPrune()
+		if err != nil {
+			color.Redln("|-" + translate.Get("commands.panel.snapshot.cleanupFail") + ": " + err.Error())
+			return nil
+		}
+		color.Greenln(fmt.Sprintf("|-%s: %s (%d)", translate.Get("commands.panel.snapshot.cleanupSuccess"), tools.FormatBytes(float64(pruned.Size)), pruned.Chunks))
 		color.Greenln(hr)
-		color.Greenln("☆ " + translate.Get("commands.panel.backup.success") + " [" + carbon.Now().ToDateTimeString() + "]")
+		color.Greenln("☆ " + translate.Get("commands.panel.snapshot.end") + " [" + carbon.Now().ToDateTimeString() + "]")
 		color.Greenln(hr)
 
+	case "backupPrune":
+		backupType := arg1
+		name := arg2
+		// 保留策略，可以是保留数量，也可以是 last=N,daily=N,weekly=N,monthly=N
+		save := arg3
+		dryRun := arg4 == "dry-run"
+		hr := `+----------------------------------------------------`
+		if !slices.Contains([]string{internal.BackupTypeWebsite, internal.BackupTypeMysql, internal.BackupTypePostgresql, internal.BackupTypeRedis}, backupType) || len(name) == 0 || len(save) == 0 {
+			color.Redln(translate.Get("commands.panel.backupPrune.paramFail"))
+			return nil
+		}
+		policy, err := retention.Parse(save)
+		if err != nil {
+			color.Redln(translate.Get("commands.panel.backupPrune.policyFail") + ": " + err.Error())
+			return nil
+		}
+
+		color.Greenln(hr)
+		color.Greenln("★ " + translate.Get("commands.panel.backupPrune.start") + " [" + carbon.Now().ToDateTimeString() + "]")
+		if dryRun {
+			color.Yellowln("|-" + translate.Get("commands.panel.backupPrune.dryRun"))
+		}
+		color.Greenln(hr)
+
+		pruned, err := services.NewBackupImpl().Prune(backupType, "", name, policy, dryRun)
+		for _, item := range pruned {
+			location := " [" + strings.Join(item.Storages, ", ") + "]"
+			if item.Keep {
+				color.Greenln("|-" + translate.Get("commands.panel.backupPrune.keep") + ": " + item.Name + location + " (" + strings.Join(item.Reasons, ", ") + ")")
+			} else if dryRun {
+				color.Yellowln("|-" + translate.Get("commands.panel.backupPrune.willDelete") + ": " + item.Name + location)
+			} else {
+				color.Yellowln("|-" + translate.Get("commands.panel.backupPrune.delete") + ": " + item.Name + location)
+			}
+		}
+		if err != nil {
+			color.Redln("|-" + translate.Get("commands.panel.backupPrune.fail") + ": " + err.Error())
+			color.Greenln(hr)
+			return nil
+		}
+		color.Greenln(hr)
+		color.Greenln("☆ " + translate.Get("commands.panel.backupPrune.success") + " [" + carbon.Now().ToDateTimeString() + "]")
+		color.Greenln(hr)
+
+	case "backupVerify":
+		// 备份记录 ID，或者备份类型和名称，后者校验该名称最新的备份
+		var backup models.Backup
+		if len(arg1) > 0 && len(arg2) == 0 {
+			_ = facades.Orm().Query().Where("id", cast.ToUint(arg1)).First(&backup)
+		} else if len(arg1) > 0 {
+			_ = facades.Orm().Query().Where("type", arg1).Where("source", arg2).Order("id desc").First(&backup)
+		} else {
+			color.Redln(translate.Get("commands.panel.backupVerify.paramFail"))
+			return nil
+		}
+		if backup.ID == 0 {
+			color.Redln(translate.Get("commands.panel.backupVerify.notExist"))
+			return nil
+		}
+
+		hr := `+----------------------------------------------------`
+		color.Greenln(hr)
+		color.Greenln("★ " + translate.Get("commands.panel.backupVerify.start") + " [" + carbon.Now().ToDateTimeString() + "]")
+		color.Greenln(hr)
+		color.Yellowln("|-" + translate.Get("commands.panel.backupVerify.target") + ": " + backup.Name)
+		if err := services.NewBackupImpl().Verify(backup.ID); err != nil {
+			color.Redln("|-" + translate.Get("commands.panel.backupVerify.fail") + ": " + err.Error())
+			color.Greenln(hr)
+			return nil
+		}
+		color.Greenln("|-" + translate.Get("commands.panel.backupVerify.success"))
+		color.Greenln(hr)
+		color.Greenln("☆ " + translate.Get("commands.panel.backupVerify.end") + " [" + carbon.Now().ToDateTimeString() + "]")
+		color.Greenln(hr)
+
+	case "backupDecrypt":
+		file := arg1
+		identityFile := arg2
+		if len(file) == 0 {
+			color.Redln(translate.Get("commands.panel.backupDecrypt.paramFail"))
+			return nil
+		}
+
+		// 未指定私钥文件时从环境变量读取密码，避免密码出现在命令历史中
+		passphrase := os.Getenv("PANEL_BACKUP_PASSPHRASE")
+		if len(identityFile) == 0 && len(passphrase) == 0 {
+			color.Redln(translate.Get("commands.panel.backupDecrypt.keyRequired"))
+			return nil
+		}
+		if len(identityFile) > 0 {
+			passphrase = ""
+		}
+		identities, err := encryption.ParseIdentities(passphrase, identityFile)
+		if err != nil {
+			color.Redln(translate.Get("commands.panel.backupDecrypt.keyFail") + ": " + err.Error())
+			return nil
+		}
+
+		plain, _, encrypted := encryption.ParseName(filepath.Base(file))
+		if !encrypted {
+			color.Redln(translate.Get("commands.panel.backupDecrypt.notEncrypted"))
+			return nil
+		}
+		target := filepath.Join(filepath.Dir(file), plain)
+		if err = encryption.DecryptFileWith(file, target, identities...); err != nil {
+			color.Redln(translate.Get("commands.panel.backupDecrypt.fail") + ": " + err.Error())
+			return nil
+		}
+
+		color.Greenln(translate.Get("commands.panel.backupDecrypt.success") + ": " + target)
+
 	case "cutoff":
 		name := arg1
 		save := arg2
@@ -645,6 +877,86 @@ func (receiver *Panel) Handle(ctx console.Context) error {
 
 		color.Greenln(translate.Get("commands.panel.removeSite.success"))
 
+	case "websiteBatch":
+		batchAction := arg1
+		hr := `+----------------------------------------------------`
+		if len(batchAction) == 0 || len(arg2) == 0 {
+			color.Redln(translate.Get("commands.panel.websiteBatch.paramFail"))
+			return nil
+		}
+
+		var ids []uint
+		for _, id := range strings.Split(arg2, ",") {
+			ids = append(ids, cast.ToUint(id))
+		}
+
+		color.Greenln(hr)
+		color.Greenln("★ " + translate.Get("commands.panel.websiteBatch.start") + " [" + carbon.Now().ToDateTimeString() + "]")
+		color.Greenln(hr)
+		if err := services.NewWebsiteImpl().BatchRun(batchAction, ids, cast.ToInt(arg3), os.Stdout); err != nil {
+			color.Redln("|-" + translate.Get("commands.panel.websiteBatch.fail") + ": " + err.Error())
+			color.Greenln(hr)
+			return err
+		}
+		color.Greenln(hr)
+		color.Greenln("☆ " + translate.Get("commands.panel.websiteBatch.success") + " [" + carbon.Now().ToDateTimeString() + "]")
+		color.Greenln(hr)
+
+	case "deploy":
+		name := arg1
+		hr := `+----------------------------------------------------`
+		if len(name) == 0 {
+			color.Redln(translate.Get("commands.panel.deploy.paramFail"))
+			return nil
+		}
+
+		website := services.NewWebsiteImpl()
+		id, err := website.GetIDByName(name)
+		if err != nil || id == 0 {
+			color.Redln(translate.Get("commands.panel.deploy.siteNotExist"))
+			return nil
+		}
+
+		color.Greenln(hr)
+		color.Greenln("★ " + translate.Get("commands.panel.deploy.start") + " [" + carbon.Now().ToDateTimeString() + "]")
+		color.Greenln(hr)
+		color.Yellowln("|-" + translate.Get("commands.panel.deploy.targetSite") + ": " + name)
+		if err = services.NewDeployImpl().Run(id, os.Stdout); err != nil {
+			color.Redln("|-" + translate.Get("commands.panel.deploy.fail") + ": " + err.Error())
+			color.Greenln(hr)
+			return err
+		}
+		color.Greenln(hr)
+		color.Greenln("☆ " + translate.Get("commands.panel.deploy.success") + " [" + carbon.Now().ToDateTimeString() + "]")
+		color.Greenln(hr)
+
+	case "cert":
+		action := arg1
+		hr := `+----------------------------------------------------`
+		if (action != "obtain" && action != "renew") || cast.ToUint(arg2) == 0 {
+			color.Redln(translate.Get("commands.panel.cert.paramFail"))
+			return nil
+		}
+
+		color.Greenln(hr)
+		color.Greenln("★ " + translate.Get("commands.panel.cert."+action) + " [" + carbon.Now().ToDateTimeString() + "]")
+		color.Greenln(hr)
+		cert := services.NewCertImpl()
+		var err error
+		if action == "obtain" {
+			_, err = cert.ObtainAuto(cast.ToUint(arg2), os.Stdout)
+		} else {
+			_, err = cert.Renew(cast.ToUint(arg2), os.Stdout)
+		}
+		if err != nil {
+			color.Redln("|-" + translate.Get("commands.panel.cert.fail") + ": " + err.Error())
+			color.Greenln(hr)
+			return err
+		}
+		color.Greenln(hr)
+		color.Greenln("☆ " + translate.Get("commands.panel.cert.success") + " [" + carbon.Now().ToDateTimeString() + "]")
+		color.Greenln(hr)
+
 	case "installPlugin":
 		slug := arg1
 		if len(slug) == 0 {
@@ -699,8 +1011,15 @@ func (receiver *Panel) Handle(ctx console.Context) error {
 		color.Greenln("panel getEntrance " + translate.Get("commands.panel.getEntrance.description"))
 		color.Greenln("panel deleteEntrance " + translate.Get("commands.panel.deleteEntrance.description"))
 		color.Greenln("panel cleanTask " + translate.Get("commands.panel.cleanTask.description"))
-		color.Greenln("panel backup {website/mysql/postgresql} {name} {path} {save_copies} " + translate.Get("commands.panel.backup.description"))
+		color.Greenln("panel backup {website/mysql/postgresql/redis} {name} {path} {save_copies/policy} [storages] " + translate.Get("commands.panel.backup.description"))
+		color.Greenln("panel backupPrune {website/mysql/postgresql/redis} {name} {save_copies/policy} [dry-run] " + translate.Get("commands.panel.backupPrune.description"))
+		color.Greenln("panel backupVerify {id} / {website/mysql/postgresql/redis} {name} " + translate.Get("commands.panel.backupVerify.description"))
+		color.Greenln("panel snapshot {website_name} {save_copies/policy} " + translate.Get("commands.panel.snapshot.description"))
+		color.Greenln("panel backupDecrypt {file} [identity_file] " + translate.Get("commands.panel.backupDecrypt.description"))
 		color.Greenln("panel cutoff {website_name} {save_copies} " + translate.Get("commands.panel.cutoff.description"))
+		color.Greenln("panel websiteBatch {start/stop/backup/php/http_redirect/delete} {website_ids} {php} " + translate.Get("commands.panel.websiteBatch.description"))
+		color.Greenln("panel deploy {website_name} " + translate.Get("commands.panel.deploy.description"))
+		color.Greenln("panel cert {obtain/renew} {cert_id} " + translate.Get("commands.panel.cert.description"))
 		color.Greenln("panel installPlugin {slug} " + translate.Get("commands.panel.installPlugin.description"))
 		color.Greenln("panel uninstallPlugin {slug} " + translate.Get("commands.panel.uninstallPlugin.description"))
 		color.Greenln("panel updatePlugin {slug} " + translate.Get("commands.panel.updatePlugin.description"))
@@ -14,7 +14,7 @@ type Kernel struct {
 func (kernel *Kernel) Schedule() []schedule.Event {
 	return []schedule.Event{
 		facades.Schedule().Command("panel:monitoring").EveryMinute().SkipIfStillRunning(),
-		facades.Schedule().Command("panel:cert-renew").DailyAt("04:00").SkipIfStillRunning(),
+		facades.Schedule().Command("panel:cert-renew").Hourly().SkipIfStillRunning(),
 		facades.Schedule().Command("panel:task").Daily().SkipIfStillRunning(),
 	}
 }
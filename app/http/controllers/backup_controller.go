@@ -0,0 +1,431 @@
+package controllers
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+	"github.com/spf13/cast"
+
+	requests "panel/app/http/requests/backup"
+	commonrequests "panel/app/http/requests/common"
+	"panel/internal"
+	"panel/internal/services"
+	"panel/pkg/encryption"
+	"panel/pkg/retention"
+)
+
+type BackupController struct {
+	backup internal.Backup
+}
+
+func NewBackupController() *BackupController {
+	return &BackupController{
+		backup: services.NewBackupImpl(),
+	}
+}
+
+// StorageList
+//
+//	@Summary		获取远程存储列表
+//	@Description	获取备份使用的远程存储列表，密码和密钥显示为 ******
+//	@Tags			备份
+//	@Produce		json
+//	@Security		BearerToken
+//	@Success		200	{object}	SuccessResponse{data=[]models.BackupStorage}
+//	@Router			/panel/backup/storages [get]
+func (r *BackupController) StorageList(ctx http.Context) http.Response {
+	storages, err := r.backup.StorageList()
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "备份").With(map[string]any{
+			"error": err.Error(),
+		}).Info("获取远程存储列表失败")
+		return ErrorSystem(ctx)
+	}
+
+	return Success(ctx, storages)
+}
+
+// StorageShow
+//
+//	@Summary		获取远程存储
+//	@Description	获取远程存储的完整配置，包含密码和密钥
+//	@Tags			备份
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"存储 ID"
+//	@Success		200	{object}	SuccessResponse{data=models.BackupStorage}
+//	@Router			/panel/backup/storages/{id} [get]
+func (r *BackupController) StorageShow(ctx http.Context) http.Response {
+	var showAndDestroyRequest requests.StorageShowAndDestroy
+	sanitize := Sanitize(ctx, &showAndDestroyRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	backupStorage, err := r.backup.StorageShow(showAndDestroyRequest.ID)
+	if err != nil {
+		return ErrorSystem(ctx)
+	}
+
+	return Success(ctx, backupStorage)
+}
+
+// StorageStore
+//
+//	@Summary		添加远程存储
+//	@Description	添加备份使用的远程存储，保存前会检查存储是否可用
+//	@Tags			备份
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			data	body		requests.StorageStore	true	"request"
+//	@Success		200		{object}	SuccessResponse
+//	@Router			/panel/backup/storages [post]
+func (r *BackupController) StorageStore(ctx http.Context) http.Response {
+	var storeRequest requests.StorageStore
+	sanitize := Sanitize(ctx, &storeRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	if err := r.backup.StorageStore(storeRequest); err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "备份").With(map[string]any{
+			"error": err.Error(),
+		}).Info("添加远程存储失败")
+		return Error(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return Success(ctx, nil)
+}
+
+// StorageUpdate
+//
+//	@Summary		更新远程存储
+//	@Description	更新备份使用的远程存储，值为 ****** 的密码和密钥保持不变，保存前会检查存储是否可用
+//	@Tags			备份
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id		path		int						true	"存储 ID"
+//	@Param			data	body		requests.StorageUpdate	true	"request"
+//	@Success		200		{object}	SuccessResponse
+//	@Router			/panel/backup/storages/{id} [put]
+func (r *BackupController) StorageUpdate(ctx http.Context) http.Response {
+	var updateRequest requests.StorageUpdate
+	sanitize := Sanitize(ctx, &updateRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	if err := r.backup.StorageUpdate(updateRequest); err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "备份").With(map[string]any{
+			"storageID": updateRequest.ID,
+			"error":     err.Error(),
+		}).Info("更新远程存储失败")
+		return Error(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return Success(ctx, nil)
+}
+
+// StorageDestroy
+//
+//	@Summary		删除远程存储
+//	@Description	删除备份使用的远程存储，已上传的备份不会被删除
+//	@Tags			备份
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"存储 ID"
+//	@Success		200	{object}	SuccessResponse
+//	@Router			/panel/backup/storages/{id} [delete]
+func (r *BackupController) StorageDestroy(ctx http.Context) http.Response {
+	var showAndDestroyRequest requests.StorageShowAndDestroy
+	sanitize := Sanitize(ctx, &showAndDestroyRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	if err := r.backup.StorageDestroy(showAndDestroyRequest.ID); err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "备份").With(map[string]any{
+			"storageID": showAndDestroyRequest.ID,
+			"error":     err.Error(),
+		}).Info("删除远程存储失败")
+		return ErrorSystem(ctx)
+	}
+
+	return Success(ctx, nil)
+}
+
+// StorageHostKey
+//
+//	@Summary		获取主机公钥
+//	@Description	获取 SFTP 服务器的主机公钥和指纹，确认后保存到存储配置中用于校验服务器身份
+//	@Tags			备份
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			data	body		requests.StorageHostKey	true	"request"
+//	@Success		200		{object}	SuccessResponse{data=internal.StorageHostKey}
+//	@Router			/panel/backup/storages/hostKey [post]
+func (r *BackupController) StorageHostKey(ctx http.Context) http.Response {
+	var hostKeyRequest requests.StorageHostKey
+	sanitize := Sanitize(ctx, &hostKeyRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	hostKey, err := r.backup.StorageHostKey(hostKeyRequest)
+	if err != nil {
+		return Error(ctx, http.StatusUnprocessableEntity, "获取主机公钥失败: "+err.Error())
+	}
+
+	return Success(ctx, hostKey)
+}
+
+// BackupStorages 获取请求中选择的远程存储 ID，未传入时返回 nil 表示使用所有启用的存储
+func BackupStorages(ctx http.Context) []uint {
+	if _, ok := ctx.Request().All()["storages"]; !ok {
+		return nil
+	}
+
+	storages := []uint{}
+	for _, id := range ctx.Request().InputArray("storages") {
+		if cast.ToUint(id) > 0 {
+			storages = append(storages, cast.ToUint(id))
+		}
+	}
+
+	return storages
+}
+
+// Catalog
+//
+//	@Summary		获取备份记录列表
+//	@Description	获取备份记录，包括大小、SHA-256、耗时、所在的存储和校验状态
+//	@Tags			备份
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			data	query		requests.Catalog		true	"request"
+//	@Param			data	query		commonrequests.Paginate	true	"request"
+//	@Success		200		{object}	SuccessResponse{data=[]models.Backup}
+//	@Router			/panel/backup/catalog [get]
+func (r *BackupController) Catalog(ctx http.Context) http.Response {
+	var catalogRequest requests.Catalog
+	sanitize := Sanitize(ctx, &catalogRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+	var paginate commonrequests.Paginate
+	paginateSanitize := Sanitize(ctx, &paginate)
+	if paginateSanitize != nil {
+		return paginateSanitize
+	}
+
+	backups, total, err := r.backup.Catalog(catalogRequest.Type, catalogRequest.Source, paginate.Page, paginate.Limit)
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "备份").With(map[string]any{
+			"error": err.Error(),
+		}).Info("获取备份记录列表失败")
+		return ErrorSystem(ctx)
+	}
+
+	return Success(ctx, http.Json{
+		"total": total,
+		"items": backups,
+	})
+}
+
+// Verify
+//
+//	@Summary		校验备份
+//	@Description	创建后台任务，检查备份的 SHA-256 并恢复到临时目录或临时数据库，结果保存到备份记录
+//	@Tags			备份
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"备份记录 ID"
+//	@Success		200	{object}	SuccessResponse
+//	@Router			/panel/backup/catalog/{id}/verify [post]
+func (r *BackupController) Verify(ctx http.Context) http.Response {
+	var verifyRequest requests.Verify
+	sanitize := Sanitize(ctx, &verifyRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	if err := r.backup.VerifyTask(verifyRequest.ID); err != nil {
+		return Error(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return Success(ctx, nil)
+}
+
+// Prune
+//
+//	@Summary		按保留策略清理备份
+//	@Description	按保留最近 N 个以及按天、周、月保留的策略清理本地和远程存储中的备份，dry_run 时只返回将要删除的备份
+//	@Tags			备份
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			data	body		requests.Prune	true	"request"
+//	@Success		200		{object}	SuccessResponse{data=[]internal.BackupPrune}
+//	@Router			/panel/backup/prune [post]
+func (r *BackupController) Prune(ctx http.Context) http.Response {
+	var pruneRequest requests.Prune
+	sanitize := Sanitize(ctx, &pruneRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	policy := retention.Policy{
+		Last:    int(pruneRequest.Last),
+		Daily:   int(pruneRequest.Daily),
+		Weekly:  int(pruneRequest.Weekly),
+		Monthly: int(pruneRequest.Monthly),
+	}
+	pruned, err := r.backup.Prune(pruneRequest.Type, "", pruneRequest.Name, policy, pruneRequest.DryRun)
+	if err != nil {
+		return Error(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return Success(ctx, pruned)
+}
+
+// Encryption
+//
+//	@Summary		获取备份加密设置
+//	@Description	获取备份加密使用的密钥 ID，0 表示不加密
+//	@Tags			备份
+//	@Produce		json
+//	@Security		BearerToken
+//	@Success		200	{object}	SuccessResponse
+//	@Router			/panel/backup/encryption [get]
+func (r *BackupController) Encryption(ctx http.Context) http.Response {
+	return Success(ctx, http.Json{
+		"key_id": r.backup.Encryption(),
+	})
+}
+
+// UpdateEncryption
+//
+//	@Summary		更新备份加密设置
+//	@Description	设置新备份加密使用的密钥，0 表示不加密，已有备份不受影响
+//	@Tags			备份
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			data	body		requests.Encryption	true	"request"
+//	@Success		200		{object}	SuccessResponse
+//	@Router			/panel/backup/encryption [post]
+func (r *BackupController) UpdateEncryption(ctx http.Context) http.Response {
+	var encryptionRequest requests.Encryption
+	sanitize := Sanitize(ctx, &encryptionRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	if err := r.backup.UpdateEncryption(encryptionRequest.KeyID); err != nil {
+		return Error(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return Success(ctx, nil)
+}
+
+// KeyList
+//
+//	@Summary		获取加密密钥列表
+//	@Description	获取备份加密使用的密钥列表
+//	@Tags			备份
+//	@Produce		json
+//	@Security		BearerToken
+//	@Success		200	{object}	SuccessResponse{data=[]models.BackupKey}
+//	@Router			/panel/backup/keys [get]
+func (r *BackupController) KeyList(ctx http.Context) http.Response {
+	keys, err := r.backup.KeyList()
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "备份").With(map[string]any{
+			"error": err.Error(),
+		}).Info("获取加密密钥列表失败")
+		return ErrorSystem(ctx)
+	}
+
+	return Success(ctx, keys)
+}
+
+// KeyStore
+//
+//	@Summary		添加加密密钥
+//	@Description	添加备份加密使用的密码或 age 密钥，age 密钥未提供公钥和私钥时自动生成
+//	@Tags			备份
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			data	body		requests.KeyStore	true	"request"
+//	@Success		200		{object}	SuccessResponse{data=models.BackupKey}
+//	@Router			/panel/backup/keys [post]
+func (r *BackupController) KeyStore(ctx http.Context) http.Response {
+	var storeRequest requests.KeyStore
+	sanitize := Sanitize(ctx, &storeRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	key, err := r.backup.KeyStore(storeRequest)
+	if err != nil {
+		return Error(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return Success(ctx, key)
+}
+
+// KeyDestroy
+//
+//	@Summary		删除加密密钥
+//	@Description	删除备份加密使用的密钥，使用该密钥加密的备份需要自行保存的私钥或密码才能解密
+//	@Tags			备份
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"密钥 ID"
+//	@Success		200	{object}	SuccessResponse
+//	@Router			/panel/backup/keys/{id} [delete]
+func (r *BackupController) KeyDestroy(ctx http.Context) http.Response {
+	var showAndDestroyRequest requests.KeyShowAndDestroy
+	sanitize := Sanitize(ctx, &showAndDestroyRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	if err := r.backup.KeyDestroy(showAndDestroyRequest.ID); err != nil {
+		return Error(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return Success(ctx, nil)
+}
+
+// KeyIdentity
+//
+//	@Summary		下载私钥
+//	@Description	下载 age 密钥的私钥文件，用于在其他机器上解密备份
+//	@Tags			备份
+//	@Produce		plain
+//	@Security		BearerToken
+//	@Param			id	path	int	true	"密钥 ID"
+//	@Success		200
+//	@Router			/panel/backup/keys/{id}/identity [get]
+func (r *BackupController) KeyIdentity(ctx http.Context) http.Response {
+	var showAndDestroyRequest requests.KeyShowAndDestroy
+	sanitize := Sanitize(ctx, &showAndDestroyRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	key, err := r.backup.KeyShow(showAndDestroyRequest.ID)
+	if err != nil {
+		return ErrorSystem(ctx)
+	}
+	if key.Type != string(encryption.TypeX25519) || !key.HasIdentity {
+		return Error(ctx, http.StatusUnprocessableEntity, "该密钥没有可下载的私钥")
+	}
+
+	return ctx.Response().Header("Content-Disposition", "attachment; filename="+key.Fingerprint+".key").
+		Data(http.StatusOK, "text/plain", []byte("# public key: "+key.Recipient+"\n"+key.Identity+"\n"))
+}
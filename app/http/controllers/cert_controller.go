@@ -15,12 +15,14 @@ import (
 type CertController struct {
 	cron internal.Cron
 	cert internal.Cert
+	ca   internal.CertAuthority
 }
 
 func NewCertController() *CertController {
 	return &CertController{
 		cron: services.NewCronImpl(),
 		cert: services.NewCertImpl(),
+		ca:   services.NewCertAuthorityImpl(),
 	}
 }
 
@@ -81,6 +83,14 @@ func (r *CertController) DNSProviders(ctx http.Context) http.Response {
 			"name": "CloudFlare",
 			"dns":  acme.CloudFlare,
 		},
+		{
+			"name": "RFC 2136",
+			"dns":  acme.RFC2136,
+		},
+		{
+			"name": "自定义脚本",
+			"dns":  acme.Exec,
+		},
 	})
 }
 
@@ -266,6 +276,63 @@ func (r *CertController) UserDestroy(ctx http.Context) http.Response {
 	return Success(ctx, nil)
 }
 
+// UserDeactivate
+//
+//	@Summary		停用 ACME 用户
+//	@Description	在 CA 上停用 ACME 账号，停用后无法恢复，账号下的证书不再自动续签
+//	@Tags			证书管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"用户 ID"
+//	@Success		200	{object}	SuccessResponse
+//	@Router			/panel/cert/users/{id}/deactivate [post]
+func (r *CertController) UserDeactivate(ctx http.Context) http.Response {
+	var showAndDestroyRequest requests.UserShowAndDestroy
+	sanitize := Sanitize(ctx, &showAndDestroyRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	if err := r.cert.UserDeactivate(showAndDestroyRequest.ID); err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
+			"userID": showAndDestroyRequest.ID,
+			"error":  err.Error(),
+		}).Info("停用ACME用户失败")
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return Success(ctx, nil)
+}
+
+// UserAccount
+//
+//	@Summary		获取 ACME 账号信息
+//	@Description	获取 ACME 账号在 CA 上的状态以及账号下的证书和签发记录
+//	@Tags			证书管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"用户 ID"
+//	@Success		200	{object}	SuccessResponse{data=internal.CertAccount}
+//	@Router			/panel/cert/users/{id}/account [get]
+func (r *CertController) UserAccount(ctx http.Context) http.Response {
+	var showAndDestroyRequest requests.UserShowAndDestroy
+	sanitize := Sanitize(ctx, &showAndDestroyRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	account, err := r.cert.UserAccount(showAndDestroyRequest.ID)
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
+			"userID": showAndDestroyRequest.ID,
+			"error":  err.Error(),
+		}).Info("获取ACME账号信息失败")
+		return ErrorSystem(ctx)
+	}
+
+	return Success(ctx, account)
+}
+
 // DNSList
 //
 //	@Summary		获取 DNS 接口列表
@@ -437,7 +504,7 @@ func (r *CertController) CertList(ctx http.Context) http.Response {
 
 	var certs []models.Cert
 	var total int64
-	err := facades.Orm().Query().With("Website").With("User").With("DNS").Paginate(paginateRequest.Page, paginateRequest.Limit, &certs, &total)
+	err := facades.Orm().Query().With("Website").With("User").With("DNS").With("CA").With("Task").With("Deployments.Website").Paginate(paginateRequest.Page, paginateRequest.Limit, &certs, &total)
 	if err != nil {
 		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
 			"error": err.Error(),
@@ -570,16 +637,134 @@ func (r *CertController) CertDestroy(ctx http.Context) http.Response {
 	return Success(ctx, nil)
 }
 
+// CSRStore
+//
+//	@Summary		生成 CSR
+//	@Description	为商业证书生成私钥和 CSR，CA 签发后通过导入接口上传证书
+//	@Tags			证书管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			data	body		requests.CSRStore	true	"request"
+//	@Success		200		{object}	SuccessResponse{data=models.Cert}
+//	@Router			/panel/cert/csr [post]
+func (r *CertController) CSRStore(ctx http.Context) http.Response {
+	var storeRequest requests.CSRStore
+	sanitize := Sanitize(ctx, &storeRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	cert, err := r.cert.CSRStore(storeRequest)
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
+			"domains": storeRequest.Domains,
+			"error":   err.Error(),
+		}).Info("生成 CSR 失败")
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return Success(ctx, cert)
+}
+
+// CSR
+//
+//	@Summary		下载 CSR
+//	@Description	下载证书的 CSR，用于提交给 CA 签发证书
+//	@Tags			证书管理
+//	@Produce		application/pkcs10
+//	@Security		BearerToken
+//	@Param			id	path	int	true	"证书 ID"
+//	@Router			/panel/cert/certs/{id}/csr [get]
+func (r *CertController) CSR(ctx http.Context) http.Response {
+	var showAndDestroyRequest requests.CertShowAndDestroy
+	sanitize := Sanitize(ctx, &showAndDestroyRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	cert, err := r.cert.CertShow(showAndDestroyRequest.ID)
+	if err != nil {
+		return ErrorSystem(ctx)
+	}
+	if len(cert.CSR) == 0 {
+		return Error(ctx, http.StatusUnprocessableEntity, "该证书没有 CSR")
+	}
+
+	return ctx.Response().Header("Content-Disposition", "attachment; filename="+cert.Domains[0]+".csr").Data(http.StatusOK, "application/pkcs10", []byte(cert.CSR))
+}
+
+// Import
+//
+//	@Summary		导入证书
+//	@Description	导入 CA 根据 CSR 签发的证书和证书链，校验与私钥匹配后部署到关联的网站和目标
+//	@Tags			证书管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id		path		int					true	"证书 ID"
+//	@Param			data	body		requests.CertImport	true	"request"
+//	@Success		200		{object}	SuccessResponse
+//	@Router			/panel/cert/certs/{id}/import [post]
+func (r *CertController) Import(ctx http.Context) http.Response {
+	var importRequest requests.CertImport
+	sanitize := Sanitize(ctx, &importRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	if err := r.cert.Import(importRequest); err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
+			"certID": importRequest.ID,
+			"error":  err.Error(),
+		}).Info("导入证书失败")
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return Success(ctx, nil)
+}
+
+// Download
+//
+//	@Summary		下载证书
+//	@Description	按格式导出证书，支持 PEM 证书链、站点证书、中间证书、私钥、DER、PFX 和 zip 压缩包；使用 POST 避免 PFX 密码出现在访问日志中
+//	@Tags			证书管理
+//	@Accept			json
+//	@Produce		application/octet-stream
+//	@Security		BearerToken
+//	@Param			id		path	int						true	"证书 ID"
+//	@Param			data	body	requests.CertDownload	true	"request"
+//	@Router			/panel/cert/certs/{id}/download [post]
+func (r *CertController) Download(ctx http.Context) http.Response {
+	var downloadRequest requests.CertDownload
+	sanitize := Sanitize(ctx, &downloadRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	name, exported, err := r.cert.Download(downloadRequest)
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
+			"certID": downloadRequest.ID,
+			"format": downloadRequest.Format,
+			"error":  err.Error(),
+		}).Info("导出证书失败")
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return ctx.Response().Header("Content-Disposition", "attachment; filename="+name).Data(http.StatusOK, exported.ContentType, exported.Data)
+}
+
 // Obtain
 //
 //	@Summary		签发证书
-//	@Description	签发面板证书管理的证书
+//	@Description	签发面板证书管理的证书，自动签发时创建后台任务并返回任务
 //	@Tags			证书管理
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerToken
 //	@Param			data	body		requests.Obtain	true	"request"
-//	@Success		200		{object}	SuccessResponse
+//	@Success		200		{object}	SuccessResponse{data=models.Task}
 //	@Router			/panel/cert/obtain [post]
 func (r *CertController) Obtain(ctx http.Context) http.Response {
 	var obtainRequest requests.Obtain
@@ -597,12 +782,21 @@ func (r *CertController) Obtain(ctx http.Context) http.Response {
 		return ErrorSystem(ctx)
 	}
 
-	if cert.DNS != nil || cert.Website != nil {
-		_, err = r.cert.ObtainAuto(obtainRequest.ID)
-	} else {
-		_, err = r.cert.ObtainManual(obtainRequest.ID)
+	// 自动签发作为后台任务运行，手动 DNS 验证需要在当前进程中完成
+	// 面板证书没有关联网站，使用 HTTP 验证自动签发
+	if cert.DNS != nil || cert.Website != nil || r.cert.IsPanelCert(cert) {
+		task, err := r.cert.ObtainTask(obtainRequest.ID)
+		if err != nil {
+			facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
+				"error": err.Error(),
+			}).Info("创建签发任务失败")
+			return Error(ctx, http.StatusInternalServerError, err.Error())
+		}
+
+		return Success(ctx, task)
 	}
-	if err != nil {
+
+	if _, err = r.cert.ObtainManual(obtainRequest.ID); err != nil {
 		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
 			"error": err.Error(),
 		}).Info("签发证书失败")
@@ -615,13 +809,13 @@ func (r *CertController) Obtain(ctx http.Context) http.Response {
 // Renew
 //
 //	@Summary		续签证书
-//	@Description	续签面板证书管理的证书
+//	@Description	创建续签面板证书管理的证书的后台任务
 //	@Tags			证书管理
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerToken
 //	@Param			data	body		requests.Renew	true	"request"
-//	@Success		200		{object}	SuccessResponse
+//	@Success		200		{object}	SuccessResponse{data=models.Task}
 //	@Router			/panel/cert/renew [post]
 func (r *CertController) Renew(ctx http.Context) http.Response {
 	var renewRequest requests.Renew
@@ -630,15 +824,15 @@ func (r *CertController) Renew(ctx http.Context) http.Response {
 		return sanitize
 	}
 
-	_, err := r.cert.Renew(renewRequest.ID)
+	task, err := r.cert.RenewTask(renewRequest.ID)
 	if err != nil {
 		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
 			"error": err.Error(),
-		}).Info("续签证书失败")
+		}).Info("创建续签任务失败")
 		return Error(ctx, http.StatusInternalServerError, err.Error())
 	}
 
-	return Success(ctx, nil)
+	return Success(ctx, task)
 }
 
 // ManualDNS
@@ -673,7 +867,7 @@ func (r *CertController) ManualDNS(ctx http.Context) http.Response {
 // Deploy
 //
 //	@Summary		部署证书
-//	@Description	部署面板证书管理的证书
+//	@Description	部署证书到网站，证书签发和续签后会自动重新部署到所有已部署的网站
 //	@Tags			证书管理
 //	@Accept			json
 //	@Produce		json
@@ -699,3 +893,492 @@ func (r *CertController) Deploy(ctx http.Context) http.Response {
 
 	return Success(ctx, nil)
 }
+
+// Undeploy
+//
+//	@Summary		取消部署证书
+//	@Description	取消证书与网站的关联，续签后不再自动部署到该网站
+//	@Tags			证书管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id			path		int	true	"证书 ID"
+//	@Param			website_id	path		int	true	"网站 ID"
+//	@Success		200			{object}	SuccessResponse
+//	@Router			/panel/cert/certs/{id}/deployments/{website_id} [delete]
+func (r *CertController) Undeploy(ctx http.Context) http.Response {
+	var deployRequest requests.CertDeploy
+	sanitize := Sanitize(ctx, &deployRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	if err := r.cert.Undeploy(deployRequest.ID, deployRequest.WebsiteID); err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
+			"certID":    deployRequest.ID,
+			"websiteID": deployRequest.WebsiteID,
+			"error":     err.Error(),
+		}).Info("取消部署证书失败")
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return Success(ctx, nil)
+}
+
+// Deployments
+//
+//	@Summary		获取证书部署情况
+//	@Description	获取证书部署到的网站，以及网站使用的是否为最新证书
+//	@Tags			证书管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"证书 ID"
+//	@Success		200	{object}	SuccessResponse{data=[]internal.CertDeploymentStatus}
+//	@Router			/panel/cert/certs/{id}/deployments [get]
+func (r *CertController) Deployments(ctx http.Context) http.Response {
+	var showAndDestroyRequest requests.CertShowAndDestroy
+	sanitize := Sanitize(ctx, &showAndDestroyRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	deployments, err := r.cert.Deployments(showAndDestroyRequest.ID)
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
+			"certID": showAndDestroyRequest.ID,
+			"error":  err.Error(),
+		}).Info("获取证书部署情况失败")
+		return ErrorSystem(ctx)
+	}
+
+	return Success(ctx, deployments)
+}
+
+// TargetList
+//
+//	@Summary		获取部署目标
+//	@Description	获取证书的文件部署目标及最近一次部署结果
+//	@Tags			证书管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"证书 ID"
+//	@Success		200	{object}	SuccessResponse{data=[]models.CertTarget}
+//	@Router			/panel/cert/certs/{id}/targets [get]
+func (r *CertController) TargetList(ctx http.Context) http.Response {
+	var showAndDestroyRequest requests.CertShowAndDestroy
+	sanitize := Sanitize(ctx, &showAndDestroyRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	targets, err := r.cert.TargetList(showAndDestroyRequest.ID)
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
+			"certID": showAndDestroyRequest.ID,
+			"error":  err.Error(),
+		}).Info("获取部署目标失败")
+		return ErrorSystem(ctx)
+	}
+
+	return Success(ctx, targets)
+}
+
+// TargetStore
+//
+//	@Summary		添加部署目标
+//	@Description	添加证书的文件部署目标，证书签发和续签后会自动部署
+//	@Tags			证书管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id		path		int						true	"证书 ID"
+//	@Param			data	body		requests.TargetStore	true	"request"
+//	@Success		200		{object}	SuccessResponse
+//	@Router			/panel/cert/certs/{id}/targets [post]
+func (r *CertController) TargetStore(ctx http.Context) http.Response {
+	var storeRequest requests.TargetStore
+	sanitize := Sanitize(ctx, &storeRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	if err := r.cert.TargetStore(storeRequest); err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
+			"certID": storeRequest.ID,
+			"error":  err.Error(),
+		}).Info("添加部署目标失败")
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return Success(ctx, nil)
+}
+
+// TargetUpdate
+//
+//	@Summary		更新部署目标
+//	@Description	更新证书的文件部署目标
+//	@Tags			证书管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id		path		int						true	"部署目标 ID"
+//	@Param			data	body		requests.TargetUpdate	true	"request"
+//	@Success		200		{object}	SuccessResponse
+//	@Router			/panel/cert/targets/{id} [put]
+func (r *CertController) TargetUpdate(ctx http.Context) http.Response {
+	var updateRequest requests.TargetUpdate
+	sanitize := Sanitize(ctx, &updateRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	if err := r.cert.TargetUpdate(updateRequest); err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
+			"targetID": updateRequest.ID,
+			"error":    err.Error(),
+		}).Info("更新部署目标失败")
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return Success(ctx, nil)
+}
+
+// TargetDestroy
+//
+//	@Summary		删除部署目标
+//	@Description	删除证书的文件部署目标，不会删除已写入的文件
+//	@Tags			证书管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"部署目标 ID"
+//	@Success		200	{object}	SuccessResponse
+//	@Router			/panel/cert/targets/{id} [delete]
+func (r *CertController) TargetDestroy(ctx http.Context) http.Response {
+	var showAndDestroyRequest requests.TargetShowAndDestroy
+	sanitize := Sanitize(ctx, &showAndDestroyRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	if err := r.cert.TargetDestroy(showAndDestroyRequest.ID); err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
+			"targetID": showAndDestroyRequest.ID,
+			"error":    err.Error(),
+		}).Info("删除部署目标失败")
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return Success(ctx, nil)
+}
+
+// TargetDeploy
+//
+//	@Summary		部署到目标
+//	@Description	立即将证书部署到文件部署目标
+//	@Tags			证书管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"部署目标 ID"
+//	@Success		200	{object}	SuccessResponse
+//	@Router			/panel/cert/targets/{id}/deploy [post]
+func (r *CertController) TargetDeploy(ctx http.Context) http.Response {
+	var showAndDestroyRequest requests.TargetShowAndDestroy
+	sanitize := Sanitize(ctx, &showAndDestroyRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	if err := r.cert.TargetDeploy(showAndDestroyRequest.ID); err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
+			"targetID": showAndDestroyRequest.ID,
+			"error":    err.Error(),
+		}).Info("部署证书到目标失败")
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return Success(ctx, nil)
+}
+
+// CAList
+//
+//	@Summary		获取私有 CA 列表
+//	@Description	获取面板私有 CA 列表
+//	@Tags			证书管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			data	query		commonrequests.Paginate	true	"request"
+//	@Success		200		{object}	SuccessResponse
+//	@Router			/panel/cert/cas [get]
+func (r *CertController) CAList(ctx http.Context) http.Response {
+	var paginateRequest commonrequests.Paginate
+	sanitize := Sanitize(ctx, &paginateRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	var authorities []models.CertAuthority
+	var total int64
+	err := facades.Orm().Query().Paginate(paginateRequest.Page, paginateRequest.Limit, &authorities, &total)
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
+			"error": err.Error(),
+		}).Info("获取私有 CA 列表失败")
+		return ErrorSystem(ctx)
+	}
+
+	return Success(ctx, http.Json{
+		"total": total,
+		"items": authorities,
+	})
+}
+
+// CAStore
+//
+//	@Summary		创建私有 CA
+//	@Description	生成私有 CA 的根证书和中间证书，私钥加密存储
+//	@Tags			证书管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			data	body		requests.CAStore	true	"request"
+//	@Success		200		{object}	SuccessResponse{data=models.CertAuthority}
+//	@Router			/panel/cert/cas [post]
+func (r *CertController) CAStore(ctx http.Context) http.Response {
+	var storeRequest requests.CAStore
+	sanitize := Sanitize(ctx, &storeRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	authority, err := r.ca.Store(storeRequest)
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
+			"error": err.Error(),
+		}).Info("创建私有 CA 失败")
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return Success(ctx, authority)
+}
+
+// CADestroy
+//
+//	@Summary		删除私有 CA
+//	@Description	删除没有签发证书的私有 CA
+//	@Tags			证书管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"CA ID"
+//	@Success		200	{object}	SuccessResponse
+//	@Router			/panel/cert/cas/{id} [delete]
+func (r *CertController) CADestroy(ctx http.Context) http.Response {
+	var showAndDestroyRequest requests.CAShowAndDestroy
+	sanitize := Sanitize(ctx, &showAndDestroyRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	if err := r.ca.Destroy(showAndDestroyRequest.ID); err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
+			"caID":  showAndDestroyRequest.ID,
+			"error": err.Error(),
+		}).Info("删除私有 CA 失败")
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return Success(ctx, nil)
+}
+
+// CAIssue
+//
+//	@Summary		私有 CA 签发证书
+//	@Description	使用私有 CA 签发服务端或客户端证书，签发的证书加入证书列表
+//	@Tags			证书管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id		path		int					true	"CA ID"
+//	@Param			data	body		requests.CAIssue	true	"request"
+//	@Success		200		{object}	SuccessResponse{data=models.Cert}
+//	@Router			/panel/cert/cas/{id}/issue [post]
+func (r *CertController) CAIssue(ctx http.Context) http.Response {
+	var issueRequest requests.CAIssue
+	sanitize := Sanitize(ctx, &issueRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	cert, err := r.ca.Issue(issueRequest)
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
+			"caID":  issueRequest.ID,
+			"error": err.Error(),
+		}).Info("私有 CA 签发证书失败")
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return Success(ctx, cert)
+}
+
+// CARoot
+//
+//	@Summary		下载根证书
+//	@Description	下载私有 CA 的根证书，用于导入客户端信任，无需登录
+//	@Tags			证书管理
+//	@Produce		application/x-pem-file
+//	@Param			id	path	int	true	"CA ID"
+//	@Router			/panel/cert/cas/{id}/root [get]
+func (r *CertController) CARoot(ctx http.Context) http.Response {
+	var showAndDestroyRequest requests.CAShowAndDestroy
+	sanitize := Sanitize(ctx, &showAndDestroyRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	root, err := r.ca.Root(showAndDestroyRequest.ID)
+	if err != nil {
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return ctx.Response().Header("Content-Disposition", "attachment; filename=root-ca.crt").Data(http.StatusOK, "application/x-pem-file", root)
+}
+
+// CACRL
+//
+//	@Summary		下载吊销列表
+//	@Description	下载 DER 格式的私有 CA 吊销列表，无需登录，可直接作为签发证书中的 CRL 分发地址
+//	@Tags			证书管理
+//	@Produce		application/pkix-crl
+//	@Param			id	path	int	true	"CA ID"
+//	@Router			/panel/cert/cas/{id}/crl [get]
+func (r *CertController) CACRL(ctx http.Context) http.Response {
+	var showAndDestroyRequest requests.CAShowAndDestroy
+	sanitize := Sanitize(ctx, &showAndDestroyRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	crl, err := r.ca.CRL(showAndDestroyRequest.ID)
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
+			"caID":  showAndDestroyRequest.ID,
+			"error": err.Error(),
+		}).Info("生成吊销列表失败")
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return ctx.Response().Header("Content-Disposition", "attachment; filename=ca.crl").Data(http.StatusOK, "application/pkix-crl", crl)
+}
+
+// CACRLPEM
+//
+//	@Summary		下载 PEM 格式吊销列表
+//	@Description	下载 PEM 格式的私有 CA 吊销列表，用于导入到需要 PEM 格式的服务
+//	@Tags			证书管理
+//	@Produce		application/x-pem-file
+//	@Security		BearerToken
+//	@Param			id	path	int	true	"CA ID"
+//	@Router			/panel/cert/cas/{id}/crl/pem [get]
+func (r *CertController) CACRLPEM(ctx http.Context) http.Response {
+	var showAndDestroyRequest requests.CAShowAndDestroy
+	sanitize := Sanitize(ctx, &showAndDestroyRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	crl, err := r.ca.CRLPEM(showAndDestroyRequest.ID)
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
+			"caID":  showAndDestroyRequest.ID,
+			"error": err.Error(),
+		}).Info("生成吊销列表失败")
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return ctx.Response().Header("Content-Disposition", "attachment; filename=ca.crl.pem").Data(http.StatusOK, "application/x-pem-file", crl)
+}
+
+// Revoke
+//
+//	@Summary		吊销证书
+//	@Description	吊销私有 CA 签发的证书并更新 CRL
+//	@Tags			证书管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"证书 ID"
+//	@Success		200	{object}	SuccessResponse
+//	@Router			/panel/cert/certs/{id}/revoke [post]
+func (r *CertController) Revoke(ctx http.Context) http.Response {
+	var showAndDestroyRequest requests.CertShowAndDestroy
+	sanitize := Sanitize(ctx, &showAndDestroyRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	if err := r.ca.Revoke(showAndDestroyRequest.ID); err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
+			"certID": showAndDestroyRequest.ID,
+			"error":  err.Error(),
+		}).Info("吊销证书失败")
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return Success(ctx, nil)
+}
+
+// Inspect
+//
+//	@Summary		检查证书
+//	@Description	检查证书链顺序和完整性、私钥匹配、密钥算法、域名覆盖和 OCSP 状态
+//	@Tags			证书管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"证书 ID"
+//	@Success		200	{object}	SuccessResponse{data=acme.Inspection}
+//	@Router			/panel/cert/certs/{id}/inspect [get]
+func (r *CertController) Inspect(ctx http.Context) http.Response {
+	var showAndDestroyRequest requests.CertShowAndDestroy
+	sanitize := Sanitize(ctx, &showAndDestroyRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	inspection, err := r.cert.Inspect(showAndDestroyRequest.ID)
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
+			"certID": showAndDestroyRequest.ID,
+			"error":  err.Error(),
+		}).Info("检查证书失败")
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return Success(ctx, inspection)
+}
+
+// TaskStatus
+//
+//	@Summary		获取签发任务状态
+//	@Description	获取证书最近一次签发或续签任务的状态、步骤日志和失败原因
+//	@Tags			证书管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"证书 ID"
+//	@Success		200	{object}	SuccessResponse{data=internal.CertTaskStatus}
+//	@Router			/panel/cert/certs/{id}/task [get]
+func (r *CertController) TaskStatus(ctx http.Context) http.Response {
+	var showAndDestroyRequest requests.CertShowAndDestroy
+	sanitize := Sanitize(ctx, &showAndDestroyRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	status, err := r.cert.TaskStatus(showAndDestroyRequest.ID)
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "证书管理").With(map[string]any{
+			"certID": showAndDestroyRequest.ID,
+			"error":  err.Error(),
+		}).Info("获取签发任务状态失败")
+		return ErrorSystem(ctx)
+	}
+
+	return Success(ctx, status)
+}
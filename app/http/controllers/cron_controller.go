@@ -3,6 +3,7 @@ package controllers
 import (
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/goravel/framework/contracts/http"
 	"github.com/goravel/framework/facades"
@@ -12,6 +13,7 @@ import (
 	"panel/app/models"
 	"panel/internal"
 	"panel/internal/services"
+	"panel/pkg/retention"
 	"panel/pkg/tools"
 )
 
@@ -55,7 +57,7 @@ func (r *CronController) Add(ctx http.Context) http.Response {
 		"time":        "required",
 		"script":      "required",
 		"type":        "required|in:shell,backup,cutoff",
-		"backup_type": "required_if:type,backup|in:website,mysql,postgresql",
+		"backup_type": "required_if:type,backup|in:website,website_snapshot,mysql,postgresql,redis",
 	})
 	if err != nil {
 		return Error(ctx, http.StatusUnprocessableEntity, err.Error())
@@ -71,17 +73,56 @@ func (r *CronController) Add(ctx http.Context) http.Response {
 
 	shell := ctx.Request().Input("script")
 	cronType := ctx.Request().Input("type")
-	if cronType == "backup" {
+	if cronType == "backup" && ctx.Request().Input("backup_type") == "website_snapshot" {
+		website := ctx.Request().Input("website")
+		save, err := r.backupPolicy(ctx)
+		if err != nil {
+			return Error(ctx, http.StatusUnprocessableEntity, err.Error())
+		}
+		shell = `#!/bin/bash
+export PATH=/bin:/sbin:/usr/bin:/usr/sbin:/usr/local/bin:/usr/local/sbin:$PATH
+
+# 耗子 Linux 面板 - 网站快照脚本
+
+name=` + website + `
+save=` + save + `
+
+# 执行快照
+panel snapshot ${name} ${save} 2>&1
+`
+	} else if cronType == "backup" {
 		backupType := ctx.Request().Input("backup_type")
 		backupName := ctx.Request().Input("database")
 		if backupType == "website" {
 			backupName = ctx.Request().Input("website")
 		}
+		if backupType == "redis" {
+			backupName = "redis"
+		}
 		backupPath := ctx.Request().Input("backup_path")
 		if len(backupPath) == 0 {
 			backupPath = r.setting.Get(models.SettingKeyBackupPath) + "/" + backupType
 		}
-		backupSave := ctx.Request().InputInt("save", 10)
+		backupSave, err := r.backupPolicy(ctx)
+		if err != nil {
+			return Error(ctx, http.StatusUnprocessableEntity, err.Error())
+		}
+		backupStorages := ""
+		if storages := BackupStorages(ctx); storages != nil {
+			ids := make([]string, len(storages))
+			for i, id := range storages {
+				ids[i] = cast.ToString(id)
+			}
+			backupStorages = strings.Join(ids, ",")
+			if len(ids) == 0 {
+				backupStorages = "local"
+			}
+		}
+		backupCommand := `panel backup ${type} ${name} ${path} ${save} ${storages} 2>&1`
+		if ctx.Request().InputBool("verify") {
+			// 备份失败时不再校验
+			backupCommand += ` && panel backupVerify ${type} ${name} 2>&1`
+		}
 		shell = `#!/bin/bash
 export PATH=/bin:/sbin:/usr/bin:/usr/sbin:/usr/local/bin:/usr/local/sbin:$PATH
 
@@ -90,10 +131,11 @@ export PATH=/bin:/sbin:/usr/bin:/usr/sbin:/usr/local/bin:/usr/local/sbin:$PATH
 type=` + backupType + `
 path=` + backupPath + `
 name=` + backupName + `
-save=` + cast.ToString(backupSave) + `
+save=` + backupSave + `
+storages=` + backupStorages + `
 
 # 执行备份
-panel backup ${type} ${name} ${path} ${save} 2>&1
+` + backupCommand + `
 `
 	}
 	if cronType == "cutoff" {
@@ -308,3 +350,15 @@ func (r *CronController) Log(ctx http.Context) http.Response {
 
 	return Success(ctx, log)
 }
+
+// backupPolicy 根据保留数量和按天、周、月保留的数量生成备份的保留策略
+func (r *CronController) backupPolicy(ctx http.Context) (string, error) {
+	policy := retention.Policy{
+		Last:    ctx.Request().InputInt("save", 10),
+		Daily:   ctx.Request().InputInt("keep_daily", 0),
+		Weekly:  ctx.Request().InputInt("keep_weekly", 0),
+		Monthly: ctx.Request().InputInt("keep_monthly", 0),
+	}
+
+	return policy.String(), policy.Validate()
+}
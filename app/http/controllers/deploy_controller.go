@@ -0,0 +1,205 @@
+package controllers
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+
+	requests "panel/app/http/requests/website"
+	"panel/internal"
+	"panel/internal/services"
+)
+
+type DeployController struct {
+	deploy internal.Deploy
+}
+
+func NewDeployController() *DeployController {
+	return &DeployController{
+		deploy: services.NewDeployImpl(),
+	}
+}
+
+// GetConfig
+//
+//	@Summary		获取部署配置
+//	@Description	获取网站的 Git 部署配置
+//	@Tags			网站管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"网站 ID"
+//	@Success		200	{object}	SuccessResponse{data=models.WebsiteDeploy}
+//	@Router			/panel/websites/{id}/deploy [get]
+func (r *DeployController) GetConfig(ctx http.Context) http.Response {
+	var idRequest requests.ID
+	sanitize := Sanitize(ctx, &idRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	deploy, err := r.deploy.GetConfig(idRequest.ID)
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "网站部署").With(map[string]any{
+			"id":    idRequest.ID,
+			"error": err.Error(),
+		}).Info("获取部署配置失败")
+		return ErrorSystem(ctx)
+	}
+
+	return Success(ctx, deploy)
+}
+
+// SaveConfig
+//
+//	@Summary		保存部署配置
+//	@Description	保存网站的 Git 部署配置
+//	@Tags			网站管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id		path		int						true	"网站 ID"
+//	@Param			data	body		requests.DeployConfig	true	"request"
+//	@Success		200		{object}	SuccessResponse
+//	@Router			/panel/websites/{id}/deploy [post]
+func (r *DeployController) SaveConfig(ctx http.Context) http.Response {
+	var configRequest requests.DeployConfig
+	sanitize := Sanitize(ctx, &configRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	if err := r.deploy.SaveConfig(configRequest); err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "网站部署").With(map[string]any{
+			"id":    configRequest.ID,
+			"error": err.Error(),
+		}).Info("保存部署配置失败")
+		return ErrorSystem(ctx)
+	}
+
+	return Success(ctx, nil)
+}
+
+// ResetToken
+//
+//	@Summary		重置 Webhook 密钥
+//	@Description	重置网站部署的 Webhook 密钥
+//	@Tags			网站管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"网站 ID"
+//	@Success		200	{object}	SuccessResponse{data=string}
+//	@Router			/panel/websites/{id}/deploy/token [post]
+func (r *DeployController) ResetToken(ctx http.Context) http.Response {
+	var idRequest requests.ID
+	sanitize := Sanitize(ctx, &idRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	token, err := r.deploy.ResetToken(idRequest.ID)
+	if err != nil {
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return Success(ctx, token)
+}
+
+// Trigger
+//
+//	@Summary		部署网站
+//	@Description	创建网站的部署任务
+//	@Tags			网站管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"网站 ID"
+//	@Success		200	{object}	SuccessResponse
+//	@Router			/panel/websites/{id}/deploy/trigger [post]
+func (r *DeployController) Trigger(ctx http.Context) http.Response {
+	var idRequest requests.ID
+	sanitize := Sanitize(ctx, &idRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	if err := r.deploy.Trigger(idRequest.ID); err != nil {
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return Success(ctx, nil)
+}
+
+// Releases
+//
+//	@Summary		获取部署版本
+//	@Description	获取网站的部署版本列表
+//	@Tags			网站管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"网站 ID"
+//	@Success		200	{object}	SuccessResponse{data=[]internal.DeployRelease}
+//	@Router			/panel/websites/{id}/deploy/releases [get]
+func (r *DeployController) Releases(ctx http.Context) http.Response {
+	var idRequest requests.ID
+	sanitize := Sanitize(ctx, &idRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	releases, err := r.deploy.Releases(idRequest.ID)
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "网站部署").With(map[string]any{
+			"id":    idRequest.ID,
+			"error": err.Error(),
+		}).Info("获取部署版本失败")
+		return ErrorSystem(ctx)
+	}
+
+	return Success(ctx, releases)
+}
+
+// Rollback
+//
+//	@Summary		回滚版本
+//	@Description	将网站回滚到指定的部署版本
+//	@Tags			网站管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id		path		int						true	"网站 ID"
+//	@Param			data	body		requests.DeployRollback	true	"request"
+//	@Success		200		{object}	SuccessResponse
+//	@Router			/panel/websites/{id}/deploy/rollback [post]
+func (r *DeployController) Rollback(ctx http.Context) http.Response {
+	var rollbackRequest requests.DeployRollback
+	sanitize := Sanitize(ctx, &rollbackRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	if err := r.deploy.Rollback(rollbackRequest.ID, rollbackRequest.Release); err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "网站部署").With(map[string]any{
+			"id":      rollbackRequest.ID,
+			"release": rollbackRequest.Release,
+			"error":   err.Error(),
+		}).Info("回滚版本失败")
+		return Error(ctx, http.StatusInternalServerError, "回滚版本失败: "+err.Error())
+	}
+
+	return Success(ctx, nil)
+}
+
+// Webhook
+//
+//	@Summary		Webhook 部署
+//	@Description	通过 Webhook 密钥触发网站部署
+//	@Tags			网站管理
+//	@Produce		json
+//	@Param			token	path		string	true	"Webhook 密钥"
+//	@Success		200		{object}	SuccessResponse
+//	@Router			/panel/webhook/deploy/{token} [post]
+func (r *DeployController) Webhook(ctx http.Context) http.Response {
+	if err := r.deploy.TriggerByToken(ctx.Request().Route("token")); err != nil {
+		return Error(ctx, http.StatusNotFound, err.Error())
+	}
+
+	return Success(ctx, nil)
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"context"
+	nethttp "net/http"
+	"time"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+	"github.com/gorilla/websocket"
+
+	requests "panel/app/http/requests/log"
+	"panel/internal"
+	"panel/internal/services"
+	"panel/pkg/logreader"
+)
+
+type LogController struct {
+	log internal.Log
+}
+
+func NewLogController() *LogController {
+	return &LogController{
+		log: services.NewLogImpl(),
+	}
+}
+
+// Sources
+//
+//	@Summary		获取日志来源
+//	@Description	获取可查看的网站、服务和面板日志列表
+//	@Tags			日志
+//	@Produce		json
+//	@Security		BearerToken
+//	@Success		200	{object}	SuccessResponse{data=[]internal.LogSource}
+//	@Router			/panel/log/sources [get]
+func (r *LogController) Sources(ctx http.Context) http.Response {
+	sources, err := r.log.Sources()
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "日志").With(map[string]any{
+			"error": err.Error(),
+		}).Info("获取日志来源失败")
+		return ErrorSystem(ctx)
+	}
+
+	return Success(ctx, sources)
+}
+
+// Read
+//
+//	@Summary		读取日志
+//	@Description	从日志末尾向前分页读取，支持关键字、正则和时间范围过滤
+//	@Tags			日志
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			data	query		requests.Read	true	"request"
+//	@Success		200		{object}	SuccessResponse{data=logreader.Page}
+//	@Router			/panel/log/read [get]
+func (r *LogController) Read(ctx http.Context) http.Response {
+	var readRequest requests.Read
+	sanitize := Sanitize(ctx, &readRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	page, err := r.log.Read(readRequest)
+	if err != nil {
+		return Error(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return Success(ctx, page)
+}
+
+// Stream
+//
+//	@Summary		实时日志
+//	@Description	通过 WebSocket 推送日志新写入的行，offset 为读取日志时返回的 size
+//	@Tags			日志
+//	@Security		BearerToken
+//	@Param			data	query	requests.Stream	true	"request"
+//	@Router			/panel/log/stream [get]
+func (r *LogController) Stream(ctx http.Context) http.Response {
+	var streamRequest requests.Stream
+	sanitize := Sanitize(ctx, &streamRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	upGrader := websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 4096,
+		CheckOrigin: func(r *nethttp.Request) bool {
+			return true
+		},
+		Subprotocols: []string{ctx.Request().Header("Sec-WebSocket-Protocol")},
+	}
+
+	ws, err := upGrader.Upgrade(ctx.Response().Writer(), ctx.Request().Origin(), nil)
+	if err != nil {
+		facades.Log().Tags("面板", "日志").With(map[string]any{
+			"error": err.Error(),
+		}).Infof("建立连接失败")
+		return ErrorSystem(ctx)
+	}
+	defer ws.Close()
+
+	// 客户端断开后停止跟踪
+	streamCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	err = r.log.Stream(streamCtx, streamRequest, func(lines []logreader.Line) error {
+		return ws.WriteJSON(lines)
+	})
+	if err != nil {
+		_ = ws.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseInternalServerErr, err.Error()), time.Now().Add(time.Second))
+	}
+
+	return nil
+}
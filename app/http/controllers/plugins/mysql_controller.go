@@ -430,7 +430,7 @@ func (r *MySQLController) CreateBackup(ctx http.Context) http.Response {
 	}
 
 	database := ctx.Request().Input("database")
-	err = r.backup.MysqlBackup(database)
+	err = r.backup.MysqlBackup(database, controllers.BackupStorages(ctx))
 	if err != nil {
 		return controllers.Error(ctx, http.StatusInternalServerError, err.Error())
 	}
@@ -450,9 +450,7 @@ func (r *MySQLController) DeleteBackup(ctx http.Context) http.Response {
 		return controllers.Error(ctx, http.StatusUnprocessableEntity, validator.Errors().One())
 	}
 
-	backupPath := r.setting.Get(models.SettingKeyBackupPath) + "/mysql"
-	fileName := ctx.Request().Input("name")
-	if err := tools.Remove(backupPath + "/" + fileName); err != nil {
+	if err := r.backup.Delete(internal.BackupTypeMysql, ctx.Request().Input("name")); err != nil {
 		return controllers.Error(ctx, http.StatusInternalServerError, err.Error())
 	}
 
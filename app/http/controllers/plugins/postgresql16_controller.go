@@ -374,7 +374,7 @@ func (r *Postgresql16Controller) CreateBackup(ctx http.Context) http.Response {
 	}
 
 	database := ctx.Request().Input("database")
-	err = r.backup.PostgresqlBackup(database)
+	err = r.backup.PostgresqlBackup(database, controllers.BackupStorages(ctx))
 	if err != nil {
 		return controllers.Error(ctx, http.StatusInternalServerError, err.Error())
 	}
@@ -394,9 +394,7 @@ func (r *Postgresql16Controller) DeleteBackup(ctx http.Context) http.Response {
 		return controllers.Error(ctx, http.StatusUnprocessableEntity, validator.Errors().One())
 	}
 
-	backupPath := r.setting.Get(models.SettingKeyBackupPath) + "/postgresql"
-	fileName := ctx.Request().Input("name")
-	if err := tools.Remove(backupPath + "/" + fileName); err != nil {
+	if err := r.backup.Delete(internal.BackupTypePostgresql, ctx.Request().Input("name")); err != nil {
 		return controllers.Error(ctx, http.StatusInternalServerError, err.Error())
 	}
 
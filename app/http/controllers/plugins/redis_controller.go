@@ -6,15 +6,23 @@ import (
 	"github.com/goravel/framework/contracts/http"
 
 	"panel/app/http/controllers"
+	"panel/app/models"
+	"panel/internal"
+	"panel/internal/services"
 	"panel/pkg/tools"
 	"panel/types"
 )
 
 type RedisController struct {
+	setting internal.Setting
+	backup  internal.Backup
 }
 
 func NewRedisController() *RedisController {
-	return &RedisController{}
+	return &RedisController{
+		setting: services.NewSettingImpl(),
+		backup:  services.NewBackupImpl(),
+	}
 }
 
 // Status 获取运行状态
@@ -122,3 +130,104 @@ func (r *RedisController) Load(ctx http.Context) http.Response {
 
 	return controllers.Success(ctx, data)
 }
+
+// BackupList 获取备份列表
+func (r *RedisController) BackupList(ctx http.Context) http.Response {
+	backupList, err := r.backup.RedisList()
+	if err != nil {
+		return controllers.Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	page := ctx.Request().QueryInt("page", 1)
+	limit := ctx.Request().QueryInt("limit", 10)
+	startIndex := (page - 1) * limit
+	endIndex := page * limit
+	if startIndex > len(backupList) {
+		return controllers.Success(ctx, http.Json{
+			"total": 0,
+			"items": []internal.BackupFile{},
+		})
+	}
+	if endIndex > len(backupList) {
+		endIndex = len(backupList)
+	}
+	pagedBackupList := backupList[startIndex:endIndex]
+	if pagedBackupList == nil {
+		pagedBackupList = []internal.BackupFile{}
+	}
+
+	return controllers.Success(ctx, http.Json{
+		"total": len(backupList),
+		"items": pagedBackupList,
+	})
+}
+
+// UploadBackup 上传备份
+func (r *RedisController) UploadBackup(ctx http.Context) http.Response {
+	file, err := ctx.Request().File("file")
+	if err != nil {
+		return controllers.Error(ctx, http.StatusUnprocessableEntity, "上传文件失败")
+	}
+
+	backupPath := r.setting.Get(models.SettingKeyBackupPath) + "/redis"
+	if !tools.Exists(backupPath) {
+		if err = tools.Mkdir(backupPath, 0644); err != nil {
+			return controllers.Error(ctx, http.StatusInternalServerError, "创建备份目录失败: "+err.Error())
+		}
+	}
+
+	name := file.GetClientOriginalName()
+	_, err = file.StoreAs(backupPath, name)
+	if err != nil {
+		return controllers.Error(ctx, http.StatusUnprocessableEntity, "上传文件失败")
+	}
+
+	return controllers.Success(ctx, nil)
+}
+
+// CreateBackup 创建备份
+func (r *RedisController) CreateBackup(ctx http.Context) http.Response {
+	if err := r.backup.RedisBackup(controllers.BackupStorages(ctx)); err != nil {
+		return controllers.Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return controllers.Success(ctx, nil)
+}
+
+// DeleteBackup 删除备份
+func (r *RedisController) DeleteBackup(ctx http.Context) http.Response {
+	validator, err := ctx.Request().Validate(map[string]string{
+		"name": "required|min_len:1|max_len:255",
+	})
+	if err != nil {
+		return controllers.Error(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+	if validator.Fails() {
+		return controllers.Error(ctx, http.StatusUnprocessableEntity, validator.Errors().One())
+	}
+
+	if err := r.backup.Delete(internal.BackupTypeRedis, ctx.Request().Input("name")); err != nil {
+		return controllers.Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return controllers.Success(ctx, nil)
+}
+
+// RestoreBackup 还原备份
+func (r *RedisController) RestoreBackup(ctx http.Context) http.Response {
+	validator, err := ctx.Request().Validate(map[string]string{
+		"backup": "required|min_len:1|max_len:255",
+	})
+	if err != nil {
+		return controllers.Error(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+	if validator.Fails() {
+		return controllers.Error(ctx, http.StatusUnprocessableEntity, validator.Errors().One())
+	}
+
+	if err = r.backup.RedisRestore(ctx.Request().Input("backup")); err != nil {
+		return controllers.Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return controllers.Success(ctx, nil)
+}
@@ -210,14 +210,8 @@ func (r *SettingController) Update(ctx http.Context) http.Response {
 		}
 	}
 
-	if updateRequest.SSL {
-		if out, err := tools.Exec("sed -i 's/APP_SSL=false/APP_SSL=true/g' /www/panel/panel.conf"); err != nil {
-			return Error(ctx, http.StatusInternalServerError, out)
-		}
-	} else {
-		if out, err := tools.Exec("sed -i 's/APP_SSL=true/APP_SSL=false/g' /www/panel/panel.conf"); err != nil {
-			return Error(ctx, http.StatusInternalServerError, out)
-		}
+	if err = r.setting.UpdateSSL(updateRequest.SSL); err != nil {
+		return Error(ctx, http.StatusInternalServerError, err.Error())
 	}
 
 	if oldPort != port || oldEntrance != entrance || oldLanguage != updateRequest.Language || updateRequest.SSL != facades.Config().GetBool("panel.ssl") {
@@ -226,3 +220,74 @@ func (r *SettingController) Update(ctx http.Context) http.Response {
 
 	return Success(ctx, nil)
 }
+
+// HTTPS
+//
+//	@Summary		获取面板 HTTPS 设置
+//	@Description	获取面板绑定的域名、证书和跳转设置
+//	@Tags			面板设置
+//	@Produce		json
+//	@Security		BearerToken
+//	@Success		200	{object}	SuccessResponse
+//	@Router			/panel/setting/https [get]
+func (r *SettingController) HTTPS(ctx http.Context) http.Response {
+	https, err := r.setting.HTTPS()
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "面板设置").With(map[string]any{
+			"error": err.Error(),
+		}).Info("获取面板 HTTPS 设置失败")
+		return ErrorSystem(ctx)
+	}
+
+	return Success(ctx, https)
+}
+
+// UpdateHTTPS
+//
+//	@Summary		更新面板 HTTPS 设置
+//	@Description	为面板绑定域名并通过 ACME 签发证书，签发成功后面板自动开启 HTTPS
+//	@Tags			面板设置
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			data	body		requests.HTTPS	true	"request"
+//	@Success		200		{object}	SuccessResponse
+//	@Router			/panel/setting/https [post]
+func (r *SettingController) UpdateHTTPS(ctx http.Context) http.Response {
+	var updateRequest requests.HTTPS
+	sanitize := Sanitize(ctx, &updateRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	task, err := r.setting.UpdateHTTPS(updateRequest)
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "面板设置").With(map[string]any{
+			"domain": updateRequest.Domain,
+			"error":  err.Error(),
+		}).Info("更新面板 HTTPS 设置失败")
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return Success(ctx, task)
+}
+
+// DeleteHTTPS
+//
+//	@Summary		解除面板证书绑定
+//	@Description	解除面板绑定的证书，面板继续使用当前证书但不再自动续签
+//	@Tags			面板设置
+//	@Produce		json
+//	@Security		BearerToken
+//	@Success		200	{object}	SuccessResponse
+//	@Router			/panel/setting/https [delete]
+func (r *SettingController) DeleteHTTPS(ctx http.Context) http.Response {
+	if err := r.setting.DeleteHTTPS(); err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "面板设置").With(map[string]any{
+			"error": err.Error(),
+		}).Info("解除面板证书绑定失败")
+		return ErrorSystem(ctx)
+	}
+
+	return Success(ctx, nil)
+}
@@ -2,8 +2,6 @@ package controllers
 
 import (
 	"fmt"
-	"regexp"
-	"strings"
 
 	"github.com/goravel/framework/contracts/http"
 	"github.com/goravel/framework/facades"
@@ -97,6 +95,20 @@ func (r *WebsiteController) Add(ctx http.Context) http.Response {
 		DbUser:     addRequest.DbUser,
 		DbPassword: addRequest.DbPassword,
 	}
+	if addRequest.PhpPool {
+		// 进程数使用应用池的默认值
+		website.PhpPool = &models.PhpPool{
+			User:            addRequest.PhpPoolUser,
+			Pm:              addRequest.PhpPoolPm,
+			MaxChildren:     10,
+			StartServers:    2,
+			MinSpareServers: 1,
+			MaxSpareServers: 3,
+		}
+		if addRequest.PhpPoolMaxChildren > 0 {
+			website.PhpPool.MaxChildren = addRequest.PhpPoolMaxChildren
+		}
+	}
 
 	_, err := r.website.Add(website)
 	if err != nil {
@@ -394,7 +406,7 @@ func (r *WebsiteController) CreateBackup(ctx http.Context) http.Response {
 		return ErrorSystem(ctx)
 	}
 
-	if err := r.backup.WebSiteBackup(website); err != nil {
+	if err := r.backup.WebSiteBackup(website, BackupStorages(ctx)); err != nil {
 		facades.Log().Request(ctx.Request()).Tags("面板", "网站管理").With(map[string]any{
 			"id":    idRequest.ID,
 			"error": err.Error(),
@@ -494,20 +506,167 @@ func (r *WebsiteController) DeleteBackup(ctx http.Context) http.Response {
 		return sanitize
 	}
 
-	backupPath := r.setting.Get(models.SettingKeyBackupPath) + "/website"
-	if !tools.Exists(backupPath) {
-		if err := tools.Mkdir(backupPath, 0644); err != nil {
-			return nil
-		}
+	if err := r.backup.Delete(internal.BackupTypeWebsite, deleteBackupRequest.Name); err != nil {
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return Success(ctx, nil)
+}
+
+// Snapshots
+//
+//	@Summary		获取快照列表
+//	@Description	获取网站的去重快照列表，包含每个快照新增和复用的数据统计
+//	@Tags			网站管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"网站 ID"
+//	@Success		200	{object}	SuccessResponse{data=[]dedup.Snapshot}
+//	@Router			/panel/websites/{id}/snapshots [get]
+func (r *WebsiteController) Snapshots(ctx http.Context) http.Response {
+	var idRequest requests.ID
+	sanitize := Sanitize(ctx, &idRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	var website models.Website
+	if err := facades.Orm().Query().Where("id", idRequest.ID).FirstOrFail(&website); err != nil {
+		return ErrorSystem(ctx)
+	}
+
+	snapshots, err := r.backup.WebsiteSnapshotList(website)
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "网站管理").With(map[string]any{
+			"id":    idRequest.ID,
+			"error": err.Error(),
+		}).Info("获取快照列表失败")
+		return ErrorSystem(ctx)
+	}
+
+	return Success(ctx, snapshots)
+}
+
+// CreateSnapshot
+//
+//	@Summary		创建快照
+//	@Description	创建网站的去重快照，只保存与已有快照不同的数据
+//	@Tags			网站管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"网站 ID"
+//	@Success		200	{object}	SuccessResponse{data=dedup.Snapshot}
+//	@Router			/panel/websites/{id}/snapshots [post]
+func (r *WebsiteController) CreateSnapshot(ctx http.Context) http.Response {
+	var idRequest requests.ID
+	sanitize := Sanitize(ctx, &idRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	var website models.Website
+	if err := facades.Orm().Query().Where("id", idRequest.ID).FirstOrFail(&website); err != nil {
+		return ErrorSystem(ctx)
+	}
+
+	snapshot, err := r.backup.WebsiteSnapshot(website)
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "网站管理").With(map[string]any{
+			"id":    idRequest.ID,
+			"error": err.Error(),
+		}).Info("创建快照失败")
+		return Error(ctx, http.StatusInternalServerError, "创建快照失败: "+err.Error())
+	}
+
+	return Success(ctx, snapshot)
+}
+
+// RestoreSnapshot
+//
+//	@Summary		还原快照
+//	@Description	从快照完整还原网站
+//	@Tags			网站管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id			path		int		true	"网站 ID"
+//	@Param			snapshot	path		string	true	"快照 ID"
+//	@Success		200			{object}	SuccessResponse
+//	@Router			/panel/websites/{id}/snapshots/{snapshot}/restore [post]
+func (r *WebsiteController) RestoreSnapshot(ctx http.Context) http.Response {
+	var snapshotRequest requests.Snapshot
+	sanitize := Sanitize(ctx, &snapshotRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	var website models.Website
+	if err := facades.Orm().Query().Where("id", snapshotRequest.ID).FirstOrFail(&website); err != nil {
+		return ErrorSystem(ctx)
+	}
+
+	if err := r.backup.WebsiteSnapshotRestore(website, snapshotRequest.Snapshot); err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "网站管理").With(map[string]any{
+			"id":       snapshotRequest.ID,
+			"snapshot": snapshotRequest.Snapshot,
+			"error":    err.Error(),
+		}).Info("还原快照失败")
+		return Error(ctx, http.StatusInternalServerError, "还原快照失败: "+err.Error())
 	}
 
-	if err := tools.Remove(backupPath + "/" + deleteBackupRequest.Name); err != nil {
+	return Success(ctx, nil)
+}
+
+// DeleteSnapshot
+//
+//	@Summary		删除快照
+//	@Description	删除网站的快照，释放空间需要再清理未引用的数据
+//	@Tags			网站管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id			path		int		true	"网站 ID"
+//	@Param			snapshot	path		string	true	"快照 ID"
+//	@Success		200			{object}	SuccessResponse
+//	@Router			/panel/websites/{id}/snapshots/{snapshot} [delete]
+func (r *WebsiteController) DeleteSnapshot(ctx http.Context) http.Response {
+	var snapshotRequest requests.Snapshot
+	sanitize := Sanitize(ctx, &snapshotRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	var website models.Website
+	if err := facades.Orm().Query().Where("id", snapshotRequest.ID).FirstOrFail(&website); err != nil {
+		return ErrorSystem(ctx)
+	}
+
+	if err := r.backup.WebsiteSnapshotDelete(website, snapshotRequest.Snapshot); err != nil {
 		return Error(ctx, http.StatusInternalServerError, err.Error())
 	}
 
 	return Success(ctx, nil)
 }
 
+// PruneSnapshots
+//
+//	@Summary		清理快照数据
+//	@Description	删除没有被任何快照引用的数据
+//	@Tags			网站管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Success		200	{object}	SuccessResponse{data=dedup.PruneStats}
+//	@Router			/panel/website/snapshots/prune [post]
+func (r *WebsiteController) PruneSnapshots(ctx http.Context) http.Response {
+	stats, err := r.backup.SnapshotPrune()
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "网站管理").With(map[string]any{
+			"error": err.Error(),
+		}).Info("清理快照数据失败")
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return Success(ctx, stats)
+}
+
 // ResetConfig
 //
 //	@Summary		重置配置
@@ -630,51 +789,264 @@ func (r *WebsiteController) Status(ctx http.Context) http.Response {
 		return sanitize
 	}
 
-	website := models.Website{}
-	if err := facades.Orm().Query().Where("id", idRequest.ID).Get(&website); err != nil {
-		return ErrorSystem(ctx)
+	if err := r.website.UpdateStatus(idRequest.ID, ctx.Request().InputBool("status")); err != nil {
+		return Error(ctx, http.StatusInternalServerError, err.Error())
 	}
 
-	website.Status = ctx.Request().InputBool("status")
-	if err := facades.Orm().Query().Save(&website); err != nil {
-		return ErrorSystem(ctx)
+	return Success(ctx, nil)
+}
+
+// Batch
+//
+//	@Summary		批量操作
+//	@Description	创建批量启用、停用、备份、切换 PHP 版本、开启 HTTPS 跳转或删除网站的任务
+//	@Tags			网站管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			data	body		requests.Batch	true	"request"
+//	@Success		200		{object}	SuccessResponse
+//	@Router			/panel/websites/batch [post]
+func (r *WebsiteController) Batch(ctx http.Context) http.Response {
+	var batchRequest requests.Batch
+	sanitize := Sanitize(ctx, &batchRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	if err := r.website.Batch(batchRequest); err != nil {
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return Success(ctx, nil)
+}
+
+// ConvertHtaccess
+//
+//	@Summary		转换 .htaccess
+//	@Description	将 Apache .htaccess 规则转换为伪静态规则预览，未提交内容时读取网站运行目录下的 .htaccess
+//	@Tags			网站管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id		path		int							true	"网站 ID"
+//	@Param			data	body		requests.ConvertHtaccess	true	"request"
+//	@Success		200		{object}	SuccessResponse{data=htaccess.Result}
+//	@Router			/panel/websites/{id}/htaccess/convert [post]
+func (r *WebsiteController) ConvertHtaccess(ctx http.Context) http.Response {
+	var convertRequest requests.ConvertHtaccess
+	sanitize := Sanitize(ctx, &convertRequest)
+	if sanitize != nil {
+		return sanitize
 	}
 
-	raw, err := tools.Read("/www/server/vhost/" + website.Name + ".conf")
+	result, err := r.website.ConvertHtaccess(convertRequest.ID, convertRequest.Content)
 	if err != nil {
+		return Error(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return Success(ctx, result)
+}
+
+// SaveRewrite
+//
+//	@Summary		保存伪静态
+//	@Description	保存网站的伪静态规则
+//	@Tags			网站管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id		path		int						true	"网站 ID"
+//	@Param			data	body		requests.SaveRewrite	true	"request"
+//	@Success		200		{object}	SuccessResponse
+//	@Router			/panel/websites/{id}/rewrite [post]
+func (r *WebsiteController) SaveRewrite(ctx http.Context) http.Response {
+	var rewriteRequest requests.SaveRewrite
+	sanitize := Sanitize(ctx, &rewriteRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	if err := r.website.SaveRewrite(rewriteRequest.ID, rewriteRequest.Rewrite); err != nil {
 		return Error(ctx, http.StatusInternalServerError, err.Error())
 	}
 
-	// 运行目录
-	rootConfig := tools.Cut(raw, "# root标记位开始\n", "# root标记位结束")
-	match := regexp.MustCompile(`root\s+(.+);`).FindStringSubmatch(rootConfig)
-	if len(match) == 2 {
-		if website.Status {
-			root := regexp.MustCompile(`# root\s+(.+);`).FindStringSubmatch(rootConfig)
-			raw = strings.ReplaceAll(raw, rootConfig, "    root "+root[1]+";\n    ")
-		} else {
-			raw = strings.ReplaceAll(raw, rootConfig, "    root /www/server/openresty/html;\n    # root "+match[1]+";\n    ")
-		}
+	return Success(ctx, nil)
+}
+
+// Export
+//
+//	@Summary		导出网站
+//	@Description	导出包含网站文件、配置、证书和数据库的迁移包
+//	@Tags			网站管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id		path		int				true	"网站 ID"
+//	@Param			data	body		requests.Export	true	"request"
+//	@Success		200		{object}	SuccessResponse{data=string}
+//	@Router			/panel/websites/{id}/export [post]
+func (r *WebsiteController) Export(ctx http.Context) http.Response {
+	var exportRequest requests.Export
+	sanitize := Sanitize(ctx, &exportRequest)
+	if sanitize != nil {
+		return sanitize
 	}
 
-	// 默认文件
-	indexConfig := tools.Cut(raw, "# index标记位开始\n", "# index标记位结束")
-	match = regexp.MustCompile(`index\s+(.+);`).FindStringSubmatch(indexConfig)
-	if len(match) == 2 {
-		if website.Status {
-			index := regexp.MustCompile(`# index\s+(.+);`).FindStringSubmatch(indexConfig)
-			raw = strings.ReplaceAll(raw, indexConfig, "    index "+index[1]+";\n    ")
-		} else {
-			raw = strings.ReplaceAll(raw, indexConfig, "    index stop.html;\n    # index "+match[1]+";\n    ")
-		}
+	bundle, err := r.website.Export(exportRequest)
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "网站管理").With(map[string]any{
+			"id":    exportRequest.ID,
+			"error": err.Error(),
+		}).Info("导出网站失败")
+		return Error(ctx, http.StatusInternalServerError, "导出网站失败: "+err.Error())
 	}
 
-	if err := tools.Write("/www/server/vhost/"+website.Name+".conf", raw, 0644); err != nil {
+	return Success(ctx, bundle)
+}
+
+// Import
+//
+//	@Summary		导入网站
+//	@Description	从迁移包重建网站、数据库、数据库用户和证书，可覆盖网站名、域名和目录
+//	@Tags			网站管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			data	body		requests.Import	true	"request"
+//	@Success		200		{object}	SuccessResponse{data=models.Website}
+//	@Router			/panel/websites/import [post]
+func (r *WebsiteController) Import(ctx http.Context) http.Response {
+	var importRequest requests.Import
+	sanitize := Sanitize(ctx, &importRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	website, err := r.website.Import(importRequest)
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "网站管理").With(map[string]any{
+			"file":  importRequest.File,
+			"error": err.Error(),
+		}).Info("导入网站失败")
+		return Error(ctx, http.StatusInternalServerError, "导入网站失败: "+err.Error())
+	}
+
+	return Success(ctx, website)
+}
+
+// GetPhpPool
+//
+//	@Summary		获取独立应用池
+//	@Description	获取网站的独立 PHP-FPM 应用池配置
+//	@Tags			网站管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"网站 ID"
+//	@Success		200	{object}	SuccessResponse{data=models.PhpPool}
+//	@Router			/panel/websites/{id}/phpPool [get]
+func (r *WebsiteController) GetPhpPool(ctx http.Context) http.Response {
+	var idRequest requests.ID
+	sanitize := Sanitize(ctx, &idRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	pool, err := r.website.GetPhpPool(idRequest.ID)
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "网站管理").With(map[string]any{
+			"id":    idRequest.ID,
+			"error": err.Error(),
+		}).Info("获取独立应用池失败")
 		return ErrorSystem(ctx)
 	}
-	if exec, err := tools.Exec("systemctl reload openresty"); err != nil {
-		return Error(ctx, http.StatusInternalServerError, exec)
+
+	return Success(ctx, pool)
+}
+
+// SavePhpPool
+//
+//	@Summary		保存独立应用池
+//	@Description	为网站创建或更新以独立用户运行的 PHP-FPM 应用池
+//	@Tags			网站管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id		path		int						true	"网站 ID"
+//	@Param			data	body		requests.SavePhpPool	true	"request"
+//	@Success		200		{object}	SuccessResponse
+//	@Router			/panel/websites/{id}/phpPool [post]
+func (r *WebsiteController) SavePhpPool(ctx http.Context) http.Response {
+	var poolRequest requests.SavePhpPool
+	sanitize := Sanitize(ctx, &poolRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	if err := r.website.SavePhpPool(poolRequest); err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "网站管理").With(map[string]any{
+			"id":    poolRequest.ID,
+			"error": err.Error(),
+		}).Info("保存独立应用池失败")
+		return Error(ctx, http.StatusInternalServerError, "保存独立应用池失败: "+err.Error())
+	}
+
+	return Success(ctx, nil)
+}
+
+// DeletePhpPool
+//
+//	@Summary		删除独立应用池
+//	@Description	删除网站的独立 PHP-FPM 应用池，网站改回使用公共应用池
+//	@Tags			网站管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"网站 ID"
+//	@Success		200	{object}	SuccessResponse
+//	@Router			/panel/websites/{id}/phpPool [delete]
+func (r *WebsiteController) DeletePhpPool(ctx http.Context) http.Response {
+	var idRequest requests.ID
+	sanitize := Sanitize(ctx, &idRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	if err := r.website.DeletePhpPool(idRequest.ID); err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "网站管理").With(map[string]any{
+			"id":    idRequest.ID,
+			"error": err.Error(),
+		}).Info("删除独立应用池失败")
+		return Error(ctx, http.StatusInternalServerError, "删除独立应用池失败: "+err.Error())
 	}
 
 	return Success(ctx, nil)
 }
+
+// InspectCert
+//
+//	@Summary		检查网站证书
+//	@Description	检查网站当前使用证书的证书链、私钥、域名覆盖和 OCSP 状态
+//	@Tags			网站管理
+//	@Produce		json
+//	@Security		BearerToken
+//	@Param			id	path		int	true	"网站 ID"
+//	@Success		200	{object}	SuccessResponse{data=acme.Inspection}
+//	@Router			/panel/websites/{id}/inspectCert [get]
+func (r *WebsiteController) InspectCert(ctx http.Context) http.Response {
+	var idRequest requests.ID
+	sanitize := Sanitize(ctx, &idRequest)
+	if sanitize != nil {
+		return sanitize
+	}
+
+	inspection, err := r.website.InspectCert(idRequest.ID)
+	if err != nil {
+		facades.Log().Request(ctx.Request()).Tags("面板", "网站管理").With(map[string]any{
+			"id":    idRequest.ID,
+			"error": err.Error(),
+		}).Info("检查网站证书失败")
+		return Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+
+	return Success(ctx, inspection)
+}
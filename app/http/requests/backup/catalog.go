@@ -0,0 +1,34 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type Catalog struct {
+	Type   string `form:"type" json:"type"`
+	Source string `form:"source" json:"source"`
+}
+
+func (r *Catalog) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *Catalog) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"type":   "in:website,mysql,postgresql,redis",
+		"source": "string",
+	}
+}
+
+func (r *Catalog) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *Catalog) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *Catalog) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
@@ -0,0 +1,32 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type Encryption struct {
+	KeyID uint `form:"key_id" json:"key_id"`
+}
+
+func (r *Encryption) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *Encryption) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"key_id": "uint",
+	}
+}
+
+func (r *Encryption) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *Encryption) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *Encryption) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
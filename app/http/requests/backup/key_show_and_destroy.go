@@ -0,0 +1,32 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type KeyShowAndDestroy struct {
+	ID uint `form:"id" json:"id"`
+}
+
+func (r *KeyShowAndDestroy) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *KeyShowAndDestroy) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"id": "required|uint|min:1|exists:backup_keys,id",
+	}
+}
+
+func (r *KeyShowAndDestroy) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *KeyShowAndDestroy) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *KeyShowAndDestroy) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
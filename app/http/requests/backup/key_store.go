@@ -0,0 +1,40 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type KeyStore struct {
+	Name       string `form:"name" json:"name"`
+	Type       string `form:"type" json:"type"`
+	Passphrase string `form:"passphrase" json:"passphrase"`
+	Recipient  string `form:"recipient" json:"recipient"`
+	Identity   string `form:"identity" json:"identity"`
+}
+
+func (r *KeyStore) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *KeyStore) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"name":       "required|string:1,255",
+		"type":       "required|in:passphrase,x25519",
+		"passphrase": "required_if:type,passphrase|string:8,255",
+		"recipient":  "string",
+		"identity":   "string",
+	}
+}
+
+func (r *KeyStore) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *KeyStore) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *KeyStore) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
@@ -0,0 +1,44 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type Prune struct {
+	Type    string `form:"type" json:"type"`
+	Name    string `form:"name" json:"name"`
+	Last    uint   `form:"last" json:"last" filter:"uint"`
+	Daily   uint   `form:"daily" json:"daily" filter:"uint"`
+	Weekly  uint   `form:"weekly" json:"weekly" filter:"uint"`
+	Monthly uint   `form:"monthly" json:"monthly" filter:"uint"`
+	DryRun  bool   `form:"dry_run" json:"dry_run" filter:"bool"`
+}
+
+func (r *Prune) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *Prune) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"type":    "required|in:website,mysql,postgresql,redis",
+		"name":    "required|string:1,255",
+		"last":    "uint",
+		"daily":   "uint",
+		"weekly":  "uint",
+		"monthly": "uint",
+		"dry_run": "bool",
+	}
+}
+
+func (r *Prune) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *Prune) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *Prune) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
@@ -0,0 +1,34 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type StorageHostKey struct {
+	Host string `form:"host" json:"host"`
+	Port uint   `form:"port" json:"port"`
+}
+
+func (r *StorageHostKey) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *StorageHostKey) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"host": "required|string:1,255",
+		"port": "uint|min:1|max:65535",
+	}
+}
+
+func (r *StorageHostKey) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *StorageHostKey) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *StorageHostKey) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
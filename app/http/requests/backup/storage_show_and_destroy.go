@@ -0,0 +1,32 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type StorageShowAndDestroy struct {
+	ID uint `form:"id" json:"id"`
+}
+
+func (r *StorageShowAndDestroy) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *StorageShowAndDestroy) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"id": "required|uint|min:1|exists:backup_storages,id",
+	}
+}
+
+func (r *StorageShowAndDestroy) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *StorageShowAndDestroy) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *StorageShowAndDestroy) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
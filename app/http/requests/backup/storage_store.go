@@ -0,0 +1,49 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+
+	"panel/pkg/storage"
+)
+
+type StorageStore struct {
+	Name    string         `form:"name" json:"name"`
+	Type    string         `form:"type" json:"type"`
+	Config  storage.Config `form:"config" json:"config"`
+	Enabled bool           `form:"enabled" json:"enabled"`
+}
+
+func (r *StorageStore) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *StorageStore) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"name":              "required|string:1,255",
+		"type":              "required|in:local,s3,sftp",
+		"config":            "required",
+		"config.path":       "required_if:type,local,sftp",
+		"config.bucket":     "required_if:type,s3",
+		"config.endpoint":   "full_url",
+		"config.prefix":     "max_len:255",
+		"config.path_style": "bool",
+		"config.host":       "required_if:type,sftp",
+		"config.port":       "uint|min:1|max:65535",
+		"config.user":       "required_if:type,sftp",
+		"config.host_key":   "required_if:type,sftp",
+		"enabled":           "bool",
+	}
+}
+
+func (r *StorageStore) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *StorageStore) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *StorageStore) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
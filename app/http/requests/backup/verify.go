@@ -0,0 +1,32 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type Verify struct {
+	ID uint `form:"id" json:"id"`
+}
+
+func (r *Verify) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *Verify) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"id": "required|uint|min:1|exists:backups,id",
+	}
+}
+
+func (r *Verify) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *Verify) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *Verify) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
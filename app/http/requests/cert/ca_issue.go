@@ -0,0 +1,42 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type CAIssue struct {
+	ID         uint     `form:"id" json:"id" filter:"uint"`
+	CommonName string   `form:"common_name" json:"common_name"`
+	Domains    []string `form:"domains" json:"domains"`
+	Usage      string   `form:"usage" json:"usage"`
+	Type       string   `form:"type" json:"type"`
+	Days       int      `form:"days" json:"days" filter:"int"`
+}
+
+func (r *CAIssue) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *CAIssue) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"id":          "required|uint|min:1|exists:cert_authorities,id",
+		"common_name": "max_len:255",
+		"domains":     "required|slice",
+		"usage":       "required|in:server,client",
+		"type":        "required|in:P256,P384,2048,4096",
+		"days":        "required|int|min:1|max:36500",
+	}
+}
+
+func (r *CAIssue) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *CAIssue) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *CAIssue) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
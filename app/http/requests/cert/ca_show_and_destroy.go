@@ -0,0 +1,32 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type CAShowAndDestroy struct {
+	ID uint `form:"id" json:"id" filter:"uint"`
+}
+
+func (r *CAShowAndDestroy) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *CAShowAndDestroy) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"id": "required|uint|min:1|exists:cert_authorities,id",
+	}
+}
+
+func (r *CAShowAndDestroy) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *CAShowAndDestroy) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *CAShowAndDestroy) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
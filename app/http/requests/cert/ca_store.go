@@ -0,0 +1,38 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type CAStore struct {
+	Name   string `form:"name" json:"name"`
+	Type   string `form:"type" json:"type"`
+	Days   int    `form:"days" json:"days" filter:"int"`
+	CRLURL string `form:"crl_url" json:"crl_url"`
+}
+
+func (r *CAStore) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *CAStore) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"name":    "required|min_len:1|max_len:255",
+		"type":    "required|in:P256,P384,2048,4096",
+		"days":    "required|int|min:1|max:36500",
+		"crl_url": "full_url",
+	}
+}
+
+func (r *CAStore) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *CAStore) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *CAStore) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
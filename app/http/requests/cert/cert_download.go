@@ -0,0 +1,36 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type CertDownload struct {
+	ID       uint   `form:"id" json:"id"`
+	Format   string `form:"format" json:"format"`
+	Password string `form:"password" json:"password"`
+}
+
+func (r *CertDownload) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *CertDownload) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"id":       "required|uint|min:1|exists:certs,id",
+		"format":   "required|in:fullchain,leaf,intermediate,key,der,pfx,pfx_legacy,bundle",
+		"password": "string|max_len:255",
+	}
+}
+
+func (r *CertDownload) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *CertDownload) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *CertDownload) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
@@ -0,0 +1,36 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type CertImport struct {
+	ID    uint   `form:"id" json:"id"`
+	Cert  string `form:"cert" json:"cert"`
+	Chain string `form:"chain" json:"chain"`
+}
+
+func (r *CertImport) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *CertImport) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"id":    "required|uint|min:1|exists:certs,id",
+		"cert":  "required|string",
+		"chain": "string",
+	}
+}
+
+func (r *CertImport) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *CertImport) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *CertImport) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
@@ -0,0 +1,56 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+	"github.com/spf13/cast"
+)
+
+type CSRStore struct {
+	Type               string   `form:"type" json:"type"`
+	Domains            []string `form:"domains" json:"domains"`
+	Organization       string   `form:"organization" json:"organization"`
+	OrganizationalUnit string   `form:"organizational_unit" json:"organizational_unit"`
+	Country            string   `form:"country" json:"country"`
+	Province           string   `form:"province" json:"province"`
+	Locality           string   `form:"locality" json:"locality"`
+	WebsiteID          *uint    `form:"website_id" json:"website_id"`
+}
+
+func (r *CSRStore) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *CSRStore) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"type":                "required|in:P256,P384,2048,4096",
+		"domains":             "required|slice",
+		"organization":        "max_len:255",
+		"organizational_unit": "max_len:255",
+		"country":             "regex:^[A-Z]{2}$",
+		"province":            "max_len:255",
+		"locality":            "max_len:255",
+		"website_id":          "uint",
+	}
+}
+
+func (r *CSRStore) Messages(ctx http.Context) map[string]string {
+	return map[string]string{
+		"country.regex": "country 必须是两位大写的国家代码",
+	}
+}
+
+func (r *CSRStore) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *CSRStore) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	websiteID, exist := data.Get("website_id")
+	if exist {
+		if err := data.Set("website_id", cast.ToUint(websiteID)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -19,14 +19,22 @@ func (r *DNSStore) Authorize(ctx http.Context) error {
 
 func (r *DNSStore) Rules(ctx http.Context) map[string]string {
 	return map[string]string{
-		"type":            "required|in:dnspod,aliyun,cloudflare",
-		"name":            "required",
-		"data":            "required",
-		"data.id":         "required_if:type,dnspod",
-		"data.token":      "required_if:type,dnspod",
-		"data.access_key": "required_if:type,aliyun",
-		"data.secret_key": "required_if:type,aliyun",
-		"data.api_key":    "required_if:type,cloudflare",
+		"type":                     "required|in:dnspod,aliyun,cloudflare,rfc2136,exec",
+		"name":                     "required",
+		"data":                     "required",
+		"data.id":                  "required_if:type,dnspod",
+		"data.token":               "required_if:type,dnspod",
+		"data.access_key":          "required_if:type,aliyun",
+		"data.secret_key":          "required_if:type,aliyun",
+		"data.api_key":             "required_if:type,cloudflare",
+		"data.server":              "required_if:type,rfc2136",
+		"data.port":                "uint|min:1|max:65535",
+		"data.zone":                "required_if:type,rfc2136",
+		"data.tsig_algorithm":      "required_with:data.tsig_key|in:hmac-md5,hmac-sha1,hmac-sha224,hmac-sha256,hmac-sha384,hmac-sha512",
+		"data.tsig_secret":         "required_with:data.tsig_key",
+		"data.present_script":      "required_if:type,exec",
+		"data.ttl":                 "uint",
+		"data.propagation_timeout": "uint|max:3600",
 	}
 }
 
@@ -20,16 +20,24 @@ func (r *DNSUpdate) Authorize(ctx http.Context) error {
 
 func (r *DNSUpdate) Rules(ctx http.Context) map[string]string {
 	return map[string]string{
-		"id":              "required|uint|min:1|exists:cert_dns,id",
-		"type":            "required|in:dnspod,aliyun,cloudflare",
-		"name":            "required",
-		"data":            "required",
-		"data.id":         "required_if:type,dnspod",
-		"data.token":      "required_if:type,dnspod",
-		"data.access_key": "required_if:type,aliyun",
-		"data.secret_key": "required_if:type,aliyun",
-		"data.email":      "required_if:type,cloudflare",
-		"data.api_key":    "required_if:type,cloudflare",
+		"id":                       "required|uint|min:1|exists:cert_dns,id",
+		"type":                     "required|in:dnspod,aliyun,cloudflare,rfc2136,exec",
+		"name":                     "required",
+		"data":                     "required",
+		"data.id":                  "required_if:type,dnspod",
+		"data.token":               "required_if:type,dnspod",
+		"data.access_key":          "required_if:type,aliyun",
+		"data.secret_key":          "required_if:type,aliyun",
+		"data.email":               "required_if:type,cloudflare",
+		"data.api_key":             "required_if:type,cloudflare",
+		"data.server":              "required_if:type,rfc2136",
+		"data.port":                "uint|min:1|max:65535",
+		"data.zone":                "required_if:type,rfc2136",
+		"data.tsig_algorithm":      "required_with:data.tsig_key|in:hmac-md5,hmac-sha1,hmac-sha224,hmac-sha256,hmac-sha384,hmac-sha512",
+		"data.tsig_secret":         "required_with:data.tsig_key",
+		"data.present_script":      "required_if:type,exec",
+		"data.ttl":                 "uint",
+		"data.propagation_timeout": "uint|max:3600",
 	}
 }
 
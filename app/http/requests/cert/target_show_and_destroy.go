@@ -0,0 +1,32 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type TargetShowAndDestroy struct {
+	ID uint `form:"id" json:"id" filter:"uint"`
+}
+
+func (r *TargetShowAndDestroy) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *TargetShowAndDestroy) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"id": "required|uint|min:1|exists:cert_targets,id",
+	}
+}
+
+func (r *TargetShowAndDestroy) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *TargetShowAndDestroy) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *TargetShowAndDestroy) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
@@ -0,0 +1,51 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type TargetUpdate struct {
+	ID            uint   `form:"id" json:"id" filter:"uint"`
+	Name          string `form:"name" json:"name"`
+	CertPath      string `form:"cert_path" json:"cert_path"`
+	KeyPath       string `form:"key_path" json:"key_path"`
+	FullchainPath string `form:"fullchain_path" json:"fullchain_path"`
+	PfxPath       string `form:"pfx_path" json:"pfx_path"`
+	PfxPassword   string `form:"pfx_password" json:"pfx_password"`
+	Mode          string `form:"mode" json:"mode"`
+	Owner         string `form:"owner" json:"owner"`
+	Service       string `form:"service" json:"service"`
+	Command       string `form:"command" json:"command"`
+}
+
+func (r *TargetUpdate) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *TargetUpdate) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"id":             "required|uint|min:1|exists:cert_targets,id",
+		"name":           "required|string",
+		"cert_path":      "required|regex:^/.+",
+		"key_path":       "required|regex:^/.+",
+		"fullchain_path": "regex:^/.+",
+		"pfx_path":       "regex:^/.+",
+		"mode":           "regex:^0?[0-7]{3}$",
+		"owner":          "regex:^[a-z_][a-z0-9_-]*(:[a-z_][a-z0-9_-]*)?$",
+		"service":        "regex:^[a-zA-Z0-9_.@-]+$",
+		"command":        "string",
+	}
+}
+
+func (r *TargetUpdate) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *TargetUpdate) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *TargetUpdate) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
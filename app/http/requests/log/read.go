@@ -0,0 +1,46 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type Read struct {
+	Source     string `form:"source" json:"source"`
+	Keyword    string `form:"keyword" json:"keyword"`
+	Regex      bool   `form:"regex" json:"regex" filter:"bool"`
+	IgnoreCase bool   `form:"ignore_case" json:"ignore_case" filter:"bool"`
+	Since      string `form:"since" json:"since"`
+	Until      string `form:"until" json:"until"`
+	Before     int64  `form:"before" json:"before" filter:"int64"`
+	Limit      int    `form:"limit" json:"limit" filter:"int"`
+}
+
+func (r *Read) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *Read) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"source":      "required|string",
+		"keyword":     "string",
+		"regex":       "bool",
+		"ignore_case": "bool",
+		"since":       "string",
+		"until":       "string",
+		"before":      "int|min:0",
+		"limit":       "int|min:0|max:1000",
+	}
+}
+
+func (r *Read) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *Read) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *Read) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
@@ -0,0 +1,40 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type Stream struct {
+	Source     string `form:"source" json:"source"`
+	Keyword    string `form:"keyword" json:"keyword"`
+	Regex      bool   `form:"regex" json:"regex" filter:"bool"`
+	IgnoreCase bool   `form:"ignore_case" json:"ignore_case" filter:"bool"`
+	Offset     int64  `form:"offset" json:"offset" filter:"int64"`
+}
+
+func (r *Stream) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *Stream) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"source":      "required|string",
+		"keyword":     "string",
+		"regex":       "bool",
+		"ignore_case": "bool",
+		"offset":      "int",
+	}
+}
+
+func (r *Stream) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *Stream) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *Stream) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
@@ -0,0 +1,54 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+	"github.com/spf13/cast"
+)
+
+type HTTPS struct {
+	Domain   string `form:"domain" json:"domain"`
+	Type     string `form:"type" json:"type"`
+	UserID   uint   `form:"user_id" json:"user_id"`
+	DNSID    *uint  `form:"dns_id" json:"dns_id"`
+	Redirect bool   `form:"redirect" json:"redirect"`
+}
+
+func (r *HTTPS) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *HTTPS) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"domain":   `required|regex:^(\*\.)?[a-zA-Z0-9-]+(\.[a-zA-Z0-9-]+)*$|max_len:255`,
+		"type":     "required|in:P256,P384,2048,4096",
+		"user_id":  "required|uint|exists:cert_users,id",
+		"dns_id":   "uint",
+		"redirect": "bool",
+	}
+}
+
+func (r *HTTPS) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *HTTPS) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *HTTPS) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	userID, exist := data.Get("user_id")
+	if exist {
+		if err := data.Set("user_id", cast.ToUint(userID)); err != nil {
+			return err
+		}
+	}
+	dnsID, exist := data.Get("dns_id")
+	if exist {
+		if err := data.Set("dns_id", cast.ToUint(dnsID)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
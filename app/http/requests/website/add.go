@@ -16,6 +16,11 @@ type Add struct {
 	DbName     string   `form:"db_name" json:"db_name"`
 	DbUser     string   `form:"db_user" json:"db_user"`
 	DbPassword string   `form:"db_password" json:"db_password"`
+
+	PhpPool            bool   `form:"php_pool" json:"php_pool"`
+	PhpPoolUser        string `form:"php_pool_user" json:"php_pool_user"`
+	PhpPoolPm          string `form:"php_pool_pm" json:"php_pool_pm"`
+	PhpPoolMaxChildren int    `form:"php_pool_max_children" json:"php_pool_max_children" filter:"int"`
 }
 
 func (r *Add) Authorize(ctx http.Context) error {
@@ -34,6 +39,11 @@ func (r *Add) Rules(ctx http.Context) map[string]string {
 		"db_name":     "required_if:db,true|regex:^[a-zA-Z0-9_-]+$",
 		"db_user":     "required_if:db,true|regex:^[a-zA-Z0-9_-]+$",
 		"db_password": "required_if:db,true|min_len:8",
+
+		"php_pool":              "bool",
+		"php_pool_user":         "required_if:php_pool,true|regex:^[a-z_][a-z0-9_-]{0,31}$|not_in:root,www,mysql,postgres,redis",
+		"php_pool_pm":           "required_if:php_pool,true|in:static,dynamic,ondemand",
+		"php_pool_max_children": "int|min:3|max:10000",
 	}
 }
 
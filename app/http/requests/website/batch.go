@@ -0,0 +1,37 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type Batch struct {
+	IDs    []uint `form:"ids" json:"ids"`
+	Action string `form:"action" json:"action"`
+	Php    int    `form:"php" json:"php" filter:"int"`
+}
+
+func (r *Batch) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *Batch) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"ids":    "required|slice",
+		"ids.*":  "required|exists:websites,id",
+		"action": "required|in:start,stop,backup,php,http_redirect,delete",
+		"php":    "int",
+	}
+}
+
+func (r *Batch) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *Batch) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *Batch) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
@@ -0,0 +1,34 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type ConvertHtaccess struct {
+	ID      uint   `form:"id" json:"id" filter:"uint"`
+	Content string `form:"content" json:"content"`
+}
+
+func (r *ConvertHtaccess) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *ConvertHtaccess) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"id":      "required|exists:websites,id",
+		"content": "string",
+	}
+}
+
+func (r *ConvertHtaccess) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *ConvertHtaccess) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *ConvertHtaccess) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
@@ -0,0 +1,46 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type DeployConfig struct {
+	ID           uint   `form:"id" json:"id" filter:"uint"`
+	Repo         string `form:"repo" json:"repo"`
+	Branch       string `form:"branch" json:"branch"`
+	DeployKey    string `form:"deploy_key" json:"deploy_key"`
+	RemoveKey    bool   `form:"remove_key" json:"remove_key"`
+	PreDeploy    string `form:"pre_deploy" json:"pre_deploy"`
+	PostDeploy   string `form:"post_deploy" json:"post_deploy"`
+	KeepReleases int    `form:"keep_releases" json:"keep_releases" filter:"int"`
+}
+
+func (r *DeployConfig) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *DeployConfig) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"id":            "required|exists:websites,id",
+		"repo":          `required|regex:^[a-zA-Z0-9_.@:/~+][a-zA-Z0-9_.@:/~+\-]*$`,
+		"branch":        `required|regex:^[a-zA-Z0-9_./][a-zA-Z0-9_./\-]*$`,
+		"deploy_key":    "string",
+		"remove_key":    "bool",
+		"pre_deploy":    "string",
+		"post_deploy":   "string",
+		"keep_releases": "required|int|min:1|max:50",
+	}
+}
+
+func (r *DeployConfig) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *DeployConfig) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *DeployConfig) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
@@ -0,0 +1,34 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type DeployRollback struct {
+	ID      uint   `form:"id" json:"id" filter:"uint"`
+	Release string `form:"release" json:"release"`
+}
+
+func (r *DeployRollback) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *DeployRollback) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"id":      "required|exists:websites,id",
+		"release": `required|regex:^\d{14}$`,
+	}
+}
+
+func (r *DeployRollback) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *DeployRollback) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *DeployRollback) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
@@ -0,0 +1,40 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type Export struct {
+	ID     uint   `form:"id" json:"id" filter:"uint"`
+	Db     bool   `form:"db" json:"db"`
+	DbType string `form:"db_type" json:"db_type"`
+	DbName string `form:"db_name" json:"db_name"`
+	DbUser string `form:"db_user" json:"db_user"`
+}
+
+func (r *Export) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *Export) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"id":      "required|exists:websites,id",
+		"db":      "bool",
+		"db_type": "required_if:db,true|in:mysql,postgresql",
+		"db_name": "required_if:db,true|regex:^[a-zA-Z0-9_-]+$",
+		"db_user": "required_if:db,true|regex:^[a-zA-Z0-9_-]+$",
+	}
+}
+
+func (r *Export) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *Export) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *Export) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
@@ -0,0 +1,40 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type Import struct {
+	File       string   `form:"file" json:"file"`
+	Name       string   `form:"name" json:"name"`
+	Domains    []string `form:"domains" json:"domains"`
+	Path       string   `form:"path" json:"path"`
+	DbPassword string   `form:"db_password" json:"db_password"`
+}
+
+func (r *Import) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *Import) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"file":        `required|regex:^/[a-zA-Z0-9_.@#$%\-\s\[\]()]+(/[a-zA-Z0-9_.@#$%\-\s\[\]()]+)*$|path_exists`,
+		"name":        "regex:^[a-zA-Z0-9_-]+(\\.[a-zA-Z0-9_-]+)*$|not_exists:websites,name|not_in:phpmyadmin,mysql,panel,ssh",
+		"domains":     "slice",
+		"path":        `regex:^/[a-zA-Z0-9_.@#$%\-\s\[\]()]+(/[a-zA-Z0-9_.@#$%\-\s\[\]()]+)*$`,
+		"db_password": "min_len:8",
+	}
+}
+
+func (r *Import) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *Import) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *Import) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
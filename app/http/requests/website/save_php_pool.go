@@ -0,0 +1,46 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type SavePhpPool struct {
+	ID              uint              `form:"id" json:"id" filter:"uint"`
+	User            string            `form:"user" json:"user"`
+	Pm              string            `form:"pm" json:"pm"`
+	MaxChildren     int               `form:"max_children" json:"max_children" filter:"int"`
+	StartServers    int               `form:"start_servers" json:"start_servers" filter:"int"`
+	MinSpareServers int               `form:"min_spare_servers" json:"min_spare_servers" filter:"int"`
+	MaxSpareServers int               `form:"max_spare_servers" json:"max_spare_servers" filter:"int"`
+	Ini             map[string]string `form:"ini" json:"ini"`
+}
+
+func (r *SavePhpPool) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *SavePhpPool) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"id":                "required|exists:websites,id",
+		"user":              "required|regex:^[a-z_][a-z0-9_-]{0,31}$|not_in:root,www,mysql,postgres,redis",
+		"pm":                "required|in:static,dynamic,ondemand",
+		"max_children":      "required|int|min:1|max:10000",
+		"start_servers":     "int|min:0",
+		"min_spare_servers": "int|min:0",
+		"max_spare_servers": "int|min:0",
+		"ini":               "map",
+	}
+}
+
+func (r *SavePhpPool) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *SavePhpPool) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *SavePhpPool) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
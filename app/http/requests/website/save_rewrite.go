@@ -0,0 +1,34 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type SaveRewrite struct {
+	ID      uint   `form:"id" json:"id" filter:"uint"`
+	Rewrite string `form:"rewrite" json:"rewrite"`
+}
+
+func (r *SaveRewrite) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *SaveRewrite) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"id":      "required|exists:websites,id",
+		"rewrite": "string",
+	}
+}
+
+func (r *SaveRewrite) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *SaveRewrite) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *SaveRewrite) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
@@ -0,0 +1,34 @@
+package requests
+
+import (
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/validation"
+)
+
+type Snapshot struct {
+	ID       uint   `form:"id" json:"id" filter:"uint"`
+	Snapshot string `form:"snapshot" json:"snapshot"`
+}
+
+func (r *Snapshot) Authorize(ctx http.Context) error {
+	return nil
+}
+
+func (r *Snapshot) Rules(ctx http.Context) map[string]string {
+	return map[string]string{
+		"id":       "required|exists:websites,id",
+		"snapshot": `required|regex:^[0-9a-f-]+$`,
+	}
+}
+
+func (r *Snapshot) Messages(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *Snapshot) Attributes(ctx http.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (r *Snapshot) PrepareForValidation(ctx http.Context, data validation.Data) error {
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/goravel/framework/facades"
 
 	"panel/app/models"
+	"panel/pkg/tools"
 )
 
 // ProcessTask 处理面板任务
@@ -55,6 +56,7 @@ func (receiver *ProcessTask) Handle(args ...any) error {
 			return nil
 		}
 		facades.Log().Infof("[面板][ProcessTask] 任务%d执行失败: %s", taskID, err.Error())
+		restartPanel()
 		return nil
 	}
 
@@ -65,9 +67,25 @@ func (receiver *ProcessTask) Handle(args ...any) error {
 	}
 
 	facades.Log().Infof("[面板][ProcessTask] 任务%d执行成功", taskID)
+	restartPanel()
 	return nil
 }
 
+// restartPanel 任务要求重启面板时，在任务状态保存后重启
+func restartPanel() {
+	var setting models.Setting
+	if err := facades.Orm().Query().Where("key = ?", models.SettingKeyPanelRestart).Get(&setting); err != nil || setting.ID == 0 {
+		return
+	}
+	if _, err := facades.Orm().Query().Delete(&setting); err != nil {
+		facades.Log().Info("[面板][ProcessTask] 删除重启标记失败: " + err.Error())
+		return
+	}
+
+	facades.Log().Info("[面板][ProcessTask] 任务要求重启面板")
+	tools.RestartPanel()
+}
+
 // haveRunningTask 是否有任务正在执行
 func haveRunningTask() bool {
 	var task models.Task
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"github.com/goravel/framework/support/carbon"
+)
+
+const (
+	BackupStatusCreated   = "created"   // 备份完成，尚未校验
+	BackupStatusVerifying = "verifying" // 正在校验
+	BackupStatusVerified  = "verified"  // 校验通过
+	BackupStatusFailed    = "failed"    // 校验失败
+	BackupStatusError     = "error"     // 备份失败
+)
+
+// Backup 备份记录，每个备份文件一条，删除备份时一并删除
+type Backup struct {
+	ID         uint             `gorm:"primaryKey" json:"id"`
+	Type       string           `gorm:"not null" json:"type"`                            // 备份类型 (website, mysql, postgresql, redis)
+	Source     string           `gorm:"not null" json:"source"`                          // 网站名或数据库名
+	Name       string           `gorm:"not null" json:"name"`                            // 备份文件名
+	Path       string           `gorm:"not null" json:"path"`                            // 本地备份目录
+	Size       int64            `gorm:"not null;default:0" json:"size"`                  // 备份文件大小，单位字节
+	SHA256     string           `gorm:"column:sha256;not null;default:''" json:"sha256"` // 备份文件（加密后）的 SHA-256
+	Duration   int64            `gorm:"not null;default:0" json:"duration"`              // 备份耗时，单位毫秒
+	Storages   []string         `gorm:"type:json;serializer:json" json:"storages"`       // 备份所在的存储，local 为本地备份目录
+	Status     string           `gorm:"not null;default:'created'" json:"status"`        // 状态 (created, verifying, verified, failed, error)
+	Message    string           `gorm:"not null;default:''" json:"message"`              // 备份或校验失败的原因
+	VerifiedAt *carbon.DateTime `gorm:"default:null" json:"verified_at"`                 // 最近一次校验时间
+	CreatedAt  carbon.DateTime  `gorm:"autoCreateTime;column:created_at" json:"created_at"`
+	UpdatedAt  carbon.DateTime  `gorm:"autoUpdateTime;column:updated_at" json:"updated_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"github.com/goravel/framework/support/carbon"
+)
+
+// BackupKey 备份加密密钥
+type BackupKey struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	Name        string          `gorm:"not null" json:"name"`
+	Type        string          `gorm:"not null" json:"type"`                 // 密钥类型 (passphrase, x25519)
+	Fingerprint string          `gorm:"not null;unique" json:"fingerprint"`   // 写入加密后的文件名，用于识别密钥
+	Recipient   string          `gorm:"not null;default:''" json:"recipient"` // age 公钥
+	Identity    string          `gorm:"not null;default:''" json:"-"`         // 使用面板密钥加密的密码或 age 私钥，只保存公钥时为空
+	HasIdentity bool            `gorm:"-" json:"has_identity"`
+	CreatedAt   carbon.DateTime `gorm:"autoCreateTime;column:created_at" json:"created_at"`
+	UpdatedAt   carbon.DateTime `gorm:"autoUpdateTime;column:updated_at" json:"updated_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"github.com/goravel/framework/support/carbon"
+
+	"panel/pkg/storage"
+)
+
+// BackupStorage 远程备份存储，备份创建后上传到所有启用的存储
+type BackupStorage struct {
+	ID        uint            `gorm:"primaryKey" json:"id"`
+	Name      string          `gorm:"not null" json:"name"`
+	Type      string          `gorm:"not null" json:"type"` // 存储类型 (local, s3, sftp)
+	Config    storage.Config  `gorm:"type:json;serializer:json" json:"config"`
+	Enabled   bool            `gorm:"not null" json:"enabled"`
+	CreatedAt carbon.DateTime `gorm:"autoCreateTime;column:created_at" json:"created_at"`
+	UpdatedAt carbon.DateTime `gorm:"autoUpdateTime;column:updated_at" json:"updated_at"`
+}
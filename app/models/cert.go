@@ -5,20 +5,38 @@ import (
 )
 
 type Cert struct {
-	ID        uint            `gorm:"primaryKey" json:"id"`
-	UserID    uint            `gorm:"default:null" json:"user_id"`              // 关联的 ACME 用户 ID
-	WebsiteID *uint           `gorm:"default:null" json:"website_id"`           // 关联的网站 ID
-	DNSID     *uint           `gorm:"column:dns_id;default:null" json:"dns_id"` // 关联的 DNS ID
-	Type      string          `gorm:"not null" json:"type"`                     // 证书类型 (P256, P384, 2048, 4096)
-	Domains   []string        `gorm:"type:json;serializer:json" json:"domains"`
-	AutoRenew bool            `gorm:"default:true" json:"auto_renew"` // 自动续签
-	CertURL   *string         `gorm:"default:null" json:"cert_url"`   // 证书 URL (续签时使用)
-	Cert      string          `gorm:"default:null" json:"cert"`       // 证书内容
-	Key       string          `gorm:"default:null" json:"key"`        // 私钥内容
+	ID        uint     `gorm:"primaryKey" json:"id"`
+	UserID    uint     `gorm:"default:null" json:"user_id"`              // 关联的 ACME 用户 ID
+	WebsiteID *uint    `gorm:"default:null" json:"website_id"`           // 关联的网站 ID (HTTP 验证使用)
+	DNSID     *uint    `gorm:"column:dns_id;default:null" json:"dns_id"` // 关联的 DNS ID
+	Type      string   `gorm:"not null" json:"type"`                     // 证书类型 (P256, P384, 2048, 4096)
+	Domains   []string `gorm:"type:json;serializer:json" json:"domains"`
+	AutoRenew bool     `gorm:"default:true" json:"auto_renew"`            // 自动续签
+	CertURL   *string  `gorm:"default:null" json:"cert_url"`              // 证书 URL (续签时使用)
+	Cert      string   `gorm:"default:null" json:"cert"`                  // 证书内容
+	Key       string   `gorm:"default:null" json:"key"`                   // 私钥内容
+	CSR       string   `gorm:"column:csr;not null;default:''" json:"csr"` // 证书签名请求，商业证书导入前私钥为待签发状态
+
+	CAID      *uint            `gorm:"column:ca_id;default:null" json:"ca_id"` // 签发证书的私有 CA ID
+	Serial    string           `gorm:"not null;default:''" json:"serial"`      // 私有 CA 签发的证书序列号
+	RevokedAt *carbon.DateTime `gorm:"default:null" json:"revoked_at"`         // 吊销时间
+	TaskID    *uint            `gorm:"default:null" json:"task_id"`            // 最近一次签发或续签任务 ID
+
+	RenewAt             *carbon.DateTime `gorm:"default:null" json:"renew_at"`                     // 下次续签时间
+	RenewCheckAt        *carbon.DateTime `gorm:"default:null" json:"renew_check_at"`               // 下次查询 ARI 续签信息的时间
+	RenewFailures       uint             `gorm:"not null;default:0" json:"renew_failures"`         // 连续续签失败次数
+	RenewError          string           `gorm:"not null;default:''" json:"renew_error"`           // 最近一次签发或续签失败的原因
+	RenewExplanationURL string           `gorm:"not null;default:''" json:"renew_explanation_url"` // CA 对续签窗口的说明
+
 	CreatedAt carbon.DateTime `gorm:"autoCreateTime;column:created_at" json:"created_at"`
 	UpdatedAt carbon.DateTime `gorm:"autoUpdateTime;column:updated_at" json:"updated_at"`
 
-	Website *Website  `gorm:"foreignKey:WebsiteID" json:"website"`
-	User    *CertUser `gorm:"foreignKey:UserID" json:"user"`
-	DNS     *CertDNS  `gorm:"foreignKey:DNSID" json:"dns"`
+	Website *Website       `gorm:"foreignKey:WebsiteID" json:"website"`
+	User    *CertUser      `gorm:"foreignKey:UserID" json:"user"`
+	DNS     *CertDNS       `gorm:"foreignKey:DNSID" json:"dns"`
+	CA      *CertAuthority `gorm:"foreignKey:CAID" json:"ca"`
+	Task    *Task          `gorm:"foreignKey:TaskID" json:"task"`
+
+	Deployments []*CertDeployment `gorm:"foreignKey:CertID" json:"deployments"`
+	Targets     []*CertTarget     `gorm:"foreignKey:CertID" json:"targets"`
 }
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"github.com/goravel/framework/support/carbon"
+
+	"panel/pkg/acme"
+)
+
+// CertAuthority 私有 CA，私钥使用 APP_KEY 加密存储
+type CertAuthority struct {
+	ID               uint              `gorm:"primaryKey" json:"id"`
+	Name             string            `gorm:"not null" json:"name"`
+	Type             string            `gorm:"not null" json:"type"` // 密钥类型 (P256, P384, 2048, 4096)
+	RootCert         string            `gorm:"not null" json:"root_cert"`
+	RootKey          string            `gorm:"not null" json:"-"`
+	IntermediateCert string            `gorm:"not null" json:"intermediate_cert"`
+	IntermediateKey  string            `gorm:"not null" json:"-"`
+	CRLURL           string            `gorm:"column:crl_url;not null;default:''" json:"crl_url"` // 写入签发证书的 CRL 分发地址
+	CRL              string            `gorm:"column:crl;not null;default:''" json:"-"`
+	CRLNumber        int64             `gorm:"column:crl_number;not null;default:0" json:"crl_number"`
+	CRLUpdatedAt     *carbon.DateTime  `gorm:"column:crl_updated_at;default:null" json:"crl_updated_at"`
+	Revocations      []acme.Revocation `gorm:"type:json;serializer:json" json:"revocations"`
+	CreatedAt        carbon.DateTime   `gorm:"autoCreateTime;column:created_at" json:"created_at"`
+	UpdatedAt        carbon.DateTime   `gorm:"autoUpdateTime;column:updated_at" json:"updated_at"`
+
+	Certs []*Cert `gorm:"foreignKey:CAID" json:"-"`
+}
+
+func (CertAuthority) TableName() string {
+	return "cert_authorities"
+}
@@ -0,0 +1,16 @@
+package models
+
+import "github.com/goravel/framework/support/carbon"
+
+type CertDeployment struct {
+	ID          uint             `gorm:"primaryKey" json:"id"`
+	CertID      uint             `gorm:"not null" json:"cert_id"`
+	WebsiteID   uint             `gorm:"not null" json:"website_id"`
+	Fingerprint string           `gorm:"not null;default:''" json:"fingerprint"` // 最近一次部署的证书 SHA256 指纹
+	DeployedAt  *carbon.DateTime `gorm:"default:null" json:"deployed_at"`        // 最近一次部署时间
+	CreatedAt   carbon.DateTime  `gorm:"autoCreateTime;column:created_at" json:"created_at"`
+	UpdatedAt   carbon.DateTime  `gorm:"autoUpdateTime;column:updated_at" json:"updated_at"`
+
+	Cert    *Cert    `gorm:"foreignKey:CertID" json:"cert"`
+	Website *Website `gorm:"foreignKey:WebsiteID" json:"website"`
+}
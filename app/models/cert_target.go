@@ -0,0 +1,32 @@
+package models
+
+import "github.com/goravel/framework/support/carbon"
+
+const (
+	CertTargetStatusSuccess = "success"
+	CertTargetStatusFailed  = "failed"
+)
+
+// CertTarget 证书部署目标，将证书写入文件并执行部署后操作
+type CertTarget struct {
+	ID            uint             `gorm:"primaryKey" json:"id"`
+	CertID        uint             `gorm:"not null;index" json:"cert_id"`
+	Name          string           `gorm:"not null" json:"name"`
+	CertPath      string           `gorm:"not null" json:"cert_path"`                 // 站点证书路径
+	KeyPath       string           `gorm:"not null" json:"key_path"`                  // 私钥路径
+	FullchainPath string           `gorm:"not null;default:''" json:"fullchain_path"` // 完整证书链路径，可选
+	PfxPath       string           `gorm:"not null;default:''" json:"pfx_path"`       // PFX 路径，可选
+	PfxPassword   string           `gorm:"not null;default:''" json:"-"`              // PFX 密码，不返回给前端
+	Mode          string           `gorm:"not null;default:'0600'" json:"mode"`       // 文件权限
+	Owner         string           `gorm:"not null;default:''" json:"owner"`          // 文件所有者，格式为 user 或 user:group
+	Service       string           `gorm:"not null;default:''" json:"service"`        // 部署后重载的服务
+	Command       string           `gorm:"not null;default:''" json:"command"`        // 部署后执行的命令
+	Status        string           `gorm:"not null;default:''" json:"status"`         // 最近一次部署结果
+	Error         string           `gorm:"not null;default:''" json:"error"`          // 最近一次部署失败原因
+	Fingerprint   string           `gorm:"not null;default:''" json:"fingerprint"`
+	DeployedAt    *carbon.DateTime `gorm:"default:null" json:"deployed_at"`
+	CreatedAt     carbon.DateTime  `gorm:"autoCreateTime;column:created_at" json:"created_at"`
+	UpdatedAt     carbon.DateTime  `gorm:"autoUpdateTime;column:updated_at" json:"updated_at"`
+
+	Cert *Cert `gorm:"foreignKey:CertID" json:"cert"`
+}
@@ -12,6 +12,7 @@ type CertUser struct {
 	HmacEncoded *string         `gorm:"default:null" json:"hmac_encoded"`
 	PrivateKey  string          `gorm:"not null" json:"private_key"`
 	KeyType     string          `gorm:"not null" json:"key_type"`
+	Status      string          `gorm:"not null;default:'valid'" json:"status"` // CA 上的账号状态 (valid, deactivated)
 	CreatedAt   carbon.DateTime `gorm:"autoCreateTime;column:created_at" json:"created_at"`
 	UpdatedAt   carbon.DateTime `gorm:"autoUpdateTime;column:updated_at" json:"updated_at"`
 
@@ -0,0 +1,19 @@
+package models
+
+import "github.com/goravel/framework/support/carbon"
+
+type PhpPool struct {
+	ID              uint              `gorm:"primaryKey" json:"id"`
+	WebsiteID       uint              `gorm:"not null;unique" json:"website_id"`
+	User            string            `gorm:"not null" json:"user"`                        // 运行用户
+	Pm              string            `gorm:"not null;default:'dynamic'" json:"pm"`        // 进程管理方式 (static, dynamic, ondemand)
+	MaxChildren     int               `gorm:"not null;default:10" json:"max_children"`     // 最大子进程数
+	StartServers    int               `gorm:"not null;default:2" json:"start_servers"`     // 启动时的子进程数
+	MinSpareServers int               `gorm:"not null;default:1" json:"min_spare_servers"` // 最小空闲进程数
+	MaxSpareServers int               `gorm:"not null;default:3" json:"max_spare_servers"` // 最大空闲进程数
+	Ini             map[string]string `gorm:"type:json;serializer:json" json:"ini"`        // 覆盖的 php.ini 配置
+	CreatedAt       carbon.DateTime   `gorm:"autoCreateTime;column:created_at" json:"created_at"`
+	UpdatedAt       carbon.DateTime   `gorm:"autoUpdateTime;column:updated_at" json:"updated_at"`
+
+	Website *Website `gorm:"foreignKey:WebsiteID" json:"website"`
+}
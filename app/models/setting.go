@@ -3,17 +3,21 @@ package models
 import "github.com/goravel/framework/support/carbon"
 
 const (
-	SettingKeyName              = "name"
-	SettingKeyVersion           = "version"
-	SettingKeyMonitor           = "monitor"
-	SettingKeyMonitorDays       = "monitor_days"
-	SettingKeyBackupPath        = "backup_path"
-	SettingKeyWebsitePath       = "website_path"
-	SettingKeyMysqlRootPassword = "mysql_root_password"
-	SettingKeySshHost           = "ssh_host"
-	SettingKeySshPort           = "ssh_port"
-	SettingKeySshUser           = "ssh_user"
-	SettingKeySshPassword       = "ssh_password"
+	SettingKeyName               = "name"
+	SettingKeyVersion            = "version"
+	SettingKeyMonitor            = "monitor"
+	SettingKeyMonitorDays        = "monitor_days"
+	SettingKeyBackupPath         = "backup_path"
+	SettingKeyWebsitePath        = "website_path"
+	SettingKeyMysqlRootPassword  = "mysql_root_password"
+	SettingKeySshHost            = "ssh_host"
+	SettingKeySshPort            = "ssh_port"
+	SettingKeySshUser            = "ssh_user"
+	SettingKeySshPassword        = "ssh_password"
+	SettingKeyPanelCertID        = "panel_cert_id"
+	SettingKeyPanelHTTPSRedirect = "panel_https_redirect"
+	SettingKeyBackupKeyID        = "backup_key_id"
+	SettingKeyPanelRestart       = "panel_restart" // 面板需要在当前任务结束后重启
 )
 
 type Setting struct {
@@ -12,6 +12,9 @@ type Website struct {
 	Php       int             `gorm:"default:0;not null;index" json:"php"`
 	Ssl       bool            `gorm:"default:false;not null;index" json:"ssl"`
 	Remark    string          `gorm:"default:''" json:"remark"`
+	DbType    string          `gorm:"not null;default:''" json:"db_type"` // 关联的数据库类型 (mysql, postgresql)，为空时没有关联数据库
+	DbName    string          `gorm:"not null;default:''" json:"db_name"`
+	DbUser    string          `gorm:"not null;default:''" json:"db_user"`
 	CreatedAt carbon.DateTime `gorm:"autoCreateTime;column:created_at" json:"created_at"`
 	UpdatedAt carbon.DateTime `gorm:"autoUpdateTime;column:updated_at" json:"updated_at"`
 
@@ -0,0 +1,20 @@
+package models
+
+import "github.com/goravel/framework/support/carbon"
+
+type WebsiteDeploy struct {
+	ID           uint            `gorm:"primaryKey" json:"id"`
+	WebsiteID    uint            `gorm:"not null;unique" json:"website_id"`
+	Repo         string          `gorm:"not null" json:"repo"`                    // 仓库地址，支持本地裸仓库
+	Branch       string          `gorm:"not null" json:"branch"`                  // 部署分支
+	DeployKey    string          `gorm:"default:''" json:"-"`                     // 部署私钥
+	HasDeployKey bool            `gorm:"-" json:"has_deploy_key"`                 // 是否已配置部署私钥，私钥不返回
+	PreDeploy    string          `gorm:"default:''" json:"pre_deploy"`            // 部署前脚本（在新版本目录中执行）
+	PostDeploy   string          `gorm:"default:''" json:"post_deploy"`           // 部署后脚本（在当前版本目录中执行）
+	KeepReleases int             `gorm:"not null;default:5" json:"keep_releases"` // 保留的历史版本数
+	WebhookToken string          `gorm:"not null;unique" json:"webhook_token"`
+	CreatedAt    carbon.DateTime `gorm:"autoCreateTime;column:created_at" json:"created_at"`
+	UpdatedAt    carbon.DateTime `gorm:"autoUpdateTime;column:updated_at" json:"updated_at"`
+
+	Website *Website `gorm:"foreignKey:WebsiteID" json:"website"`
+}
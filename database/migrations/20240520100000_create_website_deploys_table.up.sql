@@ -0,0 +1,17 @@
+CREATE TABLE website_deploys
+(
+    id            integer PRIMARY KEY AUTOINCREMENT NOT NULL,
+    website_id    integer                           NOT NULL,
+    repo          varchar(255)                      NOT NULL,
+    branch        varchar(255)                      NOT NULL,
+    deploy_key    text         DEFAULT '',
+    pre_deploy    text         DEFAULT '',
+    post_deploy   text         DEFAULT '',
+    keep_releases integer      DEFAULT 5            NOT NULL,
+    webhook_token varchar(255)                      NOT NULL,
+    created_at    datetime                          NOT NULL,
+    updated_at    datetime                          NOT NULL
+);
+
+CREATE UNIQUE INDEX website_deploys_website_id_unique ON website_deploys (website_id);
+CREATE UNIQUE INDEX website_deploys_webhook_token_unique ON website_deploys (webhook_token);
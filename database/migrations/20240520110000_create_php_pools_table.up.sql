@@ -0,0 +1,16 @@
+CREATE TABLE php_pools
+(
+    id                integer PRIMARY KEY AUTOINCREMENT NOT NULL,
+    website_id        integer                           NOT NULL,
+    user              varchar(255)                      NOT NULL,
+    pm                varchar(255) DEFAULT 'dynamic'    NOT NULL,
+    max_children      integer      DEFAULT 10           NOT NULL,
+    start_servers     integer      DEFAULT 2            NOT NULL,
+    min_spare_servers integer      DEFAULT 1            NOT NULL,
+    max_spare_servers integer      DEFAULT 3            NOT NULL,
+    ini               text         DEFAULT '{}'         NOT NULL,
+    created_at        datetime                          NOT NULL,
+    updated_at        datetime                          NOT NULL
+);
+
+CREATE UNIQUE INDEX php_pools_website_id_unique ON php_pools (website_id);
@@ -0,0 +1,17 @@
+CREATE TABLE cert_deployments
+(
+    id          integer PRIMARY KEY AUTOINCREMENT NOT NULL,
+    cert_id     integer                           NOT NULL,
+    website_id  integer                           NOT NULL,
+    fingerprint varchar(255) DEFAULT ''           NOT NULL,
+    deployed_at datetime     DEFAULT NULL,
+    created_at  datetime                          NOT NULL,
+    updated_at  datetime                          NOT NULL
+);
+
+CREATE UNIQUE INDEX cert_deployments_cert_id_website_id_unique ON cert_deployments (cert_id, website_id);
+
+INSERT INTO cert_deployments (cert_id, website_id, created_at, updated_at)
+SELECT id, website_id, created_at, updated_at
+FROM certs
+WHERE website_id IS NOT NULL;
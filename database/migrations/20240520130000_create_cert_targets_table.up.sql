@@ -0,0 +1,23 @@
+CREATE TABLE cert_targets
+(
+    id               integer PRIMARY KEY AUTOINCREMENT NOT NULL,
+    cert_id          integer                           NOT NULL,
+    name             varchar(255)                      NOT NULL,
+    cert_path        varchar(255)                      NOT NULL,
+    key_path         varchar(255)                      NOT NULL,
+    fullchain_path   varchar(255) DEFAULT ''           NOT NULL,
+    pfx_path         varchar(255) DEFAULT ''           NOT NULL,
+    pfx_password     varchar(255) DEFAULT ''           NOT NULL,
+    mode             varchar(255) DEFAULT '0600'       NOT NULL,
+    owner            varchar(255) DEFAULT ''           NOT NULL,
+    service          varchar(255) DEFAULT ''           NOT NULL,
+    command          text         DEFAULT ''           NOT NULL,
+    status           varchar(255) DEFAULT ''           NOT NULL,
+    error            text         DEFAULT ''           NOT NULL,
+    fingerprint      varchar(255) DEFAULT ''           NOT NULL,
+    deployed_at      datetime     DEFAULT NULL,
+    created_at       datetime                          NOT NULL,
+    updated_at       datetime                          NOT NULL
+);
+
+CREATE INDEX cert_targets_cert_id_index ON cert_targets (cert_id);
@@ -0,0 +1,5 @@
+ALTER TABLE certs DROP COLUMN renew_at;
+ALTER TABLE certs DROP COLUMN renew_check_at;
+ALTER TABLE certs DROP COLUMN renew_failures;
+ALTER TABLE certs DROP COLUMN renew_error;
+ALTER TABLE certs DROP COLUMN renew_explanation_url;
@@ -0,0 +1,5 @@
+ALTER TABLE certs ADD COLUMN renew_at datetime DEFAULT NULL;
+ALTER TABLE certs ADD COLUMN renew_check_at datetime DEFAULT NULL;
+ALTER TABLE certs ADD COLUMN renew_failures integer DEFAULT 0 NOT NULL;
+ALTER TABLE certs ADD COLUMN renew_error text DEFAULT '' NOT NULL;
+ALTER TABLE certs ADD COLUMN renew_explanation_url varchar(255) DEFAULT '' NOT NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS certs_ca_id_index;
+ALTER TABLE certs DROP COLUMN ca_id;
+ALTER TABLE certs DROP COLUMN serial;
+ALTER TABLE certs DROP COLUMN revoked_at;
+DROP TABLE IF EXISTS cert_authorities;
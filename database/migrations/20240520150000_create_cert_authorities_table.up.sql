@@ -0,0 +1,23 @@
+CREATE TABLE cert_authorities
+(
+    id                integer PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name              varchar(255)                      NOT NULL,
+    type              varchar(255)                      NOT NULL,
+    root_cert         text                              NOT NULL,
+    root_key          text                              NOT NULL,
+    intermediate_cert text                              NOT NULL,
+    intermediate_key  text                              NOT NULL,
+    crl_url           varchar(255) DEFAULT ''           NOT NULL,
+    crl               text         DEFAULT ''           NOT NULL,
+    crl_number        integer      DEFAULT 0            NOT NULL,
+    crl_updated_at    datetime     DEFAULT NULL,
+    revocations       text         DEFAULT '[]'         NOT NULL,
+    created_at        datetime                          NOT NULL,
+    updated_at        datetime                          NOT NULL
+);
+
+ALTER TABLE certs ADD COLUMN ca_id integer DEFAULT NULL;
+ALTER TABLE certs ADD COLUMN serial varchar(255) DEFAULT '' NOT NULL;
+ALTER TABLE certs ADD COLUMN revoked_at datetime DEFAULT NULL;
+
+CREATE INDEX certs_ca_id_index ON certs (ca_id);
@@ -0,0 +1 @@
+ALTER TABLE certs DROP COLUMN task_id;
@@ -0,0 +1 @@
+ALTER TABLE certs ADD COLUMN task_id integer DEFAULT NULL;
@@ -0,0 +1 @@
+ALTER TABLE certs ADD COLUMN csr text NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE cert_users DROP COLUMN status;
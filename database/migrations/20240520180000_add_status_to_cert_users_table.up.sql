@@ -0,0 +1 @@
+ALTER TABLE cert_users ADD COLUMN status text NOT NULL DEFAULT 'valid';
@@ -0,0 +1,10 @@
+CREATE TABLE backup_storages
+(
+    id         integer PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name       varchar(255)                      NOT NULL,
+    type       varchar(255)                      NOT NULL,
+    config     text         DEFAULT '{}'         NOT NULL,
+    enabled    boolean      DEFAULT 1            NOT NULL,
+    created_at datetime                          NOT NULL,
+    updated_at datetime                          NOT NULL
+);
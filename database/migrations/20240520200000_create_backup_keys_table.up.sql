@@ -0,0 +1,12 @@
+CREATE TABLE backup_keys
+(
+    id          integer PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name        varchar(255)                      NOT NULL,
+    type        varchar(255)                      NOT NULL,
+    fingerprint varchar(255)                      NOT NULL,
+    recipient   text         DEFAULT ''           NOT NULL,
+    identity    text         DEFAULT ''           NOT NULL,
+    created_at  datetime                          NOT NULL,
+    updated_at  datetime                          NOT NULL
+);
+CREATE UNIQUE INDEX backup_keys_fingerprint_unique ON backup_keys (fingerprint);
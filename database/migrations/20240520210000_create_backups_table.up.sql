@@ -0,0 +1,19 @@
+CREATE TABLE backups
+(
+    id          integer PRIMARY KEY AUTOINCREMENT NOT NULL,
+    type        varchar(255)                      NOT NULL,
+    source      varchar(255)                      NOT NULL,
+    name        varchar(255)                      NOT NULL,
+    path        varchar(255)                      NOT NULL,
+    size        integer      DEFAULT 0            NOT NULL,
+    sha256      varchar(255) DEFAULT ''           NOT NULL,
+    duration    integer      DEFAULT 0            NOT NULL,
+    storages    text         DEFAULT '[]'         NOT NULL,
+    status      varchar(255) DEFAULT 'created'    NOT NULL,
+    message     text         DEFAULT ''           NOT NULL,
+    verified_at datetime     DEFAULT NULL,
+    created_at  datetime                          NOT NULL,
+    updated_at  datetime                          NOT NULL
+);
+CREATE UNIQUE INDEX backups_type_name_unique ON backups (type, name);
+CREATE INDEX backups_source_index ON backups (type, source);
@@ -0,0 +1,3 @@
+ALTER TABLE websites DROP COLUMN db_type;
+ALTER TABLE websites DROP COLUMN db_name;
+ALTER TABLE websites DROP COLUMN db_user;
@@ -0,0 +1,3 @@
+ALTER TABLE websites ADD COLUMN db_type varchar(255) DEFAULT '' NOT NULL;
+ALTER TABLE websites ADD COLUMN db_name varchar(255) DEFAULT '' NOT NULL;
+ALTER TABLE websites ADD COLUMN db_user varchar(255) DEFAULT '' NOT NULL;
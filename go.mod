@@ -3,6 +3,8 @@ module panel
 go 1.22
 
 require (
+	filippo.io/age v1.1.1
+	github.com/aws/aws-sdk-go v1.49.6
 	github.com/docker/docker v26.1.3+incompatible
 	github.com/docker/go-connections v0.5.0
 	github.com/go-resty/resty/v2 v2.13.1
@@ -17,7 +19,9 @@ require (
 	github.com/libdns/libdns v0.2.2
 	github.com/mholt/acmez/v2 v2.0.1
 	github.com/mholt/archiver/v3 v3.5.1
+	github.com/miekg/dns v1.1.59
 	github.com/mojocn/base64Captcha v1.3.6
+	github.com/pkg/sftp v1.13.6
 	github.com/shirou/gopsutil v3.21.11+incompatible
 	github.com/spf13/cast v1.6.0
 	github.com/stretchr/testify v1.9.0
@@ -26,6 +30,7 @@ require (
 	go.uber.org/zap v1.27.0
 	golang.org/x/crypto v0.23.0
 	golang.org/x/net v0.25.0
+	software.sslmate.com/src/go-pkcs12 v0.4.0
 )
 
 require (
@@ -47,7 +52,6 @@ require (
 	github.com/RichardKnop/logging v0.0.0-20190827224416-1a693bdd4fae // indirect
 	github.com/RichardKnop/machinery/v2 v2.0.13 // indirect
 	github.com/andybalholm/brotli v1.1.0 // indirect
-	github.com/aws/aws-sdk-go v1.49.6 // indirect
 	github.com/bytedance/sonic v1.11.6 // indirect
 	github.com/bytedance/sonic/loader v0.1.1 // indirect
 	github.com/cenkalti/backoff/v4 v4.2.1 // indirect
@@ -120,6 +124,7 @@ require (
 	github.com/klauspost/compress v1.17.7 // indirect
 	github.com/klauspost/cpuid/v2 v2.2.7 // indirect
 	github.com/klauspost/pgzip v1.2.6 // indirect
+	github.com/kr/fs v0.1.0 // indirect
 	github.com/leodido/go-urn v1.2.4 // indirect
 	github.com/lib/pq v1.10.9 // indirect
 	github.com/magiconair/properties v1.8.7 // indirect
@@ -1,20 +1,82 @@
 package internal
 
-import "panel/app/models"
+import (
+	"time"
+
+	requests "panel/app/http/requests/backup"
+	"panel/app/models"
+	"panel/pkg/dedup"
+	"panel/pkg/retention"
+)
+
+// 备份类型，同时也是备份在本地和远程存储中的目录名
+const (
+	BackupTypeWebsite    = "website"
+	BackupTypeMysql      = "mysql"
+	BackupTypePostgresql = "postgresql"
+	BackupTypeRedis      = "redis"
+)
 
 type Backup interface {
 	WebsiteList() ([]BackupFile, error)
-	WebSiteBackup(website models.Website) error
+	WebSiteBackup(website models.Website, storages []uint) error
 	WebsiteRestore(website models.Website, backupFile string) error
+	WebsiteSnapshot(website models.Website) (*dedup.Snapshot, error)
+	WebsiteSnapshotList(website models.Website) ([]dedup.Snapshot, error)
+	WebsiteSnapshotRestore(website models.Website, ID string) error
+	WebsiteSnapshotDelete(website models.Website, ID string) error
+	SnapshotPrune() (dedup.PruneStats, error)
 	MysqlList() ([]BackupFile, error)
-	MysqlBackup(database string) error
+	MysqlBackup(database string, storages []uint) error
 	MysqlRestore(database string, backupFile string) error
 	PostgresqlList() ([]BackupFile, error)
-	PostgresqlBackup(database string) error
+	PostgresqlBackup(database string, storages []uint) error
 	PostgresqlRestore(database string, backupFile string) error
+	RedisList() ([]BackupFile, error)
+	RedisBackup(storages []uint) error
+	RedisDump(file string) error
+	RedisRestore(backupFile string) error
+	Delete(backupType, name string) error
+	Upload(backupType, file string, storages []uint) ([]string, error)
+	Record(backupType, source, file string, duration time.Duration, storages []string) (models.Backup, error)
+	RecordFailure(backupType, source, file string, duration time.Duration, cause error) error
+	Catalog(backupType, source string, page, limit int) ([]models.Backup, int64, error)
+	Verify(ID uint) error
+	VerifyTask(ID uint) error
+	Prune(backupType, dir, name string, policy retention.Policy, dryRun bool) ([]BackupPrune, error)
+	StorageList() ([]models.BackupStorage, error)
+	StorageShow(ID uint) (models.BackupStorage, error)
+	StorageStore(request requests.StorageStore) error
+	StorageUpdate(request requests.StorageUpdate) error
+	StorageDestroy(ID uint) error
+	StorageHostKey(request requests.StorageHostKey) (StorageHostKey, error)
+	Encrypt(file string) (string, error)
+	Encryption() uint
+	UpdateEncryption(keyID uint) error
+	KeyList() ([]models.BackupKey, error)
+	KeyShow(ID uint) (models.BackupKey, error)
+	KeyStore(request requests.KeyStore) (models.BackupKey, error)
+	KeyDestroy(ID uint) error
 }
 
 type BackupFile struct {
-	Name string `json:"name"`
-	Size string `json:"size"`
+	ID        uint     `json:"id"` // 备份记录 ID，没有记录时为 0
+	Name      string   `json:"name"`
+	Size      string   `json:"size"`
+	Storages  []string `json:"storages"` // 备份所在的存储，local 为本地备份目录
+	Encrypted bool     `json:"encrypted"`
+	Key       string   `json:"key"`    // 加密使用的密钥名称，密钥已删除时为指纹
+	Status    string   `json:"status"` // 备份记录的状态，没有记录时为空
+}
+
+// BackupPrune 按保留策略清理备份的结果
+type BackupPrune struct {
+	retention.Decision
+	Storages []string `json:"storages"` // 备份所在的存储，local 为本地备份目录
+}
+
+// StorageHostKey SFTP 服务器的主机公钥
+type StorageHostKey struct {
+	HostKey     string `json:"host_key"`
+	Fingerprint string `json:"fingerprint"`
 }
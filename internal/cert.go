@@ -1,6 +1,10 @@
 package internal
 
 import (
+	"io"
+
+	"github.com/goravel/framework/support/carbon"
+
 	requests "panel/app/http/requests/cert"
 	"panel/app/models"
 	"panel/pkg/acme"
@@ -11,6 +15,8 @@ type Cert interface {
 	UserUpdate(request requests.UserUpdate) error
 	UserShow(ID uint) (models.CertUser, error)
 	UserDestroy(ID uint) error
+	UserDeactivate(ID uint) error
+	UserAccount(ID uint) (CertAccount, error)
 	DNSStore(request requests.DNSStore) error
 	DNSUpdate(request requests.DNSUpdate) error
 	DNSShow(ID uint) (models.CertDNS, error)
@@ -19,9 +25,66 @@ type Cert interface {
 	CertUpdate(request requests.CertUpdate) error
 	CertShow(ID uint) (models.Cert, error)
 	CertDestroy(ID uint) error
-	ObtainAuto(ID uint) (acme.Certificate, error)
+	CSRStore(request requests.CSRStore) (models.Cert, error)
+	Import(request requests.CertImport) error
+	Download(request requests.CertDownload) (string, acme.Exported, error)
+	ObtainAuto(ID uint, log io.Writer) (acme.Certificate, error)
 	ObtainManual(ID uint) (acme.Certificate, error)
 	ManualDNS(ID uint) ([]acme.DNSRecord, error)
-	Renew(ID uint) (acme.Certificate, error)
+	Renew(ID uint, log io.Writer) (acme.Certificate, error)
+	ObtainTask(ID uint) (models.Task, error)
+	IsPanelCert(cert models.Cert) bool
+	RenewTask(ID uint) (models.Task, error)
+	TaskStatus(ID uint) (CertTaskStatus, error)
+	RefreshRenewal(ID uint) error
+	Inspect(ID uint) (acme.Inspection, error)
 	Deploy(ID, WebsiteID uint) error
+	Undeploy(ID, WebsiteID uint) error
+	Deployments(ID uint) ([]CertDeploymentStatus, error)
+	TargetList(ID uint) ([]models.CertTarget, error)
+	TargetStore(request requests.TargetStore) error
+	TargetUpdate(request requests.TargetUpdate) error
+	TargetDestroy(ID uint) error
+	TargetDeploy(ID uint) error
+}
+
+// CertDeploymentStatus 证书部署状态
+type CertDeploymentStatus struct {
+	WebsiteID         uint             `json:"website_id"`
+	WebsiteName       string           `json:"website_name"`
+	Ssl               bool             `json:"ssl"`                // 网站是否已开启 HTTPS
+	Fingerprint       string           `json:"fingerprint"`        // 最近一次部署的证书指纹
+	ActualFingerprint string           `json:"actual_fingerprint"` // 网站当前使用的证书指纹
+	Current           bool             `json:"current"`            // 网站当前使用的是否为最新证书
+	DeployedAt        *carbon.DateTime `json:"deployed_at"`
+	Error             string           `json:"error"`
+}
+
+// CertTaskStatus 证书签发或续签任务状态
+type CertTaskStatus struct {
+	Task  *models.Task `json:"task"`
+	Log   string       `json:"log"`   // 任务日志，记录签发的每个步骤
+	Error string       `json:"error"` // 最近一次签发或续签失败的原因
+}
+
+// CertAccount ACME 账号在 CA 上的信息
+type CertAccount struct {
+	Status    string            `json:"status"`     // 账号状态 (valid, deactivated, revoked)
+	Location  string            `json:"location"`   // 账号 URL
+	Contact   []string          `json:"contact"`    // CA 上登记的联系方式
+	OrdersURL string            `json:"orders_url"` // 订单列表地址，部分 CA 不提供
+	Error     string            `json:"error"`      // 获取 CA 账号信息失败的原因
+	Certs     []CertAccountCert `json:"certs"`
+}
+
+// CertAccountCert ACME 账号下的证书及其签发记录
+type CertAccountCert struct {
+	ID         uint             `json:"id"`
+	Domains    []string         `json:"domains"`
+	CertURL    *string          `json:"cert_url"` // 证书 URL，为空表示尚未签发成功
+	NotAfter   *carbon.DateTime `json:"not_after"`
+	AutoRenew  bool             `json:"auto_renew"`
+	RenewAt    *carbon.DateTime `json:"renew_at"`
+	RenewError string           `json:"renew_error"`
+	Task       *models.Task     `json:"task"` // 最近一次签发或续签任务
 }
@@ -0,0 +1,17 @@
+package internal
+
+import (
+	requests "panel/app/http/requests/cert"
+	"panel/app/models"
+)
+
+type CertAuthority interface {
+	Store(request requests.CAStore) (models.CertAuthority, error)
+	Destroy(ID uint) error
+	Issue(request requests.CAIssue) (models.Cert, error)
+	Revoke(certID uint) error
+	Root(ID uint) ([]byte, error)
+	CRL(ID uint) ([]byte, error)
+	CRLPEM(ID uint) ([]byte, error)
+	RefreshCRL() error
+}
@@ -0,0 +1,25 @@
+package internal
+
+import (
+	"io"
+
+	requests "panel/app/http/requests/website"
+	"panel/app/models"
+)
+
+type Deploy interface {
+	GetConfig(websiteID uint) (models.WebsiteDeploy, error)
+	SaveConfig(request requests.DeployConfig) error
+	ResetToken(websiteID uint) (string, error)
+	Trigger(websiteID uint) error
+	TriggerByToken(token string) error
+	Run(websiteID uint, log io.Writer) error
+	Releases(websiteID uint) ([]DeployRelease, error)
+	Rollback(websiteID uint, release string) error
+}
+
+// DeployRelease 部署版本
+type DeployRelease struct {
+	Name    string `json:"name"`
+	Current bool   `json:"current"`
+}
@@ -0,0 +1,22 @@
+package internal
+
+import (
+	"context"
+
+	requests "panel/app/http/requests/log"
+	"panel/pkg/logreader"
+)
+
+type Log interface {
+	Sources() ([]LogSource, error)
+	Read(request requests.Read) (logreader.Page, error)
+	Stream(ctx context.Context, request requests.Stream, handler func([]logreader.Line) error) error
+}
+
+// LogSource 日志来源
+type LogSource struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	Type string `json:"type"` // website, service, panel
+	Path string `json:"path"`
+}
@@ -2,63 +2,55 @@
 package services
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"io"
+	"net"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/goravel/framework/facades"
 	"github.com/goravel/framework/support/carbon"
+	"github.com/spf13/cast"
 
+	requests "panel/app/http/requests/backup"
 	"panel/app/models"
 	"panel/internal"
+	"panel/pkg/dedup"
+	"panel/pkg/encryption"
+	"panel/pkg/retention"
+	"panel/pkg/ssh"
+	"panel/pkg/storage"
 	"panel/pkg/tools"
 )
 
 type BackupImpl struct {
 	setting internal.Setting
+	task    internal.Task
 }
 
 func NewBackupImpl() *BackupImpl {
 	return &BackupImpl{
 		setting: NewSettingImpl(),
+		task:    NewTaskImpl(),
 	}
 }
 
 // WebsiteList 网站备份列表
 func (s *BackupImpl) WebsiteList() ([]internal.BackupFile, error) {
-	backupPath := s.setting.Get(models.SettingKeyBackupPath)
-	if len(backupPath) == 0 {
-		return []internal.BackupFile{}, nil
-	}
-
-	backupPath += "/website"
-	if !tools.Exists(backupPath) {
-		if err := tools.Mkdir(backupPath, 0644); err != nil {
-			return []internal.BackupFile{}, err
-		}
-	}
-
-	files, err := os.ReadDir(backupPath)
-	if err != nil {
-		return []internal.BackupFile{}, err
-	}
-	var backupList []internal.BackupFile
-	for _, file := range files {
-		info, err := file.Info()
-		if err != nil {
-			continue
-		}
-		backupList = append(backupList, internal.BackupFile{
-			Name: file.Name(),
-			Size: tools.FormatBytes(float64(info.Size())),
-		})
-	}
-
-	return backupList, nil
+	return s.list(internal.BackupTypeWebsite)
 }
 
 // WebSiteBackup 网站备份
-func (s *BackupImpl) WebSiteBackup(website models.Website) error {
+func (s *BackupImpl) WebSiteBackup(website models.Website, storages []uint) error {
 	backupPath := s.setting.Get(models.SettingKeyBackupPath)
 	if len(backupPath) == 0 {
 		return errors.New("未正确配置备份路径")
@@ -71,32 +63,22 @@ func (s *BackupImpl) WebSiteBackup(website models.Website) error {
 		}
 	}
 
+	start := time.Now()
 	backupFile := backupPath + "/" + website.Name + "_" + carbon.Now().ToShortDateTimeString() + ".zip"
 	if _, err := tools.Exec(`cd '` + website.Path + `' && zip -r '` + backupFile + `' .`); err != nil {
-		return err
+		return s.fail(internal.BackupTypeWebsite, website.Name, backupFile, start, err)
 	}
 
-	return nil
+	return s.finish(internal.BackupTypeWebsite, website.Name, backupFile, start, storages)
 }
 
 // WebsiteRestore 网站恢复
 func (s *BackupImpl) WebsiteRestore(website models.Website, backupFile string) error {
-	backupPath := s.setting.Get(models.SettingKeyBackupPath)
-	if len(backupPath) == 0 {
-		return errors.New("未正确配置备份路径")
-	}
-
-	backupPath += "/website"
-	if !tools.Exists(backupPath) {
-		if err := tools.Mkdir(backupPath, 0644); err != nil {
-			return err
-		}
-	}
-
-	backupFile = backupPath + "/" + backupFile
-	if !tools.Exists(backupFile) {
-		return errors.New("备份文件不存在")
+	backupFile, cleanup, err := s.open(internal.BackupTypeWebsite, backupFile)
+	if err != nil {
+		return err
 	}
+	defer cleanup()
 
 	if err := tools.Remove(website.Path); err != nil {
 		return err
@@ -116,39 +98,11 @@ func (s *BackupImpl) WebsiteRestore(website models.Website, backupFile string) e
 
 // MysqlList MySQL备份列表
 func (s *BackupImpl) MysqlList() ([]internal.BackupFile, error) {
-	backupPath := s.setting.Get(models.SettingKeyBackupPath)
-	if len(backupPath) == 0 {
-		return []internal.BackupFile{}, nil
-	}
-
-	backupPath += "/mysql"
-	if !tools.Exists(backupPath) {
-		if err := tools.Mkdir(backupPath, 0644); err != nil {
-			return []internal.BackupFile{}, err
-		}
-	}
-
-	files, err := os.ReadDir(backupPath)
-	if err != nil {
-		return []internal.BackupFile{}, err
-	}
-	var backupList []internal.BackupFile
-	for _, file := range files {
-		info, err := file.Info()
-		if err != nil {
-			continue
-		}
-		backupList = append(backupList, internal.BackupFile{
-			Name: file.Name(),
-			Size: tools.FormatBytes(float64(info.Size())),
-		})
-	}
-
-	return backupList, nil
+	return s.list(internal.BackupTypeMysql)
 }
 
 // MysqlBackup MySQL备份
-func (s *BackupImpl) MysqlBackup(database string) error {
+func (s *BackupImpl) MysqlBackup(database string, storages []uint) error {
 	backupPath := s.setting.Get(models.SettingKeyBackupPath) + "/mysql"
 	rootPassword := s.setting.Get(models.SettingKeyMysqlRootPassword)
 	backupFile := database + "_" + carbon.Now().ToShortDateTimeString() + ".sql"
@@ -157,34 +111,39 @@ func (s *BackupImpl) MysqlBackup(database string) error {
 			return err
 		}
 	}
+	start := time.Now()
 	err := os.Setenv("MYSQL_PWD", rootPassword)
 	if err != nil {
 		return err
 	}
 
 	if _, err := tools.Exec("/www/server/mysql/bin/mysqldump -uroot " + database + " > " + backupPath + "/" + backupFile); err != nil {
-		return err
+		return s.fail(internal.BackupTypeMysql, database, backupPath+"/"+backupFile+".zip", start, err)
 	}
 	if _, err := tools.Exec("cd " + backupPath + " && zip -r " + backupPath + "/" + backupFile + ".zip " + backupFile); err != nil {
-		return err
+		return s.fail(internal.BackupTypeMysql, database, backupPath+"/"+backupFile+".zip", start, err)
 	}
 	if err := tools.Remove(backupPath + "/" + backupFile); err != nil {
 		return err
 	}
+	if err := os.Unsetenv("MYSQL_PWD"); err != nil {
+		return err
+	}
 
-	return os.Unsetenv("MYSQL_PWD")
+	return s.finish(internal.BackupTypeMysql, database, backupPath+"/"+backupFile+".zip", start, storages)
 }
 
 // MysqlRestore MySQL恢复
 func (s *BackupImpl) MysqlRestore(database string, backupFile string) error {
-	backupPath := s.setting.Get(models.SettingKeyBackupPath) + "/mysql"
 	rootPassword := s.setting.Get(models.SettingKeyMysqlRootPassword)
-	backupFullPath := filepath.Join(backupPath, backupFile)
-	if !tools.Exists(backupFullPath) {
-		return errors.New("备份文件不存在")
+	backupFullPath, cleanup, err := s.open(internal.BackupTypeMysql, backupFile)
+	if err != nil {
+		return err
 	}
+	defer cleanup()
+	backupFile = filepath.Base(backupFullPath)
 
-	if err := os.Setenv("MYSQL_PWD", rootPassword); err != nil {
+	if err = os.Setenv("MYSQL_PWD", rootPassword); err != nil {
 		return err
 	}
 
@@ -192,31 +151,12 @@ func (s *BackupImpl) MysqlRestore(database string, backupFile string) error {
 	if err != nil {
 		return err
 	}
-
-	if !strings.HasSuffix(backupFile, ".sql") {
-		backupFile = "" // 置空，防止干扰后续判断
-		if err = tools.UnArchive(backupFullPath, tempDir); err != nil {
-			return err
-		}
-		if files, err := os.ReadDir(tempDir); err == nil {
-			for _, file := range files {
-				if strings.HasSuffix(file.Name(), ".sql") {
-					backupFile = filepath.Base(file.Name())
-					break
-				}
-			}
-		}
-	} else {
-		if err = tools.Cp(backupFullPath, filepath.Join(tempDir, backupFile)); err != nil {
-			return err
-		}
-	}
-
-	if len(backupFile) == 0 {
-		return errors.New("无法找到备份文件")
+	sqlFile, err := s.extractSQL(backupFullPath, tempDir)
+	if err != nil {
+		return err
 	}
 
-	if _, err = tools.Exec("/www/server/mysql/bin/mysql -uroot " + database + " < " + filepath.Join(tempDir, backupFile)); err != nil {
+	if _, err = tools.Exec("/www/server/mysql/bin/mysql -uroot " + database + " < " + sqlFile); err != nil {
 		return err
 	}
 
@@ -229,100 +169,1375 @@ func (s *BackupImpl) MysqlRestore(database string, backupFile string) error {
 
 // PostgresqlList PostgreSQL备份列表
 func (s *BackupImpl) PostgresqlList() ([]internal.BackupFile, error) {
-	backupPath := s.setting.Get(models.SettingKeyBackupPath)
-	if len(backupPath) == 0 {
-		return []internal.BackupFile{}, nil
-	}
+	return s.list(internal.BackupTypePostgresql)
+}
 
-	backupPath += "/postgresql"
+// PostgresqlBackup PostgreSQL备份
+func (s *BackupImpl) PostgresqlBackup(database string, storages []uint) error {
+	backupPath := s.setting.Get(models.SettingKeyBackupPath) + "/postgresql"
+	backupFile := database + "_" + carbon.Now().ToShortDateTimeString() + ".sql"
 	if !tools.Exists(backupPath) {
 		if err := tools.Mkdir(backupPath, 0644); err != nil {
-			return []internal.BackupFile{}, err
+			return err
 		}
 	}
 
-	files, err := os.ReadDir(backupPath)
+	start := time.Now()
+	if _, err := tools.Exec(`su - postgres -c "pg_dump ` + database + `" > ` + backupPath + "/" + backupFile); err != nil {
+		return s.fail(internal.BackupTypePostgresql, database, backupPath+"/"+backupFile+".zip", start, err)
+	}
+	if _, err := tools.Exec("cd " + backupPath + " && zip -r " + backupPath + "/" + backupFile + ".zip " + backupFile); err != nil {
+		return s.fail(internal.BackupTypePostgresql, database, backupPath+"/"+backupFile+".zip", start, err)
+	}
+
+	if err := tools.Remove(backupPath + "/" + backupFile); err != nil {
+		return err
+	}
+
+	return s.finish(internal.BackupTypePostgresql, database, backupPath+"/"+backupFile+".zip", start, storages)
+}
+
+// PostgresqlRestore PostgreSQL恢复
+func (s *BackupImpl) PostgresqlRestore(database string, backupFile string) error {
+	backupFullPath, cleanup, err := s.open(internal.BackupTypePostgresql, backupFile)
 	if err != nil {
-		return []internal.BackupFile{}, err
+		return err
 	}
-	var backupList []internal.BackupFile
-	for _, file := range files {
-		info, err := file.Info()
-		if err != nil {
-			continue
-		}
-		backupList = append(backupList, internal.BackupFile{
-			Name: file.Name(),
-			Size: tools.FormatBytes(float64(info.Size())),
-		})
+	defer cleanup()
+	backupFile = filepath.Base(backupFullPath)
+
+	tempDir, err := tools.TempDir(backupFile)
+	if err != nil {
+		return err
+	}
+	sqlFile, err := s.extractSQL(backupFullPath, tempDir)
+	if err != nil {
+		return err
 	}
 
-	return backupList, nil
+	if _, err = tools.Exec(`su - postgres -c "psql ` + database + `" < ` + sqlFile); err != nil {
+		return err
+	}
+
+	if err = tools.Remove(tempDir); err != nil {
+		return err
+	}
+
+	return nil
 }
 
-// PostgresqlBackup PostgreSQL备份
-func (s *BackupImpl) PostgresqlBackup(database string) error {
-	backupPath := s.setting.Get(models.SettingKeyBackupPath) + "/postgresql"
-	backupFile := database + "_" + carbon.Now().ToShortDateTimeString() + ".sql"
-	if !tools.Exists(backupPath) {
-		if err := tools.Mkdir(backupPath, 0644); err != nil {
+// RedisList Redis备份列表
+func (s *BackupImpl) RedisList() ([]internal.BackupFile, error) {
+	return s.list(internal.BackupTypeRedis)
+}
+
+// RedisBackup Redis备份
+func (s *BackupImpl) RedisBackup(storages []uint) error {
+	backupPath, err := s.localDir(internal.BackupTypeRedis)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	backupFile := backupPath + "/redis_" + carbon.Now().ToShortDateTimeString() + ".zip"
+	if err = s.RedisDump(backupFile); err != nil {
+		return s.fail(internal.BackupTypeRedis, "redis", backupFile, start, err)
+	}
+
+	return s.finish(internal.BackupTypeRedis, "redis", backupFile, start, storages)
+}
+
+// RedisDump 执行 BGSAVE 和 AOF 重写并等待完成，将 RDB 和开启时的 AOF 打包到 file
+//
+// 包中的文件名是固定的 dump.rdb、appendonlydir 或 appendonly.aof，恢复时按当前配置重命名
+func (s *BackupImpl) RedisDump(file string) error {
+	config, err := s.redisConfig()
+	if err != nil {
+		return err
+	}
+
+	// 先等待正在进行的 BGSAVE 完成，避免把它的结果当成本次备份
+	if err = s.redisWaitSave(config, ""); err != nil {
+		return err
+	}
+	lastSave, err := s.redisCli(config, "LASTSAVE")
+	if err != nil {
+		return err
+	}
+	// SCHEDULE 在 AOF 重写时等待重写完成后再执行，而不是直接报错
+	if _, err = s.redisCli(config, "BGSAVE SCHEDULE"); err != nil {
+		return fmt.Errorf("执行 BGSAVE 失败: %w", err)
+	}
+	if err = s.redisWaitSave(config, lastSave); err != nil {
+		return err
+	}
+	// 开启 AOF 时先重写，复制的是刚生成的完整文件，之后追加的命令即使截断 Redis 也能加载
+	if config.appendonly {
+		if err = s.redisWaitRewrite(config, false); err != nil {
+			return err
+		}
+		if _, err = s.redisCli(config, "BGREWRITEAOF"); err != nil {
+			return fmt.Errorf("执行 BGREWRITEAOF 失败: %w", err)
+		}
+		if err = s.redisWaitRewrite(config, true); err != nil {
 			return err
 		}
 	}
 
-	if _, err := tools.Exec(`su - postgres -c "pg_dump ` + database + `" > ` + backupPath + "/" + backupFile); err != nil {
+	tempDir, err := tools.TempDir("redis-backup")
+	if err != nil {
 		return err
 	}
-	if _, err := tools.Exec("cd " + backupPath + " && zip -r " + backupPath + "/" + backupFile + ".zip " + backupFile); err != nil {
+	defer tools.Remove(tempDir)
+
+	files := []string{filepath.Join(tempDir, "dump.rdb")}
+	if err = tools.Cp(filepath.Join(config.dir, config.dbfilename), files[0]); err != nil {
 		return err
 	}
+	if config.appendonly {
+		// Redis 7 开始 AOF 由目录中的多个文件组成，之前的版本为单个文件
+		aof := map[string]string{config.appenddirname: "appendonlydir", config.appendfilename: "appendonly.aof"}
+		for _, name := range []string{config.appenddirname, config.appendfilename} {
+			if !tools.Exists(filepath.Join(config.dir, name)) {
+				continue
+			}
+			target := filepath.Join(tempDir, aof[name])
+			if err = tools.Cp(filepath.Join(config.dir, name), target); err != nil {
+				return err
+			}
+			files = append(files, target)
+			break
+		}
+	}
 
-	return tools.Remove(backupPath + "/" + backupFile)
+	return tools.Archive(files, file)
 }
 
-// PostgresqlRestore PostgreSQL恢复
-func (s *BackupImpl) PostgresqlRestore(database string, backupFile string) error {
-	backupPath := s.setting.Get(models.SettingKeyBackupPath) + "/postgresql"
-	backupFullPath := filepath.Join(backupPath, backupFile)
-	if !tools.Exists(backupFullPath) {
-		return errors.New("备份文件不存在")
+// RedisRestore Redis恢复，停止 Redis，替换 RDB 和 AOF 后再启动，原文件重命名为 .bak 保留
+func (s *BackupImpl) RedisRestore(backupFile string) error {
+	config, err := s.redisConfig()
+	if err != nil {
+		return err
+	}
+	backupFullPath, cleanup, err := s.open(internal.BackupTypeRedis, backupFile)
+	if err != nil {
+		return err
 	}
+	defer cleanup()
 
-	tempDir, err := tools.TempDir(backupFile)
+	tempDir, err := tools.TempDir("redis-restore")
 	if err != nil {
 		return err
 	}
+	defer tools.Remove(tempDir)
+	if err = tools.UnArchive(backupFullPath, tempDir); err != nil {
+		return err
+	}
+
+	rdb := filepath.Join(tempDir, "dump.rdb")
+	if !tools.Exists(rdb) {
+		return errors.New("备份中没有 RDB 文件")
+	}
+	// 需要替换的文件，源为空表示只移走原文件
+	swaps := [][2]string{{rdb, filepath.Join(config.dir, config.dbfilename)}}
+	if config.appendonly {
+		dir, file := filepath.Join(tempDir, "appendonlydir"), filepath.Join(tempDir, "appendonly.aof")
+		switch {
+		case tools.Exists(dir):
+			swaps = append(swaps, [2]string{dir, filepath.Join(config.dir, config.appenddirname)}, [2]string{"", filepath.Join(config.dir, config.appendfilename)})
+		case tools.Exists(file):
+			// 单文件 AOF 需要移走 AOF 目录，Redis 7 启动时会自动转换
+			swaps = append(swaps, [2]string{file, filepath.Join(config.dir, config.appendfilename)}, [2]string{"", filepath.Join(config.dir, config.appenddirname)})
+		default:
+			return errors.New("Redis 已开启 AOF，但备份中没有 AOF 文件，请先关闭 appendonly 再恢复")
+		}
+	}
+
+	if err = tools.ServiceStop("redis"); err != nil {
+		return fmt.Errorf("停止 Redis 失败: %w", err)
+	}
+	swapErr := s.redisSwap(swaps)
+	if err = tools.ServiceStart("redis"); err != nil {
+		return fmt.Errorf("启动 Redis 失败: %w", err)
+	}
+
+	return swapErr
+}
+
+// redisSwap 将原文件重命名为 .bak 后移入新文件，失败时还原原文件
+func (s *BackupImpl) redisSwap(swaps [][2]string) error {
+	var moved []string
+	rollback := func(err error) error {
+		for _, target := range moved {
+			_ = tools.Remove(target)
+			_ = tools.Mv(target+".bak", target)
+		}
+		return err
+	}
+
+	for _, swap := range swaps {
+		source, target := swap[0], swap[1]
+		if tools.Exists(target) {
+			if err := tools.Remove(target + ".bak"); err != nil {
+				return rollback(err)
+			}
+			if err := tools.Mv(target, target+".bak"); err != nil {
+				return rollback(err)
+			}
+			moved = append(moved, target)
+		}
+		if len(source) == 0 {
+			continue
+		}
+		if err := tools.Mv(source, target); err != nil {
+			return rollback(err)
+		}
+		if err := tools.Chown(target, "redis", "redis"); err != nil {
+			return rollback(err)
+		}
+	}
+
+	return nil
+}
+
+type redisConfig struct {
+	port           string
+	password       string
+	dir            string
+	dbfilename     string
+	appendonly     bool
+	appenddirname  string
+	appendfilename string
+}
+
+// redisConfig 读取 Redis 配置文件，未配置的项使用 Redis 的默认值
+func (s *BackupImpl) redisConfig() (redisConfig, error) {
+	redisPath := "/www/server/redis"
+	content, err := tools.Read(redisPath + "/redis.conf")
+	if err != nil {
+		return redisConfig{}, errors.New("获取Redis配置失败")
+	}
+
+	config := redisConfig{
+		port:           "6379",
+		dir:            redisPath,
+		dbfilename:     "dump.rdb",
+		appenddirname:  "appendonlydir",
+		appendfilename: "appendonly.aof",
+	}
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		value := strings.Trim(fields[1], `"'`)
+		switch strings.ToLower(fields[0]) {
+		case "port":
+			config.port = value
+		case "requirepass":
+			config.password = value
+		case "dir":
+			// 服务的工作目录为 Redis 安装目录
+			if !filepath.IsAbs(value) {
+				value = filepath.Join(redisPath, value)
+			}
+			config.dir = value
+		case "dbfilename":
+			config.dbfilename = value
+		case "appendonly":
+			config.appendonly = value == "yes"
+		case "appenddirname":
+			config.appenddirname = value
+		case "appendfilename":
+			config.appendfilename = value
+		}
+	}
+
+	return config, nil
+}
+
+// redisCli 执行 redis-cli 命令，通过环境变量传递密码，避免出现在进程列表中
+func (s *BackupImpl) redisCli(config redisConfig, command string) (string, error) {
+	shell := "redis-cli -p " + config.port + " " + command
+	if len(config.password) > 0 {
+		shell = "REDISCLI_AUTH='" + strings.ReplaceAll(config.password, "'", `'\''`) + "' " + shell
+	}
+	out, err := tools.Exec(shell)
+	if err != nil {
+		return "", err
+	}
+	// redis-cli 遇到错误回复时退出码仍然为 0
+	for _, prefix := range []string{"ERR", "NOAUTH", "WRONGPASS", "NOPERM", "Could not connect"} {
+		if strings.HasPrefix(out, prefix) {
+			return "", errors.New(out)
+		}
+	}
+
+	return out, nil
+}
 
-	if !strings.HasSuffix(backupFile, ".sql") {
-		backupFile = "" // 置空，防止干扰后续判断
-		if err = tools.UnArchive(backupFullPath, tempDir); err != nil {
+// redisWaitSave 等待 BGSAVE 完成，lastSave 不为空时等待本次 BGSAVE 完成并检查结果
+func (s *BackupImpl) redisWaitSave(config redisConfig, lastSave string) error {
+	started := false
+	deadline := time.Now().Add(time.Hour)
+	for time.Now().Before(deadline) {
+		info, err := s.redisCli(config, "INFO persistence")
+		if err != nil {
 			return err
 		}
-		if files, err := os.ReadDir(tempDir); err == nil {
-			for _, file := range files {
-				if strings.HasSuffix(file.Name(), ".sql") {
-					backupFile = filepath.Base(file.Name())
-					break
-				}
+		if strings.Contains(info, "rdb_bgsave_in_progress:1") {
+			started = true
+			time.Sleep(time.Second)
+			continue
+		}
+		if len(lastSave) == 0 {
+			return nil
+		}
+
+		current, err := s.redisCli(config, "LASTSAVE")
+		if err != nil {
+			return err
+		}
+		// LASTSAVE 精确到秒，BGSAVE 开始后结束也视为完成
+		if current != lastSave || started {
+			if !strings.Contains(info, "rdb_last_bgsave_status:ok") {
+				return errors.New("BGSAVE 失败，请查看 Redis 日志")
 			}
+			return nil
 		}
-	} else {
-		if err = tools.Cp(backupFullPath, filepath.Join(tempDir, backupFile)); err != nil {
+		time.Sleep(time.Second)
+	}
+
+	return errors.New("等待 BGSAVE 完成超时")
+}
+
+// redisWaitRewrite 等待 AOF 重写完成，check 为 true 时检查重写结果
+func (s *BackupImpl) redisWaitRewrite(config redisConfig, check bool) error {
+	deadline := time.Now().Add(time.Hour)
+	for time.Now().Before(deadline) {
+		info, err := s.redisCli(config, "INFO persistence")
+		if err != nil {
 			return err
 		}
+		if strings.Contains(info, "aof_rewrite_in_progress:1") || strings.Contains(info, "aof_rewrite_scheduled:1") {
+			time.Sleep(time.Second)
+			continue
+		}
+		if check && !strings.Contains(info, "aof_last_bgrewrite_status:ok") {
+			return errors.New("AOF 重写失败，请查看 Redis 日志")
+		}
+		return nil
 	}
 
-	if len(backupFile) == 0 {
-		return errors.New("无法找到备份文件")
+	return errors.New("等待 AOF 重写完成超时")
+}
+
+// Delete 删除本地和所有远程存储中的备份
+func (s *BackupImpl) Delete(backupType, name string) error {
+	if strings.ContainsAny(name, `/\`) {
+		return errors.New("备份文件名不合法")
 	}
 
-	if _, err = tools.Exec(`su - postgres -c "psql ` + database + `" < ` + filepath.Join(tempDir, backupFile)); err != nil {
+	dir, err := s.localDir(backupType)
+	if err != nil {
 		return err
 	}
+	if tools.Exists(filepath.Join(dir, name)) {
+		if err = tools.Remove(filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
 
-	if err = tools.Remove(tempDir); err != nil {
+	storages, err := s.storages()
+	if err != nil {
 		return err
 	}
+	var failed []string
+	for _, item := range storages {
+		if err = item.storage.Delete(context.Background(), backupType+"/"+name); err != nil && !errors.Is(err, storage.ErrNotExist) {
+			failed = append(failed, item.name+": "+err.Error())
+		}
+	}
+	if len(failed) > 0 {
+		return errors.New("删除远程备份失败: " + strings.Join(failed, "; "))
+	}
 
-	return nil
+	_, err = facades.Orm().Query().Where("type", backupType).Where("name", name).Delete(&models.Backup{})
+	return err
+}
+
+// Upload 将本地备份文件上传到远程存储，storages 为 nil 时上传到所有启用的存储，为空时只保留本地备份
+//
+// 返回备份所在的存储名称，包括本地备份目录 local，部分存储上传失败时同时返回错误
+func (s *BackupImpl) Upload(backupType, file string, storages []uint) ([]string, error) {
+	uploaded := []string{"local"}
+	items, err := s.storages()
+	if err != nil {
+		return uploaded, err
+	}
+
+	var failed []string
+	for _, item := range items {
+		if storages != nil && !slices.Contains(storages, item.id) {
+			continue
+		}
+		if err = s.put(item.storage, backupType+"/"+filepath.Base(file), file); err != nil {
+			failed = append(failed, item.name+": "+err.Error())
+			continue
+		}
+		uploaded = append(uploaded, item.name)
+	}
+	if len(failed) > 0 {
+		return uploaded, errors.New("备份已保存到本地，但上传到远程存储失败: " + strings.Join(failed, "; "))
+	}
+
+	return uploaded, nil
+}
+
+// Record 记录备份的大小、SHA-256、耗时和所在的存储，同名备份已存在时更新
+func (s *BackupImpl) Record(backupType, source, file string, duration time.Duration, storages []string) (models.Backup, error) {
+	size, sum, err := s.checksum(file)
+	if err != nil {
+		return models.Backup{}, err
+	}
+
+	var backup models.Backup
+	if err = facades.Orm().Query().Where("type", backupType).Where("name", filepath.Base(file)).First(&backup); err != nil {
+		return models.Backup{}, err
+	}
+	backup.Type = backupType
+	backup.Source = source
+	backup.Name = filepath.Base(file)
+	backup.Path = filepath.Dir(file)
+	backup.Size = size
+	backup.SHA256 = sum
+	backup.Duration = duration.Milliseconds()
+	backup.Storages = storages
+	backup.Status = models.BackupStatusCreated
+	backup.Message = ""
+	backup.VerifiedAt = nil
+	if err = facades.Orm().Query().Save(&backup); err != nil {
+		return models.Backup{}, err
+	}
+
+	return backup, nil
+}
+
+// RecordFailure 记录失败的备份，file 为本应生成的备份文件
+func (s *BackupImpl) RecordFailure(backupType, source, file string, duration time.Duration, cause error) error {
+	backup := models.Backup{
+		Type:     backupType,
+		Source:   source,
+		Name:     filepath.Base(file),
+		Path:     filepath.Dir(file),
+		Duration: duration.Milliseconds(),
+		Storages: []string{},
+		Status:   models.BackupStatusError,
+		Message:  cause.Error(),
+	}
+
+	return facades.Orm().Query().Create(&backup)
+}
+
+// Catalog 备份记录列表，backupType 和 source 为空时不过滤
+func (s *BackupImpl) Catalog(backupType, source string, page, limit int) ([]models.Backup, int64, error) {
+	query := facades.Orm().Query()
+	if len(backupType) > 0 {
+		query = query.Where("type", backupType)
+	}
+	if len(source) > 0 {
+		query = query.Where("source", source)
+	}
+
+	var backups []models.Backup
+	var total int64
+	if err := query.Order("id desc").Paginate(page, limit, &backups, &total); err != nil {
+		return nil, 0, err
+	}
+
+	return backups, total, nil
+}
+
+// Verify 校验备份，检查 SHA-256 并恢复到临时目录或临时数据库，结果保存到备份记录
+func (s *BackupImpl) Verify(ID uint) error {
+	var backup models.Backup
+	if err := facades.Orm().Query().Where("id", ID).FirstOrFail(&backup); err != nil {
+		return errors.New("备份不存在")
+	}
+	if backup.Status == models.BackupStatusError {
+		return errors.New("备份失败，无法校验: " + backup.Message)
+	}
+	backup.Status = models.BackupStatusVerifying
+	if err := facades.Orm().Query().Save(&backup); err != nil {
+		return err
+	}
+
+	verifyErr := s.verify(backup)
+	now := carbon.DateTime{Carbon: carbon.Now()}
+	backup.VerifiedAt = &now
+	backup.Status = models.BackupStatusVerified
+	backup.Message = ""
+	if verifyErr != nil {
+		backup.Status = models.BackupStatusFailed
+		backup.Message = verifyErr.Error()
+	}
+	if err := facades.Orm().Query().Save(&backup); err != nil {
+		return err
+	}
+
+	return verifyErr
+}
+
+// VerifyTask 创建校验备份的后台任务
+func (s *BackupImpl) VerifyTask(ID uint) error {
+	var backup models.Backup
+	if err := facades.Orm().Query().Where("id", ID).FirstOrFail(&backup); err != nil {
+		return errors.New("备份不存在")
+	}
+	if backup.Status == models.BackupStatusError {
+		return errors.New("备份失败，无法校验")
+	}
+	// 校验进程异常退出时状态会停留在校验中，超过 6 小时视为已中断，允许重新校验
+	if backup.Status == models.BackupStatusVerifying && carbon.Now().Lt(backup.UpdatedAt.Carbon.AddHours(6)) {
+		return errors.New("备份正在校验中")
+	}
+
+	logFile := "/tmp/backup_verify_" + strconv.Itoa(int(backup.ID)) + "_" + carbon.Now().ToShortDateTimeString() + ".log"
+	var task models.Task
+	task.Name = "校验备份 " + backup.Name
+	task.Status = models.TaskStatusWaiting
+	task.Shell = `panel backupVerify ` + strconv.Itoa(int(backup.ID)) + ` >> '` + logFile + `' 2>&1`
+	task.Log = logFile
+	if err := facades.Orm().Query().Create(&task); err != nil {
+		return errors.New("创建任务失败")
+	}
+
+	s.task.Process(task.ID)
+	return nil
+}
+
+// Prune 按保留策略清理本地和所有远程存储中 name 的备份，dir 为空时使用默认的本地备份目录
+//
+// 只处理文件名为 name_时间.zip 或 name_时间.sql.zip（可能已加密）的备份，dryRun 时只返回结果不删除
+func (s *BackupImpl) Prune(backupType, dir, name string, policy retention.Policy, dryRun bool) ([]internal.BackupPrune, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	if len(dir) == 0 {
+		var err error
+		if dir, err = s.localDir(backupType); err != nil {
+			return nil, err
+		}
+	}
+
+	local, err := storage.NewLocal(dir)
+	if err != nil {
+		return nil, err
+	}
+	targets := []backupStorage{{name: "local", storage: local}}
+	storages, err := s.storages()
+	if err != nil {
+		return nil, err
+	}
+	targets = append(targets, storages...)
+
+	pattern := regexp.MustCompile(`^` + regexp.QuoteMeta(name) + `_(\d{14})(\.sql)?\.zip$`)
+	var items []retention.Item
+	locations := make(map[string][]backupStorage)
+	for _, target := range targets {
+		// 本地存储的 id 为 0，根目录就是该类型的备份目录
+		prefix := backupType
+		if target.id == 0 {
+			prefix = ""
+		}
+		files, err := target.storage.List(context.Background(), prefix)
+		if err != nil {
+			return nil, fmt.Errorf("获取存储 %s 中的备份失败: %w", target.name, err)
+		}
+		for _, file := range files {
+			plain, _, _ := encryption.ParseName(file.Name)
+			match := pattern.FindStringSubmatch(plain)
+			if match == nil {
+				continue
+			}
+			if _, ok := locations[file.Name]; !ok {
+				backupTime := carbon.ParseByLayout(match[1], "20060102150405")
+				if backupTime.IsInvalid() {
+					continue
+				}
+				items = append(items, retention.Item{Name: file.Name, Time: backupTime.ToStdTime()})
+			}
+			locations[file.Name] = append(locations[file.Name], target)
+		}
+	}
+
+	var result []internal.BackupPrune
+	var failed []string
+	for _, decision := range policy.Apply(items) {
+		prune := internal.BackupPrune{Decision: decision}
+		deleted := true
+		for _, target := range locations[decision.Name] {
+			prune.Storages = append(prune.Storages, target.name)
+			if decision.Keep || dryRun {
+				continue
+			}
+			key := backupType + "/" + decision.Name
+			if target.id == 0 {
+				key = decision.Name
+			}
+			if err = target.storage.Delete(context.Background(), key); err != nil && !errors.Is(err, storage.ErrNotExist) {
+				failed = append(failed, target.name+": "+decision.Name+": "+err.Error())
+				deleted = false
+			}
+		}
+		if !decision.Keep && !dryRun && deleted {
+			if _, err = facades.Orm().Query().Where("type", backupType).Where("name", decision.Name).Delete(&models.Backup{}); err != nil {
+				failed = append(failed, decision.Name+": "+err.Error())
+			}
+		}
+		result = append(result, prune)
+	}
+	if len(failed) > 0 {
+		return result, errors.New("清理备份失败: " + strings.Join(failed, "; "))
+	}
+
+	return result, nil
+}
+
+// StorageList 远程存储列表
+func (s *BackupImpl) StorageList() ([]models.BackupStorage, error) {
+	var storages []models.BackupStorage
+	if err := facades.Orm().Query().Order("id asc").Find(&storages); err != nil {
+		return nil, err
+	}
+	for i := range storages {
+		storages[i].Config = storages[i].Config.Masked()
+	}
+
+	return storages, nil
+}
+
+// StorageShow 获取远程存储，包含密钥类字段
+func (s *BackupImpl) StorageShow(ID uint) (models.BackupStorage, error) {
+	var backupStorage models.BackupStorage
+	err := facades.Orm().Query().Where("id", ID).FirstOrFail(&backupStorage)
+
+	return backupStorage, err
+}
+
+// StorageStore 添加远程存储，保存前检查存储是否可用
+func (s *BackupImpl) StorageStore(request requests.StorageStore) error {
+	backupStorage := models.BackupStorage{
+		Name:    request.Name,
+		Type:    request.Type,
+		Config:  request.Config,
+		Enabled: request.Enabled,
+	}
+	if err := s.check(backupStorage); err != nil {
+		return err
+	}
+
+	return facades.Orm().Query().Create(&backupStorage)
+}
+
+// StorageUpdate 更新远程存储
+func (s *BackupImpl) StorageUpdate(request requests.StorageUpdate) error {
+	var backupStorage models.BackupStorage
+	if err := facades.Orm().Query().Where("id", request.ID).FirstOrFail(&backupStorage); err != nil {
+		return err
+	}
+
+	backupStorage.Name = request.Name
+	backupStorage.Type = request.Type
+	backupStorage.Config = request.Config.Unmask(backupStorage.Config)
+	backupStorage.Enabled = request.Enabled
+	if err := s.check(backupStorage); err != nil {
+		return err
+	}
+
+	return facades.Orm().Query().Save(&backupStorage)
+}
+
+// StorageDestroy 删除远程存储，已上传的备份不会被删除
+func (s *BackupImpl) StorageDestroy(ID uint) error {
+	_, err := facades.Orm().Query().Delete(&models.BackupStorage{}, ID)
+	return err
+}
+
+// backupStorage 已启用的远程存储
+type backupStorage struct {
+	id      uint
+	name    string
+	storage storage.Storage
+}
+
+// storages 获取所有启用的远程存储
+func (s *BackupImpl) storages() ([]backupStorage, error) {
+	var items []models.BackupStorage
+	if err := facades.Orm().Query().Where("enabled", true).Order("id asc").Find(&items); err != nil {
+		return nil, err
+	}
+
+	var storages []backupStorage
+	for _, item := range items {
+		instance, err := storage.New(storage.Type(item.Type), item.Config)
+		if err != nil {
+			return nil, fmt.Errorf("存储 %s 配置错误: %w", item.Name, err)
+		}
+		storages = append(storages, backupStorage{id: item.ID, name: item.Name, storage: instance})
+	}
+
+	return storages, nil
+}
+
+// check 写入、读取并删除测试文件，检查存储是否可用
+func (s *BackupImpl) check(item models.BackupStorage) error {
+	instance, err := storage.New(storage.Type(item.Type), item.Config)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	name := ".panel-check-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	if err = instance.Put(ctx, name, strings.NewReader("panel"), 5); err != nil {
+		return fmt.Errorf("写入存储失败: %w", err)
+	}
+	reader, err := instance.Open(ctx, name)
+	if err != nil {
+		return fmt.Errorf("读取存储失败: %w", err)
+	}
+	_ = reader.Close()
+	if err = instance.Delete(ctx, name); err != nil {
+		return fmt.Errorf("删除存储中的文件失败: %w", err)
+	}
+
+	return nil
+}
+
+// localDir 获取本地备份目录，不存在时创建
+func (s *BackupImpl) localDir(backupType string) (string, error) {
+	backupPath := s.setting.Get(models.SettingKeyBackupPath)
+	if len(backupPath) == 0 {
+		return "", errors.New("未正确配置备份路径")
+	}
+
+	backupPath += "/" + backupType
+	if !tools.Exists(backupPath) {
+		if err := tools.Mkdir(backupPath, 0644); err != nil {
+			return "", err
+		}
+	}
+
+	return backupPath, nil
+}
+
+// list 合并本地和远程存储中的备份列表
+func (s *BackupImpl) list(backupType string) ([]internal.BackupFile, error) {
+	if len(s.setting.Get(models.SettingKeyBackupPath)) == 0 {
+		return []internal.BackupFile{}, nil
+	}
+	dir, err := s.localDir(backupType)
+	if err != nil {
+		return []internal.BackupFile{}, err
+	}
+
+	var keys []models.BackupKey
+	if err = facades.Orm().Query().Find(&keys); err != nil {
+		return []internal.BackupFile{}, err
+	}
+	keyNames := make(map[string]string)
+	for _, key := range keys {
+		keyNames[key.Fingerprint] = key.Name
+	}
+	var backups []models.Backup
+	if err = facades.Orm().Query().Where("type", backupType).Find(&backups); err != nil {
+		return []internal.BackupFile{}, err
+	}
+	records := make(map[string]models.Backup)
+	for _, backup := range backups {
+		records[backup.Name] = backup
+	}
+
+	var backupList []internal.BackupFile
+	index := make(map[string]int)
+	add := func(location string, file storage.File) {
+		if i, ok := index[file.Name]; ok {
+			backupList[i].Storages = append(backupList[i].Storages, location)
+			return
+		}
+		index[file.Name] = len(backupList)
+		backupFile := internal.BackupFile{
+			Name:     file.Name,
+			Size:     tools.FormatBytes(float64(file.Size)),
+			Storages: []string{location},
+		}
+		if record, ok := records[file.Name]; ok {
+			backupFile.ID = record.ID
+			backupFile.Status = record.Status
+		}
+		if _, fingerprint, encrypted := encryption.ParseName(file.Name); encrypted {
+			backupFile.Encrypted = true
+			backupFile.Key = fingerprint
+			if name, ok := keyNames[fingerprint]; ok {
+				backupFile.Key = name
+			}
+		}
+		backupList = append(backupList, backupFile)
+	}
+
+	local, _ := storage.NewLocal(dir)
+	files, err := local.List(context.Background(), "")
+	if err != nil {
+		return []internal.BackupFile{}, err
+	}
+	for _, file := range files {
+		add("local", file)
+	}
+
+	storages, err := s.storages()
+	if err != nil {
+		return backupList, err
+	}
+	for _, item := range storages {
+		files, err = item.storage.List(context.Background(), backupType)
+		if err != nil {
+			return backupList, fmt.Errorf("获取存储 %s 中的备份失败: %w", item.name, err)
+		}
+		for _, file := range files {
+			add(item.name, file)
+		}
+	}
+
+	return backupList, nil
+}
+
+// fetch 获取备份文件的本地路径，本地不存在时从远程存储下载
+func (s *BackupImpl) fetch(backupType, name string) (string, error) {
+	if strings.ContainsAny(name, `/\`) {
+		return "", errors.New("备份文件名不合法")
+	}
+	dir, err := s.localDir(backupType)
+	if err != nil {
+		return "", err
+	}
+	file := filepath.Join(dir, name)
+	if tools.Exists(file) {
+		return file, nil
+	}
+
+	storages, err := s.storages()
+	if err != nil {
+		return "", err
+	}
+	for _, item := range storages {
+		reader, err := item.storage.Open(context.Background(), backupType+"/"+name)
+		if errors.Is(err, storage.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("从存储 %s 下载备份失败: %w", item.name, err)
+		}
+
+		local, _ := storage.NewLocal(dir)
+		err = local.Put(context.Background(), name, reader, 0)
+		_ = reader.Close()
+		if err != nil {
+			return "", fmt.Errorf("从存储 %s 下载备份失败: %w", item.name, err)
+		}
+		return file, nil
+	}
+
+	return "", errors.New("备份文件不存在")
+}
+
+// finish 加密备份、上传到远程存储并记录备份
+func (s *BackupImpl) finish(backupType, source, file string, start time.Time, storages []uint) error {
+	encrypted, err := s.Encrypt(file)
+	if err != nil {
+		return s.fail(backupType, source, file, start, err)
+	}
+	file = encrypted
+
+	uploaded, uploadErr := s.Upload(backupType, file, storages)
+	if _, err = s.Record(backupType, source, file, time.Since(start), uploaded); err != nil {
+		return err
+	}
+
+	return uploadErr
+}
+
+// fail 记录失败的备份并返回原错误
+func (s *BackupImpl) fail(backupType, source, file string, start time.Time, err error) error {
+	_ = s.RecordFailure(backupType, source, file, time.Since(start), err)
+	return err
+}
+
+// checksum 计算文件大小和 SHA-256
+func (s *BackupImpl) checksum(file string) (int64, string, error) {
+	reader, err := os.Open(file)
+	if err != nil {
+		return 0, "", err
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, reader)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// extractSQL 将 SQL 备份解压或复制到 dir，返回 SQL 文件路径
+func (s *BackupImpl) extractSQL(file, dir string) (string, error) {
+	if strings.HasSuffix(file, ".sql") {
+		target := filepath.Join(dir, filepath.Base(file))
+		return target, tools.Cp(file, target)
+	}
+
+	if err := tools.UnArchive(file, dir); err != nil {
+		return "", err
+	}
+	if files, err := os.ReadDir(dir); err == nil {
+		for _, item := range files {
+			if strings.HasSuffix(item.Name(), ".sql") {
+				return filepath.Join(dir, item.Name()), nil
+			}
+		}
+	}
+
+	return "", errors.New("无法找到备份文件")
+}
+
+// verify 检查备份文件的 SHA-256，并恢复到临时目录或临时数据库检查备份是否可用
+func (s *BackupImpl) verify(backup models.Backup) error {
+	file := filepath.Join(backup.Path, backup.Name)
+	if !tools.Exists(file) {
+		var err error
+		if file, err = s.fetch(backup.Type, backup.Name); err != nil {
+			return err
+		}
+	}
+	if len(backup.SHA256) > 0 {
+		_, sum, err := s.checksum(file)
+		if err != nil {
+			return err
+		}
+		if sum != backup.SHA256 {
+			return errors.New("SHA-256 不匹配，备份文件可能已损坏")
+		}
+	}
+
+	plain, cleanup, err := s.decrypt(file)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	tempDir, err := tools.TempDir("backup-verify")
+	if err != nil {
+		return err
+	}
+	defer tools.Remove(tempDir)
+
+	switch backup.Type {
+	case internal.BackupTypeWebsite:
+		if err = tools.UnArchive(plain, tempDir); err != nil {
+			return fmt.Errorf("解压备份失败: %w", err)
+		}
+		return nil
+	case internal.BackupTypeRedis:
+		if err = tools.UnArchive(plain, tempDir); err != nil {
+			return fmt.Errorf("解压备份失败: %w", err)
+		}
+		if _, err = tools.Exec("redis-check-rdb " + filepath.Join(tempDir, "dump.rdb")); err != nil {
+			return fmt.Errorf("RDB 文件校验失败: %w", err)
+		}
+		return nil
+	}
+
+	sqlFile, err := s.extractSQL(plain, tempDir)
+	if err != nil {
+		return err
+	}
+	switch backup.Type {
+	case internal.BackupTypeMysql:
+		return s.verifyMysql(sqlFile)
+	case internal.BackupTypePostgresql:
+		return s.verifyPostgresql(sqlFile)
+	}
+
+	return errors.New("不支持的备份类型")
+}
+
+// verifyMysql 将 SQL 导入临时数据库，完成后删除临时数据库
+func (s *BackupImpl) verifyMysql(sqlFile string) error {
+	if err := os.Setenv("MYSQL_PWD", s.setting.Get(models.SettingKeyMysqlRootPassword)); err != nil {
+		return err
+	}
+	defer os.Unsetenv("MYSQL_PWD")
+
+	database := "panel_verify_" + strings.ToLower(tools.RandomString(8))
+	if _, err := tools.Exec("/www/server/mysql/bin/mysql -uroot -e 'CREATE DATABASE " + database + "'"); err != nil {
+		return fmt.Errorf("创建临时数据库失败: %w", err)
+	}
+	defer func() {
+		_, _ = tools.Exec("/www/server/mysql/bin/mysql -uroot -e 'DROP DATABASE IF EXISTS " + database + "'")
+	}()
+
+	if _, err := tools.Exec("/www/server/mysql/bin/mysql -uroot " + database + " < " + sqlFile); err != nil {
+		return fmt.Errorf("导入临时数据库失败: %w", err)
+	}
+
+	return nil
+}
+
+// verifyPostgresql 将 SQL 导入临时数据库，完成后删除临时数据库
+func (s *BackupImpl) verifyPostgresql(sqlFile string) error {
+	database := "panel_verify_" + strings.ToLower(tools.RandomString(8))
+	if _, err := tools.Exec(`su - postgres -c "createdb ` + database + `"`); err != nil {
+		return fmt.Errorf("创建临时数据库失败: %w", err)
+	}
+	defer func() {
+		_, _ = tools.Exec(`su - postgres -c "dropdb --if-exists ` + database + `"`)
+	}()
+
+	if _, err := tools.Exec(`su - postgres -c "psql -q -v ON_ERROR_STOP=1 ` + database + `" < ` + sqlFile); err != nil {
+		return fmt.Errorf("导入临时数据库失败: %w", err)
+	}
+
+	return nil
+}
+
+// put 上传本地文件到存储
+func (s *BackupImpl) put(target storage.Storage, name, file string) error {
+	reader, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	info, err := reader.Stat()
+	if err != nil {
+		return err
+	}
+
+	return target.Put(context.Background(), name, reader, info.Size())
+}
+
+// StorageHostKey 获取 SFTP 服务器的主机公钥，供添加存储时确认后固定
+func (s *BackupImpl) StorageHostKey(request requests.StorageHostKey) (internal.StorageHostKey, error) {
+	port := request.Port
+	if port == 0 {
+		port = 22
+	}
+
+	hostKey, fingerprint, err := ssh.ScanHostKey(net.JoinHostPort(request.Host, strconv.Itoa(int(port))), 10*time.Second)
+	if err != nil {
+		return internal.StorageHostKey{}, err
+	}
+
+	return internal.StorageHostKey{HostKey: hostKey, Fingerprint: fingerprint}, nil
+}
+
+// Encrypt 使用启用的密钥加密本地备份文件并删除原文件，未启用加密时返回原文件
+func (s *BackupImpl) Encrypt(file string) (string, error) {
+	keyID := cast.ToUint(s.setting.Get(models.SettingKeyBackupKeyID))
+	if keyID == 0 {
+		return file, nil
+	}
+
+	var key models.BackupKey
+	if err := facades.Orm().Query().Where("id", keyID).FirstOrFail(&key); err != nil {
+		return "", errors.New("备份加密密钥不存在")
+	}
+
+	encryptionKey, err := s.encryptionKey(key)
+	if err != nil {
+		return "", err
+	}
+	encrypted, err := encryption.EncryptFile(file, encryptionKey, key.Fingerprint)
+	if err != nil {
+		return "", fmt.Errorf("加密备份失败: %w", err)
+	}
+
+	return encrypted, nil
+}
+
+// Encryption 获取启用的备份加密密钥 ID，0 表示不加密
+func (s *BackupImpl) Encryption() uint {
+	return cast.ToUint(s.setting.Get(models.SettingKeyBackupKeyID))
+}
+
+// UpdateEncryption 设置备份加密密钥，0 表示不加密
+func (s *BackupImpl) UpdateEncryption(keyID uint) error {
+	if keyID > 0 {
+		var key models.BackupKey
+		if err := facades.Orm().Query().Where("id", keyID).FirstOrFail(&key); err != nil {
+			return errors.New("备份加密密钥不存在")
+		}
+	}
+
+	return s.setting.Set(models.SettingKeyBackupKeyID, cast.ToString(keyID))
+}
+
+// KeyList 备份加密密钥列表
+func (s *BackupImpl) KeyList() ([]models.BackupKey, error) {
+	var keys []models.BackupKey
+	if err := facades.Orm().Query().Order("id asc").Find(&keys); err != nil {
+		return nil, err
+	}
+	for i := range keys {
+		keys[i].HasIdentity = len(keys[i].Identity) > 0
+	}
+
+	return keys, nil
+}
+
+// KeyShow 获取备份加密密钥，包含解密后的密码或私钥
+func (s *BackupImpl) KeyShow(ID uint) (models.BackupKey, error) {
+	var key models.BackupKey
+	if err := facades.Orm().Query().Where("id", ID).FirstOrFail(&key); err != nil {
+		return models.BackupKey{}, err
+	}
+	key.HasIdentity = len(key.Identity) > 0
+
+	encryptionKey, err := s.encryptionKey(key)
+	if err != nil {
+		return models.BackupKey{}, err
+	}
+	key.Identity = encryptionKey.Identity
+
+	return key, nil
+}
+
+// KeyStore 添加备份加密密钥，x25519 类型未提供公钥和私钥时自动生成密钥对
+func (s *BackupImpl) KeyStore(request requests.KeyStore) (models.BackupKey, error) {
+	fingerprint, err := encryption.NewFingerprint()
+	if err != nil {
+		return models.BackupKey{}, err
+	}
+
+	key := models.BackupKey{
+		Name:        request.Name,
+		Type:        request.Type,
+		Fingerprint: fingerprint,
+	}
+	switch encryption.Type(request.Type) {
+	case encryption.TypePassphrase:
+		key.Identity = request.Passphrase
+	case encryption.TypeX25519:
+		key.Recipient = strings.TrimSpace(request.Recipient)
+		key.Identity = strings.TrimSpace(request.Identity)
+		if len(key.Identity) > 0 && len(key.Recipient) == 0 {
+			if key.Recipient, err = encryption.ParseX25519(key.Identity); err != nil {
+				return models.BackupKey{}, errors.New("私钥格式错误")
+			}
+		}
+		if len(key.Recipient) == 0 {
+			if key.Recipient, key.Identity, err = encryption.GenerateX25519(); err != nil {
+				return models.BackupKey{}, err
+			}
+		}
+	}
+	if err = (encryption.Key{Type: encryption.Type(key.Type), Recipient: key.Recipient, Identity: key.Identity}).Validate(); err != nil {
+		return models.BackupKey{}, err
+	}
+
+	// 密码和私钥使用面板密钥加密保存
+	if len(key.Identity) > 0 {
+		if key.Identity, err = facades.Crypt().EncryptString(key.Identity); err != nil {
+			return models.BackupKey{}, err
+		}
+	}
+	if err = facades.Orm().Query().Create(&key); err != nil {
+		return models.BackupKey{}, err
+	}
+	key.HasIdentity = len(key.Identity) > 0
+
+	return key, nil
+}
+
+// KeyDestroy 删除备份加密密钥，使用该密钥加密的备份需要自行保存的私钥或密码才能解密
+func (s *BackupImpl) KeyDestroy(ID uint) error {
+	if s.Encryption() == ID {
+		return errors.New("密钥正在使用中，请先关闭备份加密或更换密钥")
+	}
+
+	_, err := facades.Orm().Query().Delete(&models.BackupKey{}, ID)
+	return err
+}
+
+// encryptionKey 解密保存的密码或私钥
+func (s *BackupImpl) encryptionKey(key models.BackupKey) (encryption.Key, error) {
+	identity := key.Identity
+	if len(identity) > 0 {
+		var err error
+		if identity, err = facades.Crypt().DecryptString(identity); err != nil {
+			return encryption.Key{}, errors.New("备份加密密钥解密失败，面板密钥可能已变更")
+		}
+	}
+
+	return encryption.Key{
+		Type:      encryption.Type(key.Type),
+		Recipient: key.Recipient,
+		Identity:  identity,
+	}, nil
+}
+
+// open 获取用于恢复的备份文件，加密的备份解密到临时目录，使用后调用 cleanup 清理
+func (s *BackupImpl) open(backupType, name string) (string, func(), error) {
+	file, err := s.fetch(backupType, name)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return s.decrypt(file)
+}
+
+// decrypt 解密本地备份文件到临时目录，未加密时直接返回原文件
+func (s *BackupImpl) decrypt(file string) (string, func(), error) {
+	plain, fingerprint, encrypted := encryption.ParseName(filepath.Base(file))
+	if !encrypted {
+		return file, func() {}, nil
+	}
+
+	var key models.BackupKey
+	if err := facades.Orm().Query().Where("fingerprint", fingerprint).First(&key); err != nil {
+		return "", nil, err
+	}
+	if key.ID == 0 {
+		return "", nil, fmt.Errorf("找不到加密备份使用的密钥 %s", fingerprint)
+	}
+
+	tempDir, err := tools.TempDir("backup-decrypt")
+	if err != nil {
+		return "", nil, err
+	}
+	encryptionKey, err := s.encryptionKey(key)
+	if err != nil {
+		_ = tools.Remove(tempDir)
+		return "", nil, err
+	}
+	target := filepath.Join(tempDir, plain)
+	if err = encryption.DecryptFile(file, target, encryptionKey); err != nil {
+		_ = tools.Remove(tempDir)
+		return "", nil, fmt.Errorf("解密备份失败: %w", err)
+	}
+
+	return target, func() { _ = tools.Remove(tempDir) }, nil
+}
+
+// WebsiteSnapshot 创建网站的去重快照，只保存与已有快照不同的数据
+func (s *BackupImpl) WebsiteSnapshot(website models.Website) (*dedup.Snapshot, error) {
+	repository, err := s.repository()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := repository.Backup(website.Path, website.Name)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Files = nil
+
+	return snapshot, nil
+}
+
+// WebsiteSnapshotList 网站的快照列表，按时间倒序
+func (s *BackupImpl) WebsiteSnapshotList(website models.Website) ([]dedup.Snapshot, error) {
+	repository, err := s.repository()
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.Snapshots(website.Name)
+}
+
+// WebsiteSnapshotRestore 从快照完整恢复网站，先恢复到临时目录，成功后再替换网站目录
+func (s *BackupImpl) WebsiteSnapshotRestore(website models.Website, ID string) error {
+	repository, err := s.repository()
+	if err != nil {
+		return err
+	}
+	snapshot, err := repository.Snapshot(ID)
+	if err != nil {
+		return err
+	}
+	if snapshot.Tag != website.Name {
+		return errors.New("快照不属于该网站")
+	}
+
+	temp := website.Path + ".restore-" + ID
+	if err = repository.Restore(ID, temp); err != nil {
+		_ = tools.Remove(temp)
+		return err
+	}
+
+	// 先将原目录移到一旁，新目录就位后再删除，失败时恢复原目录
+	old := website.Path + ".old-" + ID
+	if err = tools.Remove(old); err != nil {
+		_ = tools.Remove(temp)
+		return err
+	}
+	if tools.Exists(website.Path) {
+		if err = tools.Mv(website.Path, old); err != nil {
+			_ = tools.Remove(temp)
+			return err
+		}
+	}
+	if err = tools.Mv(temp, website.Path); err != nil {
+		_ = tools.Remove(temp)
+		if tools.Exists(old) {
+			_ = tools.Remove(website.Path)
+			_ = tools.Mv(old, website.Path)
+		}
+		return err
+	}
+	if err = tools.Chmod(website.Path, 0755); err != nil {
+		return err
+	}
+	if err = tools.Chown(website.Path, "www", "www"); err != nil {
+		return err
+	}
+
+	return tools.Remove(old)
+}
+
+// WebsiteSnapshotDelete 删除网站的快照，释放空间需要再清理未引用的数据块
+func (s *BackupImpl) WebsiteSnapshotDelete(website models.Website, ID string) error {
+	repository, err := s.repository()
+	if err != nil {
+		return err
+	}
+	snapshot, err := repository.Snapshot(ID)
+	if err != nil {
+		return err
+	}
+	if snapshot.Tag != website.Name {
+		return errors.New("快照不属于该网站")
+	}
+
+	return repository.Delete(ID)
+}
+
+// SnapshotPrune 清理没有被任何快照引用的数据块
+func (s *BackupImpl) SnapshotPrune() (dedup.PruneStats, error) {
+	repository, err := s.repository()
+	if err != nil {
+		return dedup.PruneStats{}, err
+	}
+
+	return repository.Prune()
+}
+
+// repository 网站快照使用的去重仓库，位于备份目录下
+func (s *BackupImpl) repository() (*dedup.Repository, error) {
+	backupPath := s.setting.Get(models.SettingKeyBackupPath)
+	if len(backupPath) == 0 {
+		return nil, errors.New("未正确配置备份路径")
+	}
+
+	return dedup.Open(filepath.Join(backupPath, "snapshots"))
 }
@@ -4,23 +4,34 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/goravel/framework/facades"
+	"github.com/goravel/framework/support/carbon"
+	"github.com/spf13/cast"
 
 	requests "panel/app/http/requests/cert"
 	"panel/app/models"
+	"panel/internal"
 	"panel/pkg/acme"
 	"panel/pkg/tools"
 )
 
 type CertImpl struct {
 	client *acme.Client
+	task   internal.Task
 }
 
 func NewCertImpl() *CertImpl {
-	return &CertImpl{}
+	return &CertImpl{
+		task: NewTaskImpl(),
+	}
 }
 
 // UserStore 添加用户
@@ -32,77 +43,134 @@ func (s *CertImpl) UserStore(request requests.UserStore) error {
 	user.HmacEncoded = &request.HmacEncoded
 	user.KeyType = request.KeyType
 
-	var err error
-	var client *acme.Client
-	switch user.CA {
-	case "letsencrypt":
-		client, err = acme.NewRegisterAccount(context.Background(), user.Email, acme.CALetsEncrypt, nil, acme.KeyType(user.KeyType))
-	case "buypass":
-		client, err = acme.NewRegisterAccount(context.Background(), user.Email, acme.CABuypass, nil, acme.KeyType(user.KeyType))
-	case "zerossl":
-		client, err = acme.NewRegisterAccount(context.Background(), user.Email, acme.CAZeroSSL, &acme.EAB{KeyID: *user.Kid, MACKey: *user.HmacEncoded}, acme.KeyType(user.KeyType))
-	case "sslcom":
-		client, err = acme.NewRegisterAccount(context.Background(), user.Email, acme.CASSLcom, &acme.EAB{KeyID: *user.Kid, MACKey: *user.HmacEncoded}, acme.KeyType(user.KeyType))
-	case "google":
-		client, err = acme.NewRegisterAccount(context.Background(), user.Email, acme.CAGoogle, &acme.EAB{KeyID: *user.Kid, MACKey: *user.HmacEncoded}, acme.KeyType(user.KeyType))
-	default:
-		return errors.New("CA 提供商不支持")
-	}
-
-	if err != nil {
-		return errors.New("向 CA 注册账号失败，请检查参数是否正确")
-	}
-
-	privateKey, err := acme.EncodePrivateKey(client.Account.PrivateKey)
-	if err != nil {
-		return errors.New("获取私钥失败")
+	if err := s.register(&user); err != nil {
+		return err
 	}
-	user.PrivateKey = string(privateKey)
 
 	return facades.Orm().Query().Create(&user)
 }
 
 // UserUpdate 更新用户
+// 更换 CA 或 EAB 时重新注册账号，否则将邮箱变更推送到 CA，密钥类型变更时进行密钥轮换
 func (s *CertImpl) UserUpdate(request requests.UserUpdate) error {
 	var user models.CertUser
-	err := facades.Orm().Query().Where("id = ?", request.ID).First(&user)
+	err := facades.Orm().Query().Where("id = ?", request.ID).FirstOrFail(&user)
 	if err != nil {
 		return err
 	}
 
-	user.CA = request.CA
-	user.Email = request.Email
-	user.Kid = &request.Kid
-	user.HmacEncoded = &request.HmacEncoded
-	user.KeyType = request.KeyType
+	if user.CA != request.CA || cast.ToString(user.Kid) != request.Kid || cast.ToString(user.HmacEncoded) != request.HmacEncoded {
+		user.CA = request.CA
+		user.Email = request.Email
+		user.Kid = &request.Kid
+		user.HmacEncoded = &request.HmacEncoded
+		user.KeyType = request.KeyType
+		if err = s.register(&user); err != nil {
+			return err
+		}
 
-	var client *acme.Client
-	switch user.CA {
-	case "letsencrypt":
-		client, err = acme.NewRegisterAccount(context.Background(), user.Email, acme.CALetsEncrypt, nil, acme.KeyType(user.KeyType))
-	case "buypass":
-		client, err = acme.NewRegisterAccount(context.Background(), user.Email, acme.CABuypass, nil, acme.KeyType(user.KeyType))
-	case "zerossl":
-		client, err = acme.NewRegisterAccount(context.Background(), user.Email, acme.CAZeroSSL, &acme.EAB{KeyID: *user.Kid, MACKey: *user.HmacEncoded}, acme.KeyType(user.KeyType))
-	case "sslcom":
-		client, err = acme.NewRegisterAccount(context.Background(), user.Email, acme.CASSLcom, &acme.EAB{KeyID: *user.Kid, MACKey: *user.HmacEncoded}, acme.KeyType(user.KeyType))
-	case "google":
-		client, err = acme.NewRegisterAccount(context.Background(), user.Email, acme.CAGoogle, &acme.EAB{KeyID: *user.Kid, MACKey: *user.HmacEncoded}, acme.KeyType(user.KeyType))
-	default:
-		return errors.New("CA 提供商不支持")
+		return facades.Orm().Query().Save(&user)
 	}
 
+	if user.Status == acme.AccountStatusDeactivated {
+		return errors.New("该账号已在 CA 停用，只能更换 CA 或 EAB 重新注册")
+	}
+	client, err := s.userClient(user)
 	if err != nil {
-		return errors.New("向 CA 注册账号失败，请检查参数是否正确")
+		return fmt.Errorf("获取 CA 账号失败: %w", err)
+	}
+	// 轮换成功后 CA 只认新密钥，需要立即保存，不能等邮箱更新完成
+	if user.KeyType != request.KeyType {
+		privateKey, err := client.RolloverKey(context.Background(), acme.KeyType(request.KeyType))
+		if err != nil {
+			return fmt.Errorf("账号密钥轮换失败: %w", err)
+		}
+		user.PrivateKey = string(privateKey)
+		user.KeyType = request.KeyType
+		if err = facades.Orm().Query().Save(&user); err != nil {
+			return err
+		}
+	}
+	if user.Email != request.Email {
+		if err = client.UpdateContact(context.Background(), request.Email); err != nil {
+			return fmt.Errorf("更新 CA 账号邮箱失败: %w", err)
+		}
+		user.Email = request.Email
+		if err = facades.Orm().Query().Save(&user); err != nil {
+			return err
+		}
 	}
 
-	privateKey, err := acme.EncodePrivateKey(client.Account.PrivateKey)
+	return nil
+}
+
+// UserDeactivate 在 CA 上停用账号，停用后账号下的证书不再自动续签
+func (s *CertImpl) UserDeactivate(ID uint) error {
+	var user models.CertUser
+	if err := facades.Orm().Query().Where("id = ?", ID).FirstOrFail(&user); err != nil {
+		return err
+	}
+	if user.Status == acme.AccountStatusDeactivated {
+		return errors.New("该账号已停用")
+	}
+
+	client, err := s.userClient(user)
 	if err != nil {
-		return errors.New("获取私钥失败")
+		return fmt.Errorf("获取 CA 账号失败: %w", err)
+	}
+	if err = client.Deactivate(context.Background()); err != nil {
+		return fmt.Errorf("停用 CA 账号失败: %w", err)
+	}
+
+	if _, err = facades.Orm().Query().Model(&models.CertUser{}).Where("id", ID).Update("status", acme.AccountStatusDeactivated); err != nil {
+		return err
+	}
+	_, err = facades.Orm().Query().Model(&models.Cert{}).Where("user_id", ID).Update("auto_renew", false)
+	return err
+}
+
+// UserAccount 获取账号在 CA 上的信息和账号下的证书
+// 多数 CA（如 Let's Encrypt）不提供订单列表，订单以账号下证书的签发记录为准
+func (s *CertImpl) UserAccount(ID uint) (internal.CertAccount, error) {
+	var user models.CertUser
+	if err := facades.Orm().Query().Where("id = ?", ID).FirstOrFail(&user); err != nil {
+		return internal.CertAccount{}, err
+	}
+
+	account := internal.CertAccount{Status: user.Status, Certs: []internal.CertAccountCert{}}
+	if user.Status != acme.AccountStatusDeactivated {
+		if client, err := s.userClient(user); err != nil {
+			account.Error = err.Error()
+		} else {
+			account.Status = client.Account.Status
+			account.Location = client.Account.Location
+			account.Contact = client.Account.Contact
+			account.OrdersURL = client.Account.Orders
+		}
+	}
+
+	var certs []models.Cert
+	if err := facades.Orm().Query().With("Task").Where("user_id = ?", ID).Order("id desc").Find(&certs); err != nil {
+		return internal.CertAccount{}, err
+	}
+	for _, cert := range certs {
+		item := internal.CertAccountCert{
+			ID:         cert.ID,
+			Domains:    cert.Domains,
+			CertURL:    cert.CertURL,
+			AutoRenew:  cert.AutoRenew,
+			RenewAt:    cert.RenewAt,
+			RenewError: cert.RenewError,
+			Task:       cert.Task,
+		}
+		if chain, err := acme.ParseCertificates([]byte(cert.Cert)); err == nil {
+			notAfter := carbon.DateTime{Carbon: carbon.FromStdTime(chain[0].NotAfter)}
+			item.NotAfter = &notAfter
+		}
+		account.Certs = append(account.Certs, item)
 	}
-	user.PrivateKey = string(privateKey)
 
-	return facades.Orm().Query().Save(&user)
+	return account, nil
 }
 
 // UserShow 根据 ID 获取用户
@@ -188,7 +256,11 @@ func (s *CertImpl) CertStore(request requests.CertStore) error {
 	cert.DNSID = request.DNSID
 	cert.WebsiteID = request.WebsiteID
 
-	return facades.Orm().Query().Create(&cert)
+	if err := facades.Orm().Query().Create(&cert); err != nil {
+		return err
+	}
+
+	return s.link(cert)
 }
 
 // CertUpdate 更新证书
@@ -206,13 +278,17 @@ func (s *CertImpl) CertUpdate(request requests.CertUpdate) error {
 	cert.DNSID = request.DNSID
 	cert.WebsiteID = request.WebsiteID
 
-	return facades.Orm().Query().Save(&cert)
+	if err = facades.Orm().Query().Save(&cert); err != nil {
+		return err
+	}
+
+	return s.link(cert)
 }
 
 // CertShow 根据 ID 获取证书
 func (s *CertImpl) CertShow(ID uint) (models.Cert, error) {
 	var cert models.Cert
-	err := facades.Orm().Query().With("User").With("DNS").With("Website").Where("id = ?", ID).First(&cert)
+	err := facades.Orm().Query().With("User").With("DNS").With("Website").With("CA").Where("id = ?", ID).First(&cert)
 
 	return cert, err
 }
@@ -225,67 +301,138 @@ func (s *CertImpl) CertDestroy(ID uint) error {
 		return err
 	}
 
+	// 私有 CA 签发的证书删除后不应继续被信任
+	if cert.CAID != nil && cert.RevokedAt == nil {
+		if err = NewCertAuthorityImpl().Revoke(ID); err != nil {
+			return err
+		}
+	}
+
+	if s.IsPanelCert(cert) {
+		if err = NewSettingImpl().Delete(models.SettingKeyPanelCertID); err != nil {
+			return err
+		}
+	}
+
+	if _, err = facades.Orm().Query().Where("cert_id", ID).Delete(&models.CertDeployment{}); err != nil {
+		return err
+	}
+	if _, err = facades.Orm().Query().Where("cert_id", ID).Delete(&models.CertTarget{}); err != nil {
+		return err
+	}
+
 	_, err = facades.Orm().Query().Delete(&models.Cert{}, ID)
 	return err
 }
 
-// ObtainAuto 自动签发证书
-func (s *CertImpl) ObtainAuto(ID uint) (acme.Certificate, error) {
-	var cert models.Cert
-	err := facades.Orm().Query().With("Website").With("User").With("DNS").Where("id = ?", ID).First(&cert)
+// CSRStore 为商业证书生成私钥和 CSR，私钥保存在证书中等待导入 CA 签发的证书
+func (s *CertImpl) CSRStore(request requests.CSRStore) (models.Cert, error) {
+	csr, key, err := acme.GenerateCSR(request.Domains, acme.KeyType(request.Type), acme.CSRSubject{
+		Organization:       request.Organization,
+		OrganizationalUnit: request.OrganizationalUnit,
+		Country:            request.Country,
+		Province:           request.Province,
+		Locality:           request.Locality,
+	})
 	if err != nil {
-		return acme.Certificate{}, err
+		return models.Cert{}, err
 	}
 
-	client, err := s.getClient(cert)
-	if err != nil {
-		return acme.Certificate{}, err
+	cert := models.Cert{
+		Type:      request.Type,
+		Domains:   request.Domains,
+		AutoRenew: false,
+		WebsiteID: request.WebsiteID,
+		Key:       string(key),
+		CSR:       string(csr),
+	}
+	if err = facades.Orm().Query().Create(&cert); err != nil {
+		return models.Cert{}, err
 	}
 
-	if cert.DNS != nil {
-		client.UseDns(acme.DnsType(cert.DNS.Type), cert.DNS.Data)
-	} else {
-		if cert.Website == nil {
-			return acme.Certificate{}, errors.New("该证书没有关联网站，无法自动签发")
-		} else {
-			for _, domain := range cert.Domains {
-				if strings.Contains(domain, "*") {
-					return acme.Certificate{}, errors.New("通配符域名无法使用 HTTP 验证")
-				}
-			}
-			client.UseHTTP(cert.Website.Path)
+	return cert, nil
+}
+
+// Import 导入 CA 根据 CSR 签发的证书，校验与待签发的私钥匹配后部署
+// 商业证书到期后可使用同一 CSR 重新签发并再次导入
+func (s *CertImpl) Import(request requests.CertImport) error {
+	var cert models.Cert
+	if err := facades.Orm().Query().Where("id = ?", request.ID).FirstOrFail(&cert); err != nil {
+		return err
+	}
+	if len(cert.CSR) == 0 || len(cert.Key) == 0 {
+		return errors.New("该证书不是通过 CSR 创建的，无法导入")
+	}
+
+	chain, err := acme.MatchCertificate([]byte(request.Cert), []byte(request.Chain), []byte(cert.Key))
+	if err != nil {
+		return err
+	}
+	certs, err := acme.ParseCertificates(chain)
+	if err != nil {
+		return err
+	}
+	for _, domain := range cert.Domains {
+		if certs[0].VerifyHostname(domain) != nil {
+			return fmt.Errorf("证书不包含域名 %s", domain)
 		}
 	}
+	if time.Now().After(certs[0].NotAfter) {
+		return errors.New("证书已过期")
+	}
+
+	cert.Cert = string(chain)
+	cert.RenewError = ""
+	if err = facades.Orm().Query().Save(&cert); err != nil {
+		return err
+	}
+
+	return s.deployAll(cert, io.Discard)
+}
+
+// Download 按格式导出证书，返回文件名和文件内容
+func (s *CertImpl) Download(request requests.CertDownload) (string, acme.Exported, error) {
+	var cert models.Cert
+	if err := facades.Orm().Query().Where("id = ?", request.ID).FirstOrFail(&cert); err != nil {
+		return "", acme.Exported{}, err
+	}
+	if len(cert.Cert) == 0 {
+		return "", acme.Exported{}, errors.New("该证书尚未签发")
+	}
 
-	ssl, err := client.ObtainSSL(context.Background(), cert.Domains, acme.KeyType(cert.Type))
+	exported, err := acme.Export([]byte(cert.Cert), []byte(cert.Key), acme.ExportFormat(request.Format), request.Password)
 	if err != nil {
-		return acme.Certificate{}, err
+		return "", acme.Exported{}, err
 	}
 
-	cert.CertURL = &ssl.URL
-	cert.Cert = string(ssl.ChainPEM)
-	cert.Key = string(ssl.PrivateKey)
-	err = facades.Orm().Query().Save(&cert)
+	name := "cert"
+	if len(cert.Domains) > 0 {
+		name = strings.ReplaceAll(cert.Domains[0], "*", "_")
+	}
+
+	return name + exported.Extension, exported, nil
+}
+
+// ObtainAuto 自动签发证书，签发过程的每个步骤写入 log
+func (s *CertImpl) ObtainAuto(ID uint, log io.Writer) (acme.Certificate, error) {
+	var cert models.Cert
+	err := facades.Orm().Query().With("Website").With("User").With("DNS").Where("id = ?", ID).First(&cert)
 	if err != nil {
 		return acme.Certificate{}, err
 	}
 
-	if cert.Website != nil {
-		if err = tools.Write("/www/server/vhost/ssl/"+cert.Website.Name+".pem", cert.Cert, 0644); err != nil {
-			return acme.Certificate{}, err
-		}
-		if err = tools.Write("/www/server/vhost/ssl/"+cert.Website.Name+".key", cert.Key, 0644); err != nil {
-			return acme.Certificate{}, err
-		}
-		if err = tools.ServiceReload("openresty"); err != nil {
-			return acme.Certificate{}, err
+	ssl, err := s.issue(cert, false, log)
+	if err != nil && len(ssl.ChainPEM) == 0 {
+		cert.RenewError = err.Error()
+		if err := facades.Orm().Query().Save(&cert); err != nil {
+			return ssl, err
 		}
 	}
 
-	return ssl, nil
+	return ssl, err
 }
 
-// ObtainManual 手动签发证书
+// ObtainManual 手动签发证书，需要在同一进程中先调用 ManualDNS，因此不作为后台任务运行
 func (s *CertImpl) ObtainManual(ID uint) (acme.Certificate, error) {
 	var cert models.Cert
 	err := facades.Orm().Query().With("User").Where("id = ?", ID).First(&cert)
@@ -305,21 +452,14 @@ func (s *CertImpl) ObtainManual(ID uint) (acme.Certificate, error) {
 	cert.CertURL = &ssl.URL
 	cert.Cert = string(ssl.ChainPEM)
 	cert.Key = string(ssl.PrivateKey)
+	s.schedule(&cert, ssl.RenewalInfo)
 	err = facades.Orm().Query().Save(&cert)
 	if err != nil {
 		return acme.Certificate{}, err
 	}
 
-	if cert.Website != nil {
-		if err = tools.Write("/www/server/vhost/ssl/"+cert.Website.Name+".pem", cert.Cert, 0644); err != nil {
-			return acme.Certificate{}, err
-		}
-		if err = tools.Write("/www/server/vhost/ssl/"+cert.Website.Name+".key", cert.Key, 0644); err != nil {
-			return acme.Certificate{}, err
-		}
-		if err = tools.ServiceReload("openresty"); err != nil {
-			return acme.Certificate{}, err
-		}
+	if err = s.deployAll(cert, io.Discard); err != nil {
+		return ssl, err
 	}
 
 	return ssl, nil
@@ -350,67 +490,243 @@ func (s *CertImpl) ManualDNS(ID uint) ([]acme.DNSRecord, error) {
 	return records, err
 }
 
-// Renew 续签证书
-func (s *CertImpl) Renew(ID uint) (acme.Certificate, error) {
+// Renew 续签证书，续签过程的每个步骤写入 log，失败时按退避间隔安排下次重试
+func (s *CertImpl) Renew(ID uint, log io.Writer) (acme.Certificate, error) {
 	var cert models.Cert
 	err := facades.Orm().Query().With("Website").With("User").With("DNS").Where("id = ?", ID).First(&cert)
 	if err != nil {
 		return acme.Certificate{}, err
 	}
 
-	client, err := s.getClient(cert)
-	if err != nil {
-		return acme.Certificate{}, err
+	ssl, err := s.issue(cert, true, log)
+	if err != nil && len(ssl.ChainPEM) == 0 {
+		cert.RenewFailures++
+		cert.RenewError = err.Error()
+		renewAt := carbon.DateTime{Carbon: carbon.FromStdTime(time.Now().Add(acme.RetryBackoff(cert.RenewFailures)))}
+		cert.RenewAt = &renewAt
+		if err := facades.Orm().Query().Save(&cert); err != nil {
+			return ssl, err
+		}
+	}
+
+	return ssl, err
+}
+
+// ObtainTask 创建自动签发证书的后台任务
+func (s *CertImpl) ObtainTask(ID uint) (models.Task, error) {
+	return s.createTask(ID, "obtain", "签发证书")
+}
+
+// RenewTask 创建续签证书的后台任务
+func (s *CertImpl) RenewTask(ID uint) (models.Task, error) {
+	return s.createTask(ID, "renew", "续签证书")
+}
+
+// TaskStatus 获取证书最近一次签发或续签任务的状态和日志
+func (s *CertImpl) TaskStatus(ID uint) (internal.CertTaskStatus, error) {
+	var cert models.Cert
+	if err := facades.Orm().Query().With("Task").Where("id = ?", ID).FirstOrFail(&cert); err != nil {
+		return internal.CertTaskStatus{}, err
+	}
+
+	status := internal.CertTaskStatus{Task: cert.Task, Error: cert.RenewError}
+	if cert.Task != nil && tools.Exists(cert.Task.Log) {
+		status.Log, _ = tools.Exec(`tail -n 500 '` + cert.Task.Log + `'`)
 	}
 
-	if cert.CertURL == nil {
+	return status, nil
+}
+
+// createTask 创建证书后台任务，同一证书同时只能有一个任务
+func (s *CertImpl) createTask(ID uint, action, name string) (models.Task, error) {
+	var cert models.Cert
+	if err := facades.Orm().Query().With("Task").Where("id = ?", ID).FirstOrFail(&cert); err != nil {
+		return models.Task{}, err
+	}
+	if cert.Task != nil && (cert.Task.Status == models.TaskStatusWaiting || cert.Task.Status == models.TaskStatusRunning) {
+		return models.Task{}, errors.New("该证书已有正在进行的任务")
+	}
+
+	logFile := "/tmp/cert_" + action + "_" + strconv.Itoa(int(ID)) + "_" + carbon.Now().ToShortDateTimeString() + ".log"
+	var task models.Task
+	task.Name = name + " " + strings.Join(cert.Domains, ",")
+	task.Status = models.TaskStatusWaiting
+	task.Shell = `panel cert ` + action + ` ` + strconv.Itoa(int(ID)) + ` >> '` + logFile + `' 2>&1`
+	task.Log = logFile
+	if err := facades.Orm().Query().Create(&task); err != nil {
+		return models.Task{}, errors.New("创建任务失败")
+	}
+	if _, err := facades.Orm().Query().Model(&models.Cert{}).Where("id", ID).Update("task_id", task.ID); err != nil {
+		return models.Task{}, err
+	}
+
+	s.task.Process(task.ID)
+	return task, nil
+}
+
+// issue 签发或续签证书并部署
+func (s *CertImpl) issue(cert models.Cert, renew bool, log io.Writer) (acme.Certificate, error) {
+	if renew && cert.CertURL == nil {
 		return acme.Certificate{}, errors.New("该证书没有签发成功，无法续签")
 	}
 
+	client, err := s.getClient(cert)
+	if err != nil {
+		return acme.Certificate{}, err
+	}
+	_, _ = fmt.Fprintf(log, "|-使用 ACME 账号 %s (%s)\n", cert.User.Email, cert.User.CA)
+	client.SetLog(log)
+
 	if cert.DNS != nil {
 		client.UseDns(acme.DnsType(cert.DNS.Type), cert.DNS.Data)
+		_, _ = fmt.Fprintf(log, "|-使用 DNS 验证 (%s)\n", cert.DNS.Name)
+	} else if s.IsPanelCert(cert) {
+		for _, domain := range cert.Domains {
+			if strings.Contains(domain, "*") {
+				return acme.Certificate{}, errors.New("通配符域名无法使用 HTTP 验证")
+			}
+		}
+		// 80 端口通常由 OpenResty 占用，面板域名没有绑定网站时由默认站点响应，验证文件写入默认站点目录
+		if tools.Exists("/www/server/openresty/html") {
+			client.UseHTTP("/www/server/openresty/html")
+			_, _ = fmt.Fprintln(log, "|-使用 HTTP 验证 (OpenResty 默认站点)")
+		} else {
+			client.UseHTTPServer(":80")
+			_, _ = fmt.Fprintln(log, "|-使用 HTTP 验证 (临时监听 80 端口)")
+		}
 	} else {
 		if cert.Website == nil {
-			return acme.Certificate{}, errors.New("该证书没有关联网站，无法续签，可以尝试手动签发")
-		} else {
-			for _, domain := range cert.Domains {
-				if strings.Contains(domain, "*") {
-					return acme.Certificate{}, errors.New("通配符域名无法使用 HTTP 验证")
-				}
+			if renew {
+				return acme.Certificate{}, errors.New("该证书没有关联网站，无法续签，可以尝试手动签发")
 			}
-			client.UseHTTP(cert.Website.Path)
+			return acme.Certificate{}, errors.New("该证书没有关联网站，无法自动签发")
 		}
+		for _, domain := range cert.Domains {
+			if strings.Contains(domain, "*") {
+				return acme.Certificate{}, errors.New("通配符域名无法使用 HTTP 验证")
+			}
+		}
+		client.UseHTTP(cert.Website.Path)
+		_, _ = fmt.Fprintf(log, "|-使用 HTTP 验证 (网站 %s)\n", cert.Website.Name)
 	}
 
-	ssl, err := client.RenewSSL(context.Background(), *cert.CertURL, cert.Domains, acme.KeyType(cert.Type))
+	var ssl acme.Certificate
+	if renew {
+		ssl, err = client.RenewSSL(context.Background(), *cert.CertURL, cert.Domains, acme.KeyType(cert.Type))
+	} else {
+		ssl, err = client.ObtainSSL(context.Background(), cert.Domains, acme.KeyType(cert.Type))
+	}
 	if err != nil {
 		return acme.Certificate{}, err
 	}
+	_, _ = fmt.Fprintln(log, "|-证书签发成功")
 
 	cert.CertURL = &ssl.URL
 	cert.Cert = string(ssl.ChainPEM)
 	cert.Key = string(ssl.PrivateKey)
+	s.schedule(&cert, ssl.RenewalInfo)
 	err = facades.Orm().Query().Save(&cert)
 	if err != nil {
 		return acme.Certificate{}, err
 	}
 
-	if cert.Website != nil {
-		if err = tools.Write("/www/server/vhost/ssl/"+cert.Website.Name+".pem", cert.Cert, 0644); err != nil {
-			return acme.Certificate{}, err
-		}
-		if err = tools.Write("/www/server/vhost/ssl/"+cert.Website.Name+".key", cert.Key, 0644); err != nil {
-			return acme.Certificate{}, err
+	if err = s.deployAll(cert, log); err != nil {
+		return ssl, err
+	}
+
+	return ssl, nil
+}
+
+// RefreshRenewal 查询 CA 的 ARI 续签信息并更新续签计划，CA 不支持 ARI 时在有效期的三分之二处续签
+func (s *CertImpl) RefreshRenewal(ID uint) error {
+	var cert models.Cert
+	err := facades.Orm().Query().With("User").Where("id = ?", ID).First(&cert)
+	if err != nil {
+		return err
+	}
+
+	if cert.CertURL == nil || cert.Cert == "" {
+		return errors.New("该证书没有签发成功，无法计划续签")
+	}
+
+	var ari *acme.RenewalInfo
+	if cert.User != nil {
+		if client, err := s.getClient(cert); err == nil {
+			if info, err := client.RenewalInfo(context.Background(), []byte(cert.Cert)); err == nil {
+				ari = &info
+			}
 		}
-		if err = tools.ServiceReload("openresty"); err != nil {
-			return acme.Certificate{}, err
+	}
+
+	// 续签失败重试期间保留重试时间
+	if cert.RenewFailures == 0 {
+		switch {
+		case ari != nil && ari.HasWindow():
+			// 建议窗口未变化时保留已选择的时间
+			if cert.RenewAt == nil || !acme.InWindow(cert.RenewAt.ToStdTime(), *ari) {
+				if err = s.setRenewAt(&cert, ari); err != nil {
+					return err
+				}
+			}
+			cert.RenewExplanationURL = ari.ExplanationURL
+		case cert.RenewAt == nil:
+			if err = s.setRenewAt(&cert, nil); err != nil {
+				return err
+			}
 		}
 	}
+	checkAt := carbon.DateTime{Carbon: carbon.FromStdTime(acme.RenewalCheckTime(ari))}
+	cert.RenewCheckAt = &checkAt
 
-	return ssl, nil
+	return facades.Orm().Query().Save(&cert)
+}
+
+// schedule 证书签发或续签成功后重新计划续签
+func (s *CertImpl) schedule(cert *models.Cert, ari *acme.RenewalInfo) {
+	cert.RenewFailures = 0
+	cert.RenewError = ""
+	cert.RenewExplanationURL = ""
+	if ari != nil {
+		cert.RenewExplanationURL = ari.ExplanationURL
+	}
+	checkAt := carbon.DateTime{Carbon: carbon.FromStdTime(acme.RenewalCheckTime(ari))}
+	cert.RenewCheckAt = &checkAt
+	if err := s.setRenewAt(cert, ari); err != nil {
+		cert.RenewAt = nil
+	}
+}
+
+func (s *CertImpl) setRenewAt(cert *models.Cert, ari *acme.RenewalInfo) error {
+	renewAt, err := acme.RenewalTime([]byte(cert.Cert), ari)
+	if err != nil {
+		return err
+	}
+
+	at := carbon.DateTime{Carbon: carbon.FromStdTime(renewAt)}
+	cert.RenewAt = &at
+	return nil
+}
+
+// Inspect 检查证书链、私钥、域名覆盖和 OCSP 状态
+func (s *CertImpl) Inspect(ID uint) (acme.Inspection, error) {
+	var cert models.Cert
+	err := facades.Orm().Query().With("CA").Where("id = ?", ID).FirstOrFail(&cert)
+	if err != nil {
+		return acme.Inspection{}, err
+	}
+	if cert.Cert == "" {
+		return acme.Inspection{}, errors.New("该证书没有签发成功，无法检查")
+	}
+
+	var roots []byte
+	if cert.CA != nil {
+		roots = []byte(cert.CA.RootCert)
+	}
+
+	return acme.Inspect(context.Background(), []byte(cert.Cert), []byte(cert.Key), cert.Domains, roots)
 }
 
-// Deploy 部署证书
+// Deploy 部署证书到网站，并在证书签发和续签后自动重新部署
 func (s *CertImpl) Deploy(ID, WebsiteID uint) error {
 	var cert models.Cert
 	err := facades.Orm().Query().Where("id = ?", ID).First(&cert)
@@ -422,43 +738,394 @@ func (s *CertImpl) Deploy(ID, WebsiteID uint) error {
 		return errors.New("该证书没有签发成功，无法部署")
 	}
 
-	website := models.Website{}
-	err = facades.Orm().Query().Where("id = ?", WebsiteID).First(&website)
+	var deployment models.CertDeployment
+	if err = facades.Orm().Query().Where("cert_id", ID).Where("website_id", WebsiteID).FirstOrCreate(&deployment, models.CertDeployment{CertID: ID, WebsiteID: WebsiteID}); err != nil {
+		return err
+	}
+	if err = s.deploy(cert, &deployment); err != nil {
+		return err
+	}
+
+	return tools.ServiceReload("openresty")
+}
+
+// Undeploy 取消证书与网站的关联，不会删除网站上已部署的证书
+func (s *CertImpl) Undeploy(ID, WebsiteID uint) error {
+	var cert models.Cert
+	if err := facades.Orm().Query().Where("id = ?", ID).First(&cert); err != nil {
+		return err
+	}
+	if cert.WebsiteID != nil && *cert.WebsiteID == WebsiteID {
+		return errors.New("该网站用于证书的 HTTP 验证，请先修改证书的关联网站")
+	}
+
+	_, err := facades.Orm().Query().Where("cert_id", ID).Where("website_id", WebsiteID).Delete(&models.CertDeployment{})
+	return err
+}
+
+// Deployments 获取证书的部署情况
+func (s *CertImpl) Deployments(ID uint) ([]internal.CertDeploymentStatus, error) {
+	var cert models.Cert
+	if err := facades.Orm().Query().With("Deployments.Website").Where("id = ?", ID).First(&cert); err != nil {
+		return nil, err
+	}
+	fingerprint, _ := acme.Fingerprint([]byte(cert.Cert))
+
+	statuses := make([]internal.CertDeploymentStatus, 0, len(cert.Deployments))
+	for _, deployment := range cert.Deployments {
+		status := internal.CertDeploymentStatus{
+			WebsiteID:   deployment.WebsiteID,
+			Fingerprint: deployment.Fingerprint,
+			DeployedAt:  deployment.DeployedAt,
+		}
+		if deployment.Website == nil {
+			status.Error = "网站不存在"
+			statuses = append(statuses, status)
+			continue
+		}
+		status.WebsiteName = deployment.Website.Name
+		status.Ssl = deployment.Website.Ssl
+
+		// 以网站上实际使用的证书判断是否为最新
+		deployed, err := tools.Read("/www/server/vhost/ssl/" + deployment.Website.Name + ".pem")
+		if err != nil {
+			status.Error = "读取网站证书失败"
+		} else if status.ActualFingerprint, err = acme.Fingerprint([]byte(deployed)); err != nil {
+			status.Error = "网站证书格式错误"
+		}
+		status.Current = len(fingerprint) > 0 && status.ActualFingerprint == fingerprint
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// link 关联证书用于 HTTP 验证的网站
+func (s *CertImpl) link(cert models.Cert) error {
+	if cert.WebsiteID == nil {
+		return nil
+	}
+
+	var deployment models.CertDeployment
+	return facades.Orm().Query().Where("cert_id", cert.ID).Where("website_id", *cert.WebsiteID).FirstOrCreate(&deployment, models.CertDeployment{CertID: cert.ID, WebsiteID: *cert.WebsiteID})
+}
+
+// deploy 将证书写入网站，不重载 OpenResty
+func (s *CertImpl) deploy(cert models.Cert, deployment *models.CertDeployment) error {
+	var website models.Website
+	if err := facades.Orm().Query().Where("id = ?", deployment.WebsiteID).FirstOrFail(&website); err != nil {
+		return err
+	}
+
+	if err := tools.Write("/www/server/vhost/ssl/"+website.Name+".pem", cert.Cert, 0644); err != nil {
+		return err
+	}
+	if err := tools.Write("/www/server/vhost/ssl/"+website.Name+".key", cert.Key, 0644); err != nil {
+		return err
+	}
+
+	fingerprint, err := acme.Fingerprint([]byte(cert.Cert))
 	if err != nil {
 		return err
 	}
+	now := carbon.DateTime{Carbon: carbon.Now()}
+	deployment.Fingerprint = fingerprint
+	deployment.DeployedAt = &now
+
+	return facades.Orm().Query().Save(deployment)
+}
+
+// deployAll 将证书部署到所有关联的网站和部署目标
+func (s *CertImpl) deployAll(cert models.Cert, log io.Writer) error {
+	if err := s.link(cert); err != nil {
+		return err
+	}
+
+	var deployments []models.CertDeployment
+	if err := facades.Orm().Query().With("Website").Where("cert_id", cert.ID).Find(&deployments); err != nil {
+		return err
+	}
+	var failed []string
+	for i := range deployments {
+		name := strconv.Itoa(int(deployments[i].WebsiteID))
+		if deployments[i].Website != nil {
+			name = deployments[i].Website.Name
+		}
+		if err := s.deploy(cert, &deployments[i]); err != nil {
+			failed = append(failed, fmt.Sprintf("网站 %s: %v", name, err))
+			_, _ = fmt.Fprintf(log, "|-部署到网站 %s 失败: %v\n", name, err)
+			continue
+		}
+		_, _ = fmt.Fprintf(log, "|-部署到网站 %s\n", name)
+	}
+	if len(deployments) > 0 {
+		if err := tools.ServiceReload("openresty"); err != nil {
+			failed = append(failed, err.Error())
+			_, _ = fmt.Fprintf(log, "|-重载 OpenResty 失败: %v\n", err)
+		}
+	}
+
+	if s.IsPanelCert(cert) {
+		if err := s.deployPanel(cert); err != nil {
+			failed = append(failed, fmt.Sprintf("面板: %v", err))
+			_, _ = fmt.Fprintf(log, "|-部署到面板失败: %v\n", err)
+		} else {
+			_, _ = fmt.Fprintln(log, "|-部署到面板")
+		}
+	}
+
+	var targets []models.CertTarget
+	if err := facades.Orm().Query().Where("cert_id", cert.ID).Find(&targets); err != nil {
+		return err
+	}
+	for i := range targets {
+		if err := s.deployTarget(cert, &targets[i]); err != nil {
+			failed = append(failed, fmt.Sprintf("目标 %s: %v", targets[i].Name, err))
+			_, _ = fmt.Fprintf(log, "|-部署到目标 %s 失败: %v\n", targets[i].Name, err)
+			continue
+		}
+		_, _ = fmt.Fprintf(log, "|-部署到目标 %s\n", targets[i].Name)
+	}
+
+	if len(failed) > 0 {
+		return errors.New("证书已更新，但部分网站部署失败: " + strings.Join(failed, "; "))
+	}
+
+	return nil
+}
+
+// IsPanelCert 判断证书是否绑定到面板
+func (s *CertImpl) IsPanelCert(cert models.Cert) bool {
+	return NewSettingImpl().Get(models.SettingKeyPanelCertID) == strconv.Itoa(int(cert.ID))
+}
+
+// deployPanel 将证书写入面板的证书文件，面板未开启 HTTPS 时开启并在任务结束后重启面板
+// 面板运行时会检测证书文件的变化，续签后无需重启
+func (s *CertImpl) deployPanel(cert models.Cert) error {
+	if err := tools.Write(facades.Config().GetString("http.tls.ssl.key"), cert.Key, 0600); err != nil {
+		return err
+	}
+	if err := tools.Write(facades.Config().GetString("http.tls.ssl.cert"), cert.Cert, 0644); err != nil {
+		return err
+	}
+
+	if !facades.Config().GetBool("panel.ssl") {
+		setting := NewSettingImpl()
+		if err := setting.UpdateSSL(true); err != nil {
+			return err
+		}
+		return setting.ScheduleRestart()
+	}
+
+	return nil
+}
+
+// TargetList 获取证书的部署目标
+func (s *CertImpl) TargetList(ID uint) ([]models.CertTarget, error) {
+	var targets []models.CertTarget
+	err := facades.Orm().Query().Where("cert_id = ?", ID).Order("id asc").Find(&targets)
+
+	return targets, err
+}
+
+// TargetStore 添加部署目标
+func (s *CertImpl) TargetStore(request requests.TargetStore) error {
+	target := models.CertTarget{CertID: request.ID}
+	s.fillTarget(&target, request)
+
+	return facades.Orm().Query().Create(&target)
+}
 
-	if err = tools.Write("/www/server/vhost/ssl/"+website.Name+".pem", cert.Cert, 0644); err != nil {
+// TargetUpdate 更新部署目标
+func (s *CertImpl) TargetUpdate(request requests.TargetUpdate) error {
+	var target models.CertTarget
+	if err := facades.Orm().Query().Where("id = ?", request.ID).FirstOrFail(&target); err != nil {
 		return err
 	}
-	if err = tools.Write("/www/server/vhost/ssl/"+website.Name+".key", cert.Key, 0644); err != nil {
+	s.fillTarget(&target, requests.TargetStore(request))
+
+	return facades.Orm().Query().Save(&target)
+}
+
+// TargetDestroy 删除部署目标，不会删除已写入的文件
+func (s *CertImpl) TargetDestroy(ID uint) error {
+	_, err := facades.Orm().Query().Delete(&models.CertTarget{}, ID)
+	return err
+}
+
+// TargetDeploy 立即部署证书到目标
+func (s *CertImpl) TargetDeploy(ID uint) error {
+	var target models.CertTarget
+	if err := facades.Orm().Query().With("Cert").Where("id = ?", ID).FirstOrFail(&target); err != nil {
 		return err
 	}
-	if err = tools.ServiceReload("openresty"); err != nil {
+	if target.Cert == nil || target.Cert.Cert == "" || target.Cert.Key == "" {
+		return errors.New("该证书没有签发成功，无法部署")
+	}
+
+	return s.deployTarget(*target.Cert, &target)
+}
+
+func (s *CertImpl) fillTarget(target *models.CertTarget, request requests.TargetStore) {
+	target.Name = request.Name
+	target.CertPath = request.CertPath
+	target.KeyPath = request.KeyPath
+	target.FullchainPath = request.FullchainPath
+	target.PfxPath = request.PfxPath
+	// 密码不会返回给前端，未填写时保留原密码，不再导出 PFX 时清空
+	if len(request.PfxPassword) > 0 || len(request.PfxPath) == 0 {
+		target.PfxPassword = request.PfxPassword
+	}
+	target.Mode = request.Mode
+	target.Owner = request.Owner
+	target.Service = request.Service
+	target.Command = request.Command
+	if len(target.Mode) == 0 {
+		target.Mode = "0600"
+	}
+}
+
+// deployTarget 部署证书到目标并记录结果
+func (s *CertImpl) deployTarget(cert models.Cert, target *models.CertTarget) error {
+	err := s.writeTarget(cert, *target)
+
+	now := carbon.DateTime{Carbon: carbon.Now()}
+	target.DeployedAt = &now
+	if err != nil {
+		target.Status = models.CertTargetStatusFailed
+		target.Error = err.Error()
+	} else {
+		target.Status = models.CertTargetStatusSuccess
+		target.Error = ""
+		target.Fingerprint, _ = acme.Fingerprint([]byte(cert.Cert))
+	}
+	if saveErr := facades.Orm().Query().Save(target); saveErr != nil && err == nil {
+		err = saveErr
+	}
+
+	return err
+}
+
+// writeTarget 写入证书文件并执行部署后操作
+func (s *CertImpl) writeTarget(cert models.Cert, target models.CertTarget) error {
+	mode, err := strconv.ParseUint(target.Mode, 8, 32)
+	if err != nil || mode == 0 {
+		mode = 0600
+	}
+	leaf, err := acme.LeafPEM([]byte(cert.Cert))
+	if err != nil {
 		return err
 	}
 
+	files := map[string][]byte{
+		target.CertPath: leaf,
+		target.KeyPath:  []byte(cert.Key),
+	}
+	if len(target.FullchainPath) > 0 {
+		files[target.FullchainPath] = []byte(cert.Cert)
+	}
+	if len(target.PfxPath) > 0 {
+		pfx, err := acme.EncodePFX([]byte(cert.Cert), []byte(cert.Key), target.PfxPassword)
+		if err != nil {
+			return fmt.Errorf("生成 PFX 失败: %w", err)
+		}
+		files[target.PfxPath] = pfx
+	}
+
+	for path, content := range files {
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err = tools.Write(path, string(content), os.FileMode(mode)); err != nil {
+			return err
+		}
+		// 文件已存在时 Write 不会修改权限
+		if err = os.Chmod(path, os.FileMode(mode)); err != nil {
+			return err
+		}
+		if len(target.Owner) > 0 {
+			user, group, found := strings.Cut(target.Owner, ":")
+			if !found {
+				group = user
+			}
+			if err = tools.Chown(path, user, group); err != nil {
+				return fmt.Errorf("修改 %s 的所有者失败: %w", path, err)
+			}
+		}
+	}
+
+	if len(target.Service) > 0 {
+		if err = tools.ServiceReload(target.Service); err != nil {
+			return fmt.Errorf("重载服务 %s 失败: %w", target.Service, err)
+		}
+	}
+	if len(strings.TrimSpace(target.Command)) > 0 {
+		if _, err = tools.Exec(target.Command); err != nil {
+			return fmt.Errorf("部署后命令执行失败: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// getClient 获取证书关联账号的 ACME 客户端
 func (s *CertImpl) getClient(cert models.Cert) (*acme.Client, error) {
-	var ca string
-	var eab *acme.EAB
-	switch cert.User.CA {
+	if cert.User == nil {
+		return nil, errors.New("该证书没有关联 ACME 账号")
+	}
+	if cert.User.Status == acme.AccountStatusDeactivated {
+		return nil, errors.New("该证书关联的 ACME 账号已停用")
+	}
+
+	return s.userClient(*cert.User)
+}
+
+// userClient 使用已保存的私钥获取账号的 ACME 客户端
+func (s *CertImpl) userClient(user models.CertUser) (*acme.Client, error) {
+	ca, eab, err := s.caDirectory(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return acme.NewPrivateKeyAccount(user.Email, user.PrivateKey, ca, eab)
+}
+
+// register 向 CA 注册新账号并保存私钥
+func (s *CertImpl) register(user *models.CertUser) error {
+	ca, eab, err := s.caDirectory(*user)
+	if err != nil {
+		return err
+	}
+
+	client, err := acme.NewRegisterAccount(context.Background(), user.Email, ca, eab, acme.KeyType(user.KeyType))
+	if err != nil {
+		return errors.New("向 CA 注册账号失败，请检查参数是否正确")
+	}
+
+	privateKey, err := acme.EncodePrivateKey(client.Account.PrivateKey)
+	if err != nil {
+		return errors.New("获取私钥失败")
+	}
+	user.PrivateKey = string(privateKey)
+	user.Status = acme.AccountStatusValid
+
+	return nil
+}
+
+// caDirectory 获取账号所属 CA 的目录地址和 EAB
+func (s *CertImpl) caDirectory(user models.CertUser) (string, *acme.EAB, error) {
+	switch user.CA {
 	case "letsencrypt":
-		ca = acme.CALetsEncrypt
+		return acme.CALetsEncrypt, nil, nil
 	case "buypass":
-		ca = acme.CABuypass
+		return acme.CABuypass, nil, nil
 	case "zerossl":
-		ca = acme.CAZeroSSL
-		eab = &acme.EAB{KeyID: *cert.User.Kid, MACKey: *cert.User.HmacEncoded}
+		return acme.CAZeroSSL, &acme.EAB{KeyID: cast.ToString(user.Kid), MACKey: cast.ToString(user.HmacEncoded)}, nil
 	case "sslcom":
-		ca = acme.CASSLcom
-		eab = &acme.EAB{KeyID: *cert.User.Kid, MACKey: *cert.User.HmacEncoded}
+		return acme.CASSLcom, &acme.EAB{KeyID: cast.ToString(user.Kid), MACKey: cast.ToString(user.HmacEncoded)}, nil
 	case "google":
-		ca = acme.CAGoogle
-		eab = &acme.EAB{KeyID: *cert.User.Kid, MACKey: *cert.User.HmacEncoded}
+		return acme.CAGoogle, &acme.EAB{KeyID: cast.ToString(user.Kid), MACKey: cast.ToString(user.HmacEncoded)}, nil
 	}
 
-	return acme.NewPrivateKeyAccount(cert.User.Email, cert.User.PrivateKey, ca, eab)
+	return "", nil, errors.New("CA 提供商不支持")
 }
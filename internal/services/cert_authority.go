@@ -0,0 +1,250 @@
+package services
+
+import (
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goravel/framework/facades"
+	"github.com/goravel/framework/support/carbon"
+
+	requests "panel/app/http/requests/cert"
+	"panel/app/models"
+	"panel/pkg/acme"
+)
+
+// crlValidity CRL 有效期，过半后在续签任务或下载时重新生成
+const crlValidity = 7 * 24 * time.Hour
+
+type CertAuthorityImpl struct {
+}
+
+func NewCertAuthorityImpl() *CertAuthorityImpl {
+	return &CertAuthorityImpl{}
+}
+
+// Store 创建私有 CA，生成根证书和中间证书
+func (s *CertAuthorityImpl) Store(request requests.CAStore) (models.CertAuthority, error) {
+	ca, err := acme.GenerateCA(request.Name, acme.KeyType(request.Type), request.Days)
+	if err != nil {
+		return models.CertAuthority{}, err
+	}
+
+	rootKey, err := facades.Crypt().EncryptString(string(ca.RootKey))
+	if err != nil {
+		return models.CertAuthority{}, err
+	}
+	intermediateKey, err := facades.Crypt().EncryptString(string(ca.IntermediateKey))
+	if err != nil {
+		return models.CertAuthority{}, err
+	}
+
+	authority := models.CertAuthority{
+		Name:             request.Name,
+		Type:             request.Type,
+		RootCert:         string(ca.RootCert),
+		RootKey:          rootKey,
+		IntermediateCert: string(ca.IntermediateCert),
+		IntermediateKey:  intermediateKey,
+		CRLURL:           request.CRLURL,
+		Revocations:      []acme.Revocation{},
+	}
+	if err = facades.Orm().Query().Create(&authority); err != nil {
+		return models.CertAuthority{}, err
+	}
+
+	return authority, s.updateCRL(&authority, ca)
+}
+
+// Destroy 删除私有 CA
+func (s *CertAuthorityImpl) Destroy(ID uint) error {
+	var count int64
+	err := facades.Orm().Query().Model(&models.Cert{}).Where("ca_id", ID).Count(&count)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("该 CA 签发的证书尚未删除，无法删除 CA")
+	}
+
+	_, err = facades.Orm().Query().Delete(&models.CertAuthority{}, ID)
+	return err
+}
+
+// Issue 签发服务端或客户端证书，签发的证书加入证书列表，可以像 ACME 证书一样部署
+func (s *CertAuthorityImpl) Issue(request requests.CAIssue) (models.Cert, error) {
+	var authority models.CertAuthority
+	if err := facades.Orm().Query().Where("id", request.ID).FirstOrFail(&authority); err != nil {
+		return models.Cert{}, err
+	}
+
+	ca, err := s.ca(authority)
+	if err != nil {
+		return models.Cert{}, err
+	}
+	chain, key, serial, err := ca.Issue(acme.IssueRequest{
+		CommonName: request.CommonName,
+		Domains:    request.Domains,
+		Usage:      acme.CertUsage(request.Usage),
+		Days:       request.Days,
+		KeyType:    acme.KeyType(request.Type),
+		CRLURL:     authority.CRLURL,
+	})
+	if err != nil {
+		return models.Cert{}, err
+	}
+
+	cert := models.Cert{
+		CAID:      &authority.ID,
+		Type:      request.Type,
+		Domains:   request.Domains,
+		AutoRenew: false,
+		Cert:      string(chain),
+		Key:       string(key),
+		Serial:    serial,
+	}
+	if err = facades.Orm().Query().Create(&cert); err != nil {
+		return models.Cert{}, err
+	}
+
+	return cert, nil
+}
+
+// Revoke 吊销私有 CA 签发的证书并更新 CRL
+func (s *CertAuthorityImpl) Revoke(certID uint) error {
+	var cert models.Cert
+	if err := facades.Orm().Query().Where("id", certID).FirstOrFail(&cert); err != nil {
+		return err
+	}
+	if cert.CAID == nil {
+		return errors.New("该证书不是由私有 CA 签发的，无法吊销")
+	}
+	if cert.RevokedAt != nil {
+		return errors.New("该证书已被吊销")
+	}
+
+	var authority models.CertAuthority
+	if err := facades.Orm().Query().Where("id", *cert.CAID).FirstOrFail(&authority); err != nil {
+		return err
+	}
+	ca, err := s.ca(authority)
+	if err != nil {
+		return err
+	}
+
+	now := carbon.DateTime{Carbon: carbon.Now()}
+	authority.Revocations = append(authority.Revocations, acme.Revocation{
+		Serial:    cert.Serial,
+		RevokedAt: now.ToStdTime(),
+	})
+	if err = s.updateCRL(&authority, ca); err != nil {
+		return err
+	}
+
+	cert.RevokedAt = &now
+	return facades.Orm().Query().Save(&cert)
+}
+
+// Root 获取根证书
+func (s *CertAuthorityImpl) Root(ID uint) ([]byte, error) {
+	var authority models.CertAuthority
+	if err := facades.Orm().Query().Where("id", ID).FirstOrFail(&authority); err != nil {
+		return nil, err
+	}
+
+	return []byte(authority.RootCert), nil
+}
+
+// CRL 获取 DER 格式的吊销列表，用于 CRL 分发地址，即将过期时重新生成
+func (s *CertAuthorityImpl) CRL(ID uint) ([]byte, error) {
+	crl, err := s.CRLPEM(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(crl)
+	if block == nil {
+		return nil, errors.New("吊销列表格式错误")
+	}
+
+	return block.Bytes, nil
+}
+
+// CRLPEM 获取 PEM 格式的吊销列表，即将过期时重新生成
+func (s *CertAuthorityImpl) CRLPEM(ID uint) ([]byte, error) {
+	var authority models.CertAuthority
+	if err := facades.Orm().Query().Where("id", ID).FirstOrFail(&authority); err != nil {
+		return nil, err
+	}
+
+	if err := s.refreshCRL(&authority); err != nil {
+		return nil, err
+	}
+
+	return []byte(authority.CRL), nil
+}
+
+// RefreshCRL 重新生成所有即将过期的吊销列表，由证书续签任务定时调用
+func (s *CertAuthorityImpl) RefreshCRL() error {
+	var authorities []models.CertAuthority
+	if err := facades.Orm().Query().Find(&authorities); err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := range authorities {
+		if err := s.refreshCRL(&authorities[i]); err != nil {
+			errs = append(errs, fmt.Errorf("CA %s: %w", authorities[i].Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// refreshCRL CRL 有效期过半时重新生成
+func (s *CertAuthorityImpl) refreshCRL(authority *models.CertAuthority) error {
+	if authority.CRLUpdatedAt != nil && time.Since(authority.CRLUpdatedAt.ToStdTime()) <= crlValidity/2 {
+		return nil
+	}
+
+	ca, err := s.ca(*authority)
+	if err != nil {
+		return err
+	}
+
+	return s.updateCRL(authority, ca)
+}
+
+// ca 解密私钥
+func (s *CertAuthorityImpl) ca(authority models.CertAuthority) (acme.CA, error) {
+	rootKey, err := facades.Crypt().DecryptString(authority.RootKey)
+	if err != nil {
+		return acme.CA{}, errors.New("CA 私钥解密失败，面板密钥可能已变更")
+	}
+	intermediateKey, err := facades.Crypt().DecryptString(authority.IntermediateKey)
+	if err != nil {
+		return acme.CA{}, errors.New("CA 私钥解密失败，面板密钥可能已变更")
+	}
+
+	return acme.CA{
+		RootCert:         []byte(authority.RootCert),
+		RootKey:          []byte(rootKey),
+		IntermediateCert: []byte(authority.IntermediateCert),
+		IntermediateKey:  []byte(intermediateKey),
+	}, nil
+}
+
+// updateCRL 重新生成并保存 CRL
+func (s *CertAuthorityImpl) updateCRL(authority *models.CertAuthority, ca acme.CA) error {
+	crl, err := ca.CRL(authority.Revocations, authority.CRLNumber+1, time.Now().Add(crlValidity))
+	if err != nil {
+		return err
+	}
+
+	now := carbon.DateTime{Carbon: carbon.Now()}
+	authority.CRL = string(crl)
+	authority.CRLNumber++
+	authority.CRLUpdatedAt = &now
+	return facades.Orm().Query().Save(authority)
+}
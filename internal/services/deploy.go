@@ -0,0 +1,383 @@
+// Package services 网站部署服务
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goravel/framework/facades"
+	"github.com/goravel/framework/support/carbon"
+
+	requests "panel/app/http/requests/website"
+	"panel/app/models"
+	"panel/internal"
+	"panel/pkg/tools"
+)
+
+type DeployImpl struct {
+	task internal.Task
+}
+
+func NewDeployImpl() *DeployImpl {
+	return &DeployImpl{
+		task: NewTaskImpl(),
+	}
+}
+
+// GetConfig 获取部署配置
+func (r *DeployImpl) GetConfig(websiteID uint) (models.WebsiteDeploy, error) {
+	var deploy models.WebsiteDeploy
+	err := facades.Orm().Query().Where("website_id", websiteID).First(&deploy)
+	deploy.HasDeployKey = len(deploy.DeployKey) > 0
+
+	return deploy, err
+}
+
+// SaveConfig 保存部署配置
+func (r *DeployImpl) SaveConfig(request requests.DeployConfig) error {
+	var deploy models.WebsiteDeploy
+	if err := facades.Orm().Query().Where("website_id", request.ID).First(&deploy); err != nil {
+		return err
+	}
+
+	deploy.WebsiteID = request.ID
+	deploy.Repo = request.Repo
+	deploy.Branch = request.Branch
+	// 私钥不会返回给前端，未填写时保留原私钥
+	if key := strings.TrimSpace(request.DeployKey); len(key) > 0 {
+		deploy.DeployKey = key
+	} else if request.RemoveKey {
+		deploy.DeployKey = ""
+	}
+	deploy.PreDeploy = request.PreDeploy
+	deploy.PostDeploy = request.PostDeploy
+	deploy.KeepReleases = request.KeepReleases
+	if len(deploy.WebhookToken) == 0 {
+		deploy.WebhookToken = tools.RandomString(32)
+	}
+
+	return facades.Orm().Query().Save(&deploy)
+}
+
+// ResetToken 重置 Webhook 密钥
+func (r *DeployImpl) ResetToken(websiteID uint) (string, error) {
+	var deploy models.WebsiteDeploy
+	if err := facades.Orm().Query().Where("website_id", websiteID).FirstOrFail(&deploy); err != nil {
+		return "", errors.New("网站未配置部署")
+	}
+
+	deploy.WebhookToken = tools.RandomString(32)
+	if err := facades.Orm().Query().Save(&deploy); err != nil {
+		return "", err
+	}
+
+	return deploy.WebhookToken, nil
+}
+
+// Trigger 创建部署任务
+func (r *DeployImpl) Trigger(websiteID uint) error {
+	var deploy models.WebsiteDeploy
+	if err := facades.Orm().Query().With("Website").Where("website_id", websiteID).FirstOrFail(&deploy); err != nil {
+		return errors.New("网站未配置部署")
+	}
+
+	return r.createTask(deploy)
+}
+
+// TriggerByToken 通过 Webhook 密钥创建部署任务
+func (r *DeployImpl) TriggerByToken(token string) error {
+	if len(token) == 0 {
+		return errors.New("密钥不能为空")
+	}
+
+	var deploy models.WebsiteDeploy
+	if err := facades.Orm().Query().With("Website").Where("webhook_token", token).FirstOrFail(&deploy); err != nil {
+		return errors.New("密钥无效")
+	}
+
+	return r.createTask(deploy)
+}
+
+// Run 执行部署
+func (r *DeployImpl) Run(websiteID uint, log io.Writer) error {
+	var deploy models.WebsiteDeploy
+	if err := facades.Orm().Query().With("Website").Where("website_id", websiteID).FirstOrFail(&deploy); err != nil {
+		return errors.New("网站未配置部署")
+	}
+	if deploy.Website == nil {
+		return errors.New("网站不存在")
+	}
+
+	website := *deploy.Website
+	releasesPath := filepath.Join(website.Path, "releases")
+	release := carbon.Now().ToShortDateTimeString()
+	releasePath := filepath.Join(releasesPath, release)
+	if err := tools.Mkdir(releasesPath, 0755); err != nil {
+		return err
+	}
+	// 版本目录以秒命名，同一秒内的重复部署直接拒绝，避免写入同一目录
+	if err := os.Mkdir(releasePath, 0755); err != nil {
+		if os.IsExist(err) {
+			return errors.New("版本 " + release + " 已存在，请稍后重试")
+		}
+		return err
+	}
+
+	// 拉取代码
+	gitEnv := ""
+	if len(deploy.DeployKey) > 0 {
+		keyFile, err := tools.TempFile("deploy_key")
+		if err != nil {
+			_ = tools.Remove(releasePath)
+			return err
+		}
+		defer tools.Remove(keyFile.Name())
+		if _, err = keyFile.WriteString(deploy.DeployKey + "\n"); err != nil {
+			_ = tools.Remove(releasePath)
+			return err
+		}
+		if err = keyFile.Close(); err != nil {
+			_ = tools.Remove(releasePath)
+			return err
+		}
+		if err = os.Chmod(keyFile.Name(), 0600); err != nil {
+			_ = tools.Remove(releasePath)
+			return err
+		}
+		gitEnv = `GIT_SSH_COMMAND='ssh -i ` + keyFile.Name() + ` -o IdentitiesOnly=yes -o StrictHostKeyChecking=accept-new' `
+	}
+
+	_, _ = fmt.Fprintln(log, "|-拉取代码: "+deploy.Repo+" ("+deploy.Branch+")")
+	out, err := tools.Exec(gitEnv + `git clone --depth 1 --single-branch --branch '` + deploy.Branch + `' -- '` + deploy.Repo + `' '` + releasePath + `' 2>&1`)
+	if len(out) > 0 {
+		_, _ = fmt.Fprintln(log, out)
+	}
+	if err != nil {
+		_ = tools.Remove(releasePath)
+		return fmt.Errorf("拉取代码失败: %w", err)
+	}
+	commit, _ := tools.Exec(`git -C '` + releasePath + `' rev-parse --short HEAD`)
+	_, _ = fmt.Fprintln(log, "|-当前提交: "+commit)
+	if err = tools.Remove(filepath.Join(releasePath, ".git")); err != nil {
+		_ = tools.Remove(releasePath)
+		return err
+	}
+
+	// 部署前脚本
+	if len(strings.TrimSpace(deploy.PreDeploy)) > 0 {
+		_, _ = fmt.Fprintln(log, "|-执行部署前脚本")
+		if err = r.runHook(deploy.PreDeploy, releasePath, website, log); err != nil {
+			_ = tools.Remove(releasePath)
+			return fmt.Errorf("部署前脚本执行失败: %w", err)
+		}
+	}
+
+	// 使用独立应用池的网站以应用池用户运行
+	owner := "www"
+	var pool models.PhpPool
+	if err = facades.Orm().Query().Where("website_id", website.ID).First(&pool); err != nil {
+		_ = tools.Remove(releasePath)
+		return err
+	}
+	if pool.ID != 0 {
+		owner = pool.User
+	}
+	if err = tools.Chown(releasePath, owner, "www"); err != nil {
+		_ = tools.Remove(releasePath)
+		return err
+	}
+
+	// 切换版本
+	_, _ = fmt.Fprintln(log, "|-切换到版本: "+release)
+	if err = r.switchRelease(website, release); err != nil {
+		_ = tools.Remove(releasePath)
+		return err
+	}
+
+	// 部署后脚本
+	if len(strings.TrimSpace(deploy.PostDeploy)) > 0 {
+		_, _ = fmt.Fprintln(log, "|-执行部署后脚本")
+		if err = r.runHook(deploy.PostDeploy, filepath.Join(website.Path, "current"), website, log); err != nil {
+			return fmt.Errorf("部署后脚本执行失败: %w", err)
+		}
+	}
+
+	// 清理旧版本
+	releases, err := r.Releases(websiteID)
+	if err != nil {
+		return err
+	}
+	for i := deploy.KeepReleases; i < len(releases); i++ {
+		if releases[i].Current {
+			continue
+		}
+		_, _ = fmt.Fprintln(log, "|-清理旧版本: "+releases[i].Name)
+		if err = tools.Remove(filepath.Join(releasesPath, releases[i].Name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Releases 获取部署版本列表（按时间倒序）
+func (r *DeployImpl) Releases(websiteID uint) ([]internal.DeployRelease, error) {
+	var website models.Website
+	if err := facades.Orm().Query().Where("id", websiteID).FirstOrFail(&website); err != nil {
+		return nil, err
+	}
+
+	releasesPath := filepath.Join(website.Path, "releases")
+	if !tools.Exists(releasesPath) {
+		return []internal.DeployRelease{}, nil
+	}
+
+	entries, err := os.ReadDir(releasesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	current, _ := os.Readlink(filepath.Join(website.Path, "current"))
+	releases := make([]internal.DeployRelease, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		releases = append(releases, internal.DeployRelease{
+			Name:    entry.Name(),
+			Current: filepath.Base(current) == entry.Name(),
+		})
+	}
+	sort.Slice(releases, func(i, j int) bool {
+		return releases[i].Name > releases[j].Name
+	})
+
+	return releases, nil
+}
+
+// Rollback 回滚到指定版本
+func (r *DeployImpl) Rollback(websiteID uint, release string) error {
+	var website models.Website
+	if err := facades.Orm().Query().Where("id", websiteID).FirstOrFail(&website); err != nil {
+		return err
+	}
+
+	if !regexp.MustCompile(`^\d{14}$`).MatchString(release) || !tools.Exists(filepath.Join(website.Path, "releases", release)) {
+		return errors.New("版本不存在")
+	}
+
+	return r.switchRelease(website, release)
+}
+
+// createTask 创建部署任务
+func (r *DeployImpl) createTask(deploy models.WebsiteDeploy) error {
+	if deploy.Website == nil {
+		return errors.New("网站不存在")
+	}
+
+	logFile := "/tmp/deploy_" + deploy.Website.Name + "_" + carbon.Now().ToShortDateTimeString() + ".log"
+	var task models.Task
+	task.Name = "部署网站 " + deploy.Website.Name
+	task.Status = models.TaskStatusWaiting
+	task.Shell = `panel deploy '` + deploy.Website.Name + `' >> '` + logFile + `' 2>&1`
+	task.Log = logFile
+	if err := facades.Orm().Query().Create(&task); err != nil {
+		return errors.New("创建任务失败")
+	}
+
+	r.task.Process(task.ID)
+	return nil
+}
+
+// runHook 在指定目录中执行部署脚本
+func (r *DeployImpl) runHook(script, dir string, website models.Website, log io.Writer) error {
+	hookFile, err := tools.TempFile("deploy_hook")
+	if err != nil {
+		return err
+	}
+	defer tools.Remove(hookFile.Name())
+	if _, err = hookFile.WriteString(script + "\n"); err != nil {
+		return err
+	}
+	if err = hookFile.Close(); err != nil {
+		return err
+	}
+
+	out, err := tools.Exec(`cd '` + dir + `' && WEBSITE_NAME='` + website.Name + `' WEBSITE_PATH='` + website.Path + `' RELEASE_PATH='` + dir + `' bash '` + hookFile.Name() + `' 2>&1`)
+	if len(out) > 0 {
+		_, _ = fmt.Fprintln(log, out)
+	}
+
+	return err
+}
+
+// switchRelease 原子切换 current 软链接到指定版本，后续步骤失败时切换回原版本
+func (r *DeployImpl) switchRelease(website models.Website, release string) (err error) {
+	current := filepath.Join(website.Path, "current")
+	previous, _ := os.Readlink(current)
+	if err = r.link(current, filepath.Join(website.Path, "releases", release)); err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		if len(previous) > 0 {
+			_ = r.link(current, previous)
+		} else {
+			_ = tools.Remove(current)
+		}
+	}()
+
+	// 确保网站运行目录指向 current
+	raw, err := tools.Read("/www/server/vhost/" + website.Name + ".conf")
+	if err != nil {
+		return err
+	}
+	rootConfig := tools.Cut(raw, "# root标记位开始", "# root标记位结束")
+	match := regexp.MustCompile(`(#\s*)?root\s+(.+);\n`).FindAllStringSubmatch(rootConfig, -1)
+	if len(match) == 0 {
+		return errors.New("配置文件中缺少root标记位")
+	}
+	root := match[len(match)-1][2]
+	if root != current && !strings.HasPrefix(root, current+"/") {
+		// 保留运行目录在网站目录下的子目录，例如 public
+		newRoot := current
+		if rel, relErr := filepath.Rel(website.Path, root); relErr == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			newRoot = filepath.Join(current, rel)
+		}
+		newRootConfig := strings.Replace(rootConfig, root+";", newRoot+";", 1)
+		raw = strings.Replace(raw, rootConfig, newRootConfig, 1)
+		if err = tools.Write("/www/server/vhost/"+website.Name+".conf", raw, 0644); err != nil {
+			return err
+		}
+		if err = tools.ServiceReload("openresty"); err != nil {
+			return err
+		}
+	}
+
+	// 清除 PHP 的 realpath 和 OPcache 缓存
+	if website.Php > 0 {
+		return tools.ServiceReload("php-fpm-" + strconv.Itoa(website.Php))
+	}
+
+	return nil
+}
+
+// link 通过重命名临时软链接原子替换 current
+func (r *DeployImpl) link(current, target string) error {
+	temp := current + ".tmp"
+	_ = tools.Remove(temp)
+	if err := os.Symlink(target, temp); err != nil {
+		return err
+	}
+
+	return os.Rename(temp, current)
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goravel/framework/facades"
+	"github.com/goravel/framework/support/carbon"
+
+	requests "panel/app/http/requests/log"
+	"panel/app/models"
+	"panel/internal"
+	"panel/pkg/logreader"
+	"panel/pkg/tools"
+)
+
+type LogImpl struct {
+}
+
+func NewLogImpl() *LogImpl {
+	return &LogImpl{}
+}
+
+// Sources 获取可查看的日志来源，只返回存在的日志文件
+func (r *LogImpl) Sources() ([]internal.LogSource, error) {
+	var sources []internal.LogSource
+	add := func(key, name, typ, path string) {
+		if tools.Exists(path) {
+			sources = append(sources, internal.LogSource{Key: key, Name: name, Type: typ, Path: path})
+		}
+	}
+
+	var websites []models.Website
+	if err := facades.Orm().Query().Order("id asc").Find(&websites); err != nil {
+		return nil, err
+	}
+	for _, website := range websites {
+		add("website:"+website.Name, website.Name, "website", "/www/wwwlogs/"+website.Name+".log")
+	}
+
+	add("openresty:error", "OpenResty 错误日志", "service", "/www/wwwlogs/openresty_error.log")
+
+	phps, _ := filepath.Glob("/www/server/php/*/var/log")
+	sort.Strings(phps)
+	for _, dir := range phps {
+		version := filepath.Base(filepath.Dir(filepath.Dir(dir)))
+		add("php:"+version+":error", "PHP-"+version+" 错误日志", "service", dir+"/php-fpm.log")
+		add("php:"+version+":slow", "PHP-"+version+" 慢日志", "service", dir+"/slow.log")
+		for _, website := range websites {
+			add("php:"+version+":slow:"+website.Name, "PHP-"+version+" "+website.Name+" 慢日志", "service", dir+"/slow-"+website.Name+".log")
+		}
+	}
+
+	add("mysql:error", "MySQL 错误日志", "service", "/www/server/mysql/mysql-error.log")
+	add("mysql:slow", "MySQL 慢查询日志", "service", "/www/server/mysql/mysql-slow.log")
+	add("postgresql", "PostgreSQL 日志", "service", "/www/server/postgresql/logs/postgresql-"+carbon.Now().ToDateString()+".log")
+
+	add("supervisor", "Supervisor 日志", "service", "/var/log/supervisor/supervisord.log")
+	confDir := "/etc/supervisor/conf.d"
+	if tools.IsRHEL() {
+		confDir = "/etc/supervisord.d"
+	}
+	programs, _ := filepath.Glob(confDir + "/*.conf")
+	sort.Strings(programs)
+	logfile := regexp.MustCompile(`(?m)^\s*stdout_logfile\s*=\s*(\S+)`)
+	for _, program := range programs {
+		config, err := tools.Read(program)
+		if err != nil {
+			continue
+		}
+		if match := logfile.FindStringSubmatch(config); len(match) > 1 {
+			name := strings.TrimSuffix(filepath.Base(program), ".conf")
+			add("supervisor:"+name, "Supervisor "+name+" 日志", "service", match[1])
+		}
+	}
+
+	panelLogs, _ := filepath.Glob("/www/panel/storage/logs/*.log")
+	sort.Sort(sort.Reverse(sort.StringSlice(panelLogs)))
+	for _, path := range panelLogs {
+		name := filepath.Base(path)
+		add("panel:"+name, "面板日志 "+name, "panel", path)
+	}
+
+	return sources, nil
+}
+
+// Read 分页读取日志
+func (r *LogImpl) Read(request requests.Read) (logreader.Page, error) {
+	path, err := r.path(request.Source)
+	if err != nil {
+		return logreader.Page{}, err
+	}
+	since, err := r.parseTime(request.Since)
+	if err != nil {
+		return logreader.Page{}, err
+	}
+	until, err := r.parseTime(request.Until)
+	if err != nil {
+		return logreader.Page{}, err
+	}
+
+	return logreader.Read(path, logreader.Query{
+		Keyword:    request.Keyword,
+		Regex:      request.Regex,
+		IgnoreCase: request.IgnoreCase,
+		Since:      since,
+		Until:      until,
+		Before:     request.Before,
+		Limit:      request.Limit,
+	})
+}
+
+// Stream 跟踪日志新写入的行
+func (r *LogImpl) Stream(ctx context.Context, request requests.Stream, handler func([]logreader.Line) error) error {
+	path, err := r.path(request.Source)
+	if err != nil {
+		return err
+	}
+	match, err := logreader.NewMatcher(request.Keyword, request.Regex, request.IgnoreCase)
+	if err != nil {
+		return err
+	}
+	offset := request.Offset
+	if offset == 0 {
+		offset = -1
+	}
+
+	return logreader.Follow(ctx, path, offset, match, handler)
+}
+
+// path 根据日志来源获取日志路径，只允许读取 Sources 中列出的文件
+func (r *LogImpl) path(source string) (string, error) {
+	sources, err := r.Sources()
+	if err != nil {
+		return "", err
+	}
+	for _, item := range sources {
+		if item.Key == source {
+			return item.Path, nil
+		}
+	}
+	return "", errors.New("日志来源不存在")
+}
+
+func (r *LogImpl) parseTime(value string) (time.Time, error) {
+	if len(value) == 0 {
+		return time.Time{}, nil
+	}
+	t, err := time.ParseInLocation(time.DateTime, value, time.Local)
+	if err != nil {
+		return time.Time{}, errors.New("时间格式错误，应为 " + time.DateTime)
+	}
+
+	return t, nil
+}
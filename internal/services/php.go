@@ -14,6 +14,7 @@ import (
 
 	"panel/app/models"
 	"panel/internal"
+	"panel/pkg/fastcgi"
 	"panel/pkg/tools"
 	"panel/types"
 )
@@ -79,7 +80,37 @@ func (r *PHPImpl) Load() ([]types.NV, error) {
 		return nil, errors.New("获取 PHP-" + r.version + " 运行状态失败")
 	}
 
-	raw := resp.String()
+	data := r.parseStatus(resp.String())
+
+	// 网站独立应用池的状态
+	var websites []models.Website
+	if err = facades.Orm().Query().Where("php", r.version).Find(&websites); err != nil {
+		return nil, err
+	}
+	for _, website := range websites {
+		var pool models.PhpPool
+		if err = facades.Orm().Query().Where("website_id", website.ID).First(&pool); err != nil {
+			return nil, err
+		}
+		if pool.ID == 0 {
+			continue
+		}
+		status, err := fastcgi.Get("unix", PhpPoolSocket(website.Php, website.Name), "/phpfpm_status", 10*time.Second)
+		if err == nil && status.Status != 200 {
+			err = fmt.Errorf("状态码 %d", status.Status)
+		}
+		if err != nil {
+			data = append(data, types.NV{Name: "应用池", Value: website.Name}, types.NV{Name: "获取状态失败", Value: err.Error()})
+			continue
+		}
+		data = append(data, r.parseStatus(status.Body)...)
+	}
+
+	return data, nil
+}
+
+// parseStatus 解析 PHP-FPM 状态页
+func (r *PHPImpl) parseStatus(raw string) []types.NV {
 	dataKeys := []string{"应用池", "工作模式", "启动时间", "接受连接", "监听队列", "最大监听队列", "监听队列长度", "空闲进程数量", "活动进程数量", "总进程数量", "最大活跃进程数量", "达到进程上限次数", "慢请求"}
 	regexKeys := []string{"pool", "process manager", "start time", "accepted conn", "listen queue", "max listen queue", "listen queue len", "idle processes", "active processes", "total processes", "max active processes", "max children reached", "slow requests"}
 
@@ -95,7 +126,7 @@ func (r *PHPImpl) Load() ([]types.NV, error) {
 		}
 	}
 
-	return data, nil
+	return data
 }
 
 func (r *PHPImpl) GetErrorLog() (string, error) {
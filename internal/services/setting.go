@@ -2,8 +2,18 @@
 package services
 
 import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
 	"github.com/goravel/framework/facades"
+	"github.com/spf13/cast"
+
+	requests "panel/app/http/requests/setting"
 	"panel/app/models"
+	"panel/internal"
+	"panel/pkg/tools"
 )
 
 type SettingImpl struct {
@@ -54,3 +64,102 @@ func (r *SettingImpl) Delete(key string) error {
 
 	return nil
 }
+
+// UpdateSSL 修改面板配置文件中的 HTTPS 开关，重启面板后生效
+func (r *SettingImpl) UpdateSSL(enabled bool) error {
+	if out, err := tools.Exec("sed -i 's/APP_SSL=" + strconv.FormatBool(!enabled) + "/APP_SSL=" + strconv.FormatBool(enabled) + "/g' /www/panel/panel.conf"); err != nil {
+		return errors.New(out)
+	}
+
+	return nil
+}
+
+// ScheduleRestart 标记面板需要重启，在任务中调用时由任务队列在任务结束后重启，避免中断任务
+func (r *SettingImpl) ScheduleRestart() error {
+	return r.Set(models.SettingKeyPanelRestart, "1")
+}
+
+// HTTPS 获取面板 HTTPS 设置
+func (r *SettingImpl) HTTPS() (internal.PanelHTTPS, error) {
+	https := internal.PanelHTTPS{
+		Enabled:  facades.Config().GetBool("panel.ssl"),
+		Redirect: cast.ToBool(r.Get(models.SettingKeyPanelHTTPSRedirect)),
+	}
+
+	ID := cast.ToUint(r.Get(models.SettingKeyPanelCertID))
+	if ID == 0 {
+		return https, nil
+	}
+	var cert models.Cert
+	if err := facades.Orm().Query().With("User").With("DNS").With("Task").Where("id = ?", ID).First(&cert); err != nil {
+		return https, err
+	}
+	if cert.ID != 0 {
+		https.Cert = &cert
+	}
+
+	return https, nil
+}
+
+// UpdateHTTPS 为面板绑定域名并签发证书，域名或验证方式变化时返回签发任务
+// 证书签发成功后部署到面板并开启 HTTPS，之后由证书续签任务自动续签
+func (r *SettingImpl) UpdateHTTPS(request requests.HTTPS) (*models.Task, error) {
+	var cert models.Cert
+	if err := facades.Orm().Query().Where("id = ?", cast.ToUint(r.Get(models.SettingKeyPanelCertID))).First(&cert); err != nil {
+		return nil, err
+	}
+	if request.DNSID == nil && strings.HasPrefix(request.Domain, "*.") {
+		return nil, errors.New("通配符域名需要使用 DNS 验证")
+	}
+
+	obtain := cert.ID == 0 || len(cert.Cert) == 0 || cert.Type != request.Type ||
+		!slices.Equal(cert.Domains, []string{request.Domain}) || cert.UserID != request.UserID ||
+		cast.ToUint(cert.DNSID) != cast.ToUint(request.DNSID)
+
+	cert.Type = request.Type
+	cert.Domains = []string{request.Domain}
+	cert.AutoRenew = true
+	cert.UserID = request.UserID
+	cert.DNSID = request.DNSID
+	cert.WebsiteID = nil
+	if err := facades.Orm().Query().Save(&cert); err != nil {
+		return nil, err
+	}
+	if err := r.Set(models.SettingKeyPanelCertID, strconv.Itoa(int(cert.ID))); err != nil {
+		return nil, err
+	}
+
+	oldRedirect := cast.ToBool(r.Get(models.SettingKeyPanelHTTPSRedirect))
+	if err := r.Set(models.SettingKeyPanelHTTPSRedirect, strconv.FormatBool(request.Redirect)); err != nil {
+		return nil, err
+	}
+
+	if obtain {
+		task, err := NewCertImpl().ObtainTask(cert.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &task, nil
+	}
+
+	// 跳转设置在启动时生效
+	if oldRedirect != request.Redirect && facades.Config().GetBool("panel.ssl") {
+		tools.RestartPanel()
+	}
+
+	return nil, nil
+}
+
+// DeleteHTTPS 解除面板绑定的证书，面板继续使用当前证书文件，不再自动续签
+func (r *SettingImpl) DeleteHTTPS() error {
+	ID := cast.ToUint(r.Get(models.SettingKeyPanelCertID))
+	if err := r.Delete(models.SettingKeyPanelCertID); err != nil {
+		return err
+	}
+	if ID == 0 {
+		return nil
+	}
+
+	_, err := facades.Orm().Query().Model(&models.Cert{}).Where("id", ID).Update("auto_renew", false)
+	return err
+}
@@ -2,30 +2,52 @@
 package services
 
 import (
+	"context"
 	"crypto/x509"
+	"encoding/json"
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/goravel/framework/facades"
+	"github.com/goravel/framework/support/carbon"
 	"github.com/spf13/cast"
 	requests "panel/app/http/requests/website"
 
 	"panel/app/models"
 	"panel/internal"
+	"panel/pkg/acme"
+	"panel/pkg/htaccess"
 	"panel/pkg/tools"
 )
 
+// httpRedirectConfig HTTP 跳转到 HTTPS 的配置，位于 ssl 标记位中
+const httpRedirectConfig = `# http重定向标记位开始
+    if ($server_port !~ 443){
+        return 301 https://$host$request_uri;
+    }
+    error_page 497  https://$host$request_uri;
+    # http重定向标记位结束
+    `
+
 type WebsiteImpl struct {
 	setting internal.Setting
+	backup  internal.Backup
+	task    internal.Task
 }
 
 func NewWebsiteImpl() *WebsiteImpl {
 	return &WebsiteImpl{
 		setting: NewSettingImpl(),
+		backup:  NewBackupImpl(),
+		task:    NewTaskImpl(),
 	}
 }
 
@@ -42,6 +64,15 @@ func (r *WebsiteImpl) List(page, limit int) (int64, []models.Website, error) {
 
 // Add 添加网站
 func (r *WebsiteImpl) Add(website internal.PanelWebsite) (models.Website, error) {
+	if website.PhpPool != nil {
+		if cast.ToInt(website.Php) == 0 {
+			return models.Website{}, errors.New("使用独立应用池需要选择 PHP 版本")
+		}
+		if err := r.validatePhpPool(*website.PhpPool, ""); err != nil {
+			return models.Website{}, err
+		}
+	}
+
 	w := models.Website{
 		Name:   website.Name,
 		Status: website.Status,
@@ -50,6 +81,11 @@ func (r *WebsiteImpl) Add(website internal.PanelWebsite) (models.Website, error)
 		Ssl:    website.Ssl,
 		Remark: website.Remark,
 	}
+	if website.Db {
+		w.DbType = website.DbType
+		w.DbName = website.DbName
+		w.DbUser = website.DbUser
+	}
 	if err := facades.Orm().Query().Create(&w); err != nil {
 		return models.Website{}, err
 	}
@@ -244,6 +280,17 @@ server
 		_ = tools.ServiceReload("postgresql")
 	}
 
+	if website.PhpPool != nil {
+		if err := r.savePhpPool(w, *website.PhpPool); err != nil {
+			// 应用池创建失败时删除已创建的网站和数据库
+			_ = r.Delete(w.ID)
+			if website.Db {
+				r.dropDatabase(website.DbType, website.DbName, website.DbUser)
+			}
+			return models.Website{}, err
+		}
+	}
+
 	return w, nil
 }
 
@@ -291,11 +338,9 @@ func (r *WebsiteImpl) SaveConfig(config requests.SaveConfig) error {
 		domain += " " + v
 	}
 	domain += ";"
-	domainConfigOld := tools.Cut(raw, "# server_name标记位开始", "# server_name标记位结束")
-	if len(strings.TrimSpace(domainConfigOld)) == 0 {
-		return errors.New("配置文件中缺少server_name标记位")
+	if raw, err = r.replaceMarker(raw, "server_name", "\n    "+domain+"\n    "); err != nil {
+		return err
 	}
-	raw = strings.Replace(raw, domainConfigOld, "\n    "+domain+"\n    ", -1)
 
 	// 端口
 	var port strings.Builder
@@ -321,11 +366,9 @@ func (r *WebsiteImpl) SaveConfig(config requests.SaveConfig) error {
 			port.WriteString("    listen [::]:" + vStr + ";")
 		}
 	}
-	portConfigOld := tools.Cut(raw, "# port标记位开始", "# port标记位结束")
-	if len(strings.TrimSpace(portConfigOld)) == 0 {
-		return errors.New("配置文件中缺少port标记位")
+	if raw, err = r.replaceMarker(raw, "port", "\n"+port.String()+"\n    "); err != nil {
+		return err
 	}
-	raw = strings.Replace(raw, portConfigOld, "\n"+port.String()+"\n    ", -1)
 
 	// 运行目录
 	root := tools.Cut(raw, "# root标记位开始", "# root标记位结束")
@@ -411,13 +454,7 @@ func (r *WebsiteImpl) SaveConfig(config requests.SaveConfig) error {
     ssl_early_data on;
     `
 		if config.HttpRedirect {
-			sslConfig += `# http重定向标记位开始
-    if ($server_port !~ 443){
-        return 301 https://$host$request_uri;
-    }
-    error_page 497  https://$host$request_uri;
-    # http重定向标记位结束
-    `
+			sslConfig += httpRedirectConfig
 		}
 		if config.Hsts {
 			sslConfig += `# hsts标记位开始
@@ -438,13 +475,15 @@ func (r *WebsiteImpl) SaveConfig(config requests.SaveConfig) error {
 	}
 
 	if website.Php != config.Php {
+		if err = r.movePhpPool(website, config.Php); err != nil {
+			return err
+		}
 		website.Php = config.Php
-		phpConfigOld := tools.Cut(raw, "# php标记位开始", "# php标记位结束")
-		phpConfig := `
-    include enable-php-` + strconv.Itoa(website.Php) + `.conf;
-    `
-		if len(strings.TrimSpace(phpConfigOld)) != 0 {
-			raw = strings.Replace(raw, phpConfigOld, phpConfig, -1)
+		// 旧版本的配置文件可能没有 php 标记位，此时保持原样
+		if r.hasMarker(raw, "php") {
+			if raw, err = r.replaceMarker(raw, "php", r.phpConfig(website)); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -477,6 +516,21 @@ func (r *WebsiteImpl) Delete(id uint) error {
 	if _, err := facades.Orm().Query().Delete(&website); err != nil {
 		return err
 	}
+	if _, err := facades.Orm().Query().Where("website_id", website.ID).Delete(&models.WebsiteDeploy{}); err != nil {
+		return err
+	}
+	if _, err := facades.Orm().Query().Where("website_id", website.ID).Delete(&models.CertDeployment{}); err != nil {
+		return err
+	}
+	var pool models.PhpPool
+	if err := facades.Orm().Query().Where("website_id", website.ID).First(&pool); err != nil {
+		return err
+	}
+	if pool.ID != 0 {
+		if err := r.removePhpPool(website, pool); err != nil {
+			return err
+		}
+	}
 
 	if err := tools.Remove("/www/server/vhost/" + website.Name + ".conf"); err != nil {
 		return err
@@ -630,3 +684,988 @@ func (r *WebsiteImpl) GetIDByName(name string) (uint, error) {
 
 	return website.ID, nil
 }
+
+// ConvertHtaccess 将 .htaccess 规则转换为伪静态规则，内容为空时读取网站运行目录下的 .htaccess
+func (r *WebsiteImpl) ConvertHtaccess(id uint, content string) (htaccess.Result, error) {
+	if len(strings.TrimSpace(content)) == 0 {
+		config, err := r.GetConfig(id)
+		if err != nil {
+			return htaccess.Result{}, err
+		}
+		file := strings.TrimSuffix(config.Root, "/") + "/.htaccess"
+		if !tools.Exists(file) {
+			return htaccess.Result{}, errors.New("网站运行目录下不存在 .htaccess 文件")
+		}
+		if content, err = tools.Read(file); err != nil {
+			return htaccess.Result{}, err
+		}
+	}
+
+	return htaccess.Convert(content), nil
+}
+
+// SaveRewrite 保存伪静态规则，配置检查失败时还原
+func (r *WebsiteImpl) SaveRewrite(id uint, rewrite string) error {
+	var website models.Website
+	if err := facades.Orm().Query().Where("id", id).FirstOrFail(&website); err != nil {
+		return err
+	}
+
+	file := "/www/server/vhost/rewrite/" + website.Name + ".conf"
+	old, err := tools.Read(file)
+	if err != nil {
+		return err
+	}
+	if err = tools.Write(file, rewrite, 0644); err != nil {
+		return err
+	}
+	if _, err = tools.Exec("openresty -t"); err != nil {
+		_ = tools.Write(file, old, 0644)
+		return errors.New("伪静态规则检查失败: " + err.Error())
+	}
+
+	return tools.ServiceReload("openresty")
+}
+
+// UpdateStatus 启用或停用网站
+func (r *WebsiteImpl) UpdateStatus(id uint, status bool) error {
+	var website models.Website
+	if err := facades.Orm().Query().Where("id", id).FirstOrFail(&website); err != nil {
+		return err
+	}
+
+	if err := r.updateVhost(website, func(raw string) (string, error) {
+		var err error
+		// 运行目录
+		rootConfig := tools.Cut(raw, "# root标记位开始", "# root标记位结束")
+		match := regexp.MustCompile(`root\s+(.+);`).FindStringSubmatch(rootConfig)
+		if len(match) == 2 {
+			if status {
+				root := regexp.MustCompile(`# root\s+(.+);`).FindStringSubmatch(rootConfig)
+				if len(root) == 2 {
+					if raw, err = r.replaceMarker(raw, "root", "\n    root "+root[1]+";\n    "); err != nil {
+						return "", err
+					}
+				}
+			} else if !strings.Contains(rootConfig, "# root") {
+				if raw, err = r.replaceMarker(raw, "root", "\n    root /www/server/openresty/html;\n    # root "+match[1]+";\n    "); err != nil {
+					return "", err
+				}
+			}
+		}
+
+		// 默认文件
+		indexConfig := tools.Cut(raw, "# index标记位开始", "# index标记位结束")
+		match = regexp.MustCompile(`index\s+(.+);`).FindStringSubmatch(indexConfig)
+		if len(match) == 2 {
+			if status {
+				index := regexp.MustCompile(`# index\s+(.+);`).FindStringSubmatch(indexConfig)
+				if len(index) == 2 {
+					if raw, err = r.replaceMarker(raw, "index", "\n    index "+index[1]+";\n    "); err != nil {
+						return "", err
+					}
+				}
+			} else if !strings.Contains(indexConfig, "# index") {
+				if raw, err = r.replaceMarker(raw, "index", "\n    index stop.html;\n    # index "+match[1]+";\n    "); err != nil {
+					return "", err
+				}
+			}
+		}
+
+		return raw, nil
+	}); err != nil {
+		return err
+	}
+
+	website.Status = status
+	return facades.Orm().Query().Save(&website)
+}
+
+// UpdatePHP 切换网站的 PHP 版本，0 为纯静态
+func (r *WebsiteImpl) UpdatePHP(id uint, php int) error {
+	var website models.Website
+	if err := facades.Orm().Query().Where("id", id).FirstOrFail(&website); err != nil {
+		return err
+	}
+	if !tools.Exists("/www/server/openresty/conf/enable-php-" + strconv.Itoa(php) + ".conf") {
+		return errors.New("PHP-" + strconv.Itoa(php) + " 未安装")
+	}
+
+	previous := website
+	if err := r.movePhpPool(website, php); err != nil {
+		return err
+	}
+	website.Php = php
+	err := r.updatePhpConfig(website)
+	if err == nil {
+		err = facades.Orm().Query().Save(&website)
+	}
+	if err != nil {
+		// 回滚应用池和配置文件，保持与数据库中的版本一致
+		_ = r.movePhpPool(website, previous.Php)
+		_ = r.updatePhpConfig(previous)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateHttpRedirect 开启或关闭 HTTP 跳转到 HTTPS
+func (r *WebsiteImpl) UpdateHttpRedirect(id uint, enable bool) error {
+	var website models.Website
+	if err := facades.Orm().Query().Where("id", id).FirstOrFail(&website); err != nil {
+		return err
+	}
+	if !website.Ssl {
+		return errors.New("网站未开启 HTTPS")
+	}
+
+	return r.updateVhost(website, func(raw string) (string, error) {
+		redirectConfig := tools.Cut(raw, "# http重定向标记位开始", "# http重定向标记位结束")
+		switch {
+		case enable && len(redirectConfig) == 0:
+			return strings.Replace(raw, "# ssl标记位结束", httpRedirectConfig+"# ssl标记位结束", 1), nil
+		case !enable && len(redirectConfig) != 0:
+			return strings.Replace(raw, "# http重定向标记位开始"+redirectConfig+"# http重定向标记位结束\n    ", "", 1), nil
+		}
+
+		return raw, nil
+	})
+}
+
+// Batch 创建批量操作任务
+func (r *WebsiteImpl) Batch(request requests.Batch) error {
+	actions := map[string]string{
+		"start":         "启用",
+		"stop":          "停用",
+		"backup":        "备份",
+		"php":           "切换 PHP 版本",
+		"http_redirect": "开启 HTTPS 跳转",
+		"delete":        "删除",
+	}
+	name, ok := actions[request.Action]
+	if !ok {
+		return errors.New("不支持的操作")
+	}
+	if request.Action == "php" && !tools.Exists("/www/server/openresty/conf/enable-php-"+strconv.Itoa(request.Php)+".conf") {
+		return errors.New("PHP-" + strconv.Itoa(request.Php) + " 未安装")
+	}
+
+	ids := make([]string, 0, len(request.IDs))
+	for _, id := range request.IDs {
+		ids = append(ids, strconv.Itoa(int(id)))
+	}
+
+	logFile := "/tmp/website_batch_" + carbon.Now().ToShortDateTimeString() + ".log"
+	var task models.Task
+	task.Name = "批量" + name + "网站"
+	task.Status = models.TaskStatusWaiting
+	task.Shell = `panel websiteBatch ` + request.Action + ` ` + strings.Join(ids, ",") + ` ` + strconv.Itoa(request.Php) + ` >> '` + logFile + `' 2>&1`
+	task.Log = logFile
+	if err := facades.Orm().Query().Create(&task); err != nil {
+		return errors.New("创建任务失败")
+	}
+
+	r.task.Process(task.ID)
+	return nil
+}
+
+// BatchRun 执行批量操作，逐个输出网站的处理结果
+func (r *WebsiteImpl) BatchRun(action string, ids []uint, php int, log io.Writer) error {
+	failed := 0
+	for _, id := range ids {
+		var website models.Website
+		if err := facades.Orm().Query().Where("id", id).FirstOrFail(&website); err != nil {
+			failed++
+			_, _ = fmt.Fprintf(log, "|-网站 %d 处理失败: 网站不存在\n", id)
+			continue
+		}
+
+		var err error
+		switch action {
+		case "start":
+			err = r.UpdateStatus(id, true)
+		case "stop":
+			err = r.UpdateStatus(id, false)
+		case "backup":
+			err = r.backup.WebSiteBackup(website, nil)
+		case "php":
+			err = r.UpdatePHP(id, php)
+		case "http_redirect":
+			err = r.UpdateHttpRedirect(id, true)
+		case "delete":
+			err = r.Delete(id)
+		default:
+			return errors.New("不支持的操作")
+		}
+		if err != nil {
+			failed++
+			_, _ = fmt.Fprintln(log, "|-网站 "+website.Name+" 处理失败: "+err.Error())
+			continue
+		}
+		_, _ = fmt.Fprintln(log, "|-网站 "+website.Name+" 处理成功")
+	}
+
+	_, _ = fmt.Fprintf(log, "|-共 %d 个网站，成功 %d 个，失败 %d 个\n", len(ids), len(ids)-failed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d 个网站处理失败", failed)
+	}
+
+	return nil
+}
+
+// Export 导出网站迁移包，包含网站文件、配置、证书和关联的数据库，不包含数据库密码
+func (r *WebsiteImpl) Export(request requests.Export) (string, error) {
+	var website models.Website
+	if err := facades.Orm().Query().With("Cert").Where("id", request.ID).FirstOrFail(&website); err != nil {
+		return "", err
+	}
+	setting, err := r.GetConfig(website.ID)
+	if err != nil {
+		return "", err
+	}
+
+	backupPath := r.setting.Get(models.SettingKeyBackupPath)
+	if len(backupPath) == 0 {
+		return "", errors.New("未正确配置备份路径")
+	}
+	backupPath += "/migration"
+	if !tools.Exists(backupPath) {
+		if err = tools.Mkdir(backupPath, 0755); err != nil {
+			return "", err
+		}
+	}
+
+	// 早期创建的网站没有记录关联的数据库，需要导出时指定，避免迁移包静默缺少数据库
+	if len(website.DbType) == 0 {
+		if request.Db {
+			if !r.databaseExists(request.DbType, request.DbName) {
+				return "", errors.New("数据库 " + request.DbName + " 不存在")
+			}
+			website.DbType = request.DbType
+			website.DbName = request.DbName
+			website.DbUser = request.DbUser
+			if err = facades.Orm().Query().Save(&website); err != nil {
+				return "", err
+			}
+		} else {
+			name := strings.NewReplacer(".", "_", "-", "_").Replace(website.Name)
+			for _, dbType := range []string{"mysql", "postgresql"} {
+				if r.databaseExists(dbType, name) {
+					return "", errors.New("网站未记录关联的数据库，但检测到同名数据库 " + name + "，请指定要导出的数据库")
+				}
+			}
+		}
+	}
+
+	// 迁移包包含证书私钥和数据库，暂存目录仅允许 root 访问
+	tempDir, err := tools.TempDir("website_export")
+	if err != nil {
+		return "", err
+	}
+	defer tools.Remove(tempDir)
+	if err = tools.Chmod(tempDir, 0700); err != nil {
+		return "", err
+	}
+
+	manifest := internal.WebsiteManifest{
+		Version:      1,
+		PanelVersion: facades.Config().GetString("panel.version"),
+		CreatedAt:    carbon.Now().ToDateTimeString(),
+		Name:         website.Name,
+		Domains:      setting.Domains,
+		Ports:        setting.Ports,
+		Index:        setting.Index,
+		Php:          website.Php,
+		OpenBasedir:  setting.OpenBasedir,
+		Ssl:          setting.Ssl,
+		HttpRedirect: setting.HttpRedirect,
+		Hsts:         setting.Hsts,
+		Waf:          setting.Waf,
+		WafMode:      setting.WafMode,
+		WafCcDeny:    setting.WafCcDeny,
+		WafCache:     setting.WafCache,
+		Remark:       website.Remark,
+	}
+	if strings.HasPrefix(setting.Root, website.Path) {
+		manifest.Root = strings.TrimPrefix(setting.Root, website.Path)
+	}
+
+	if _, err = tools.Exec(`tar -czf '` + tempDir + `/site.tar.gz' -C '` + website.Path + `' .`); err != nil {
+		return "", err
+	}
+	if err = tools.Write(tempDir+"/vhost.conf", setting.Raw, 0644); err != nil {
+		return "", err
+	}
+	if err = tools.Write(tempDir+"/rewrite.conf", setting.Rewrite, 0644); err != nil {
+		return "", err
+	}
+
+	// 证书
+	if setting.Ssl {
+		if err = tools.Mkdir(tempDir+"/ssl", 0700); err != nil {
+			return "", err
+		}
+		if err = tools.Write(tempDir+"/ssl/cert.pem", setting.SslCertificate, 0644); err != nil {
+			return "", err
+		}
+		if err = tools.Write(tempDir+"/ssl/key.pem", setting.SslCertificateKey, 0600); err != nil {
+			return "", err
+		}
+		if website.Cert != nil {
+			manifest.Cert = &internal.WebsiteManifestCert{
+				Type:    website.Cert.Type,
+				Domains: website.Cert.Domains,
+			}
+		}
+	}
+
+	// 数据库
+	if len(website.DbType) > 0 {
+		manifest.Database = &internal.WebsiteManifestDatabase{
+			Type: website.DbType,
+			Name: website.DbName,
+			User: website.DbUser,
+		}
+		if err = r.dumpDatabase(website.DbType, website.DbName, tempDir+"/database.sql"); err != nil {
+			return "", err
+		}
+	}
+
+	manifestJson, err := json.MarshalIndent(manifest, "", "    ")
+	if err != nil {
+		return "", err
+	}
+	if err = tools.Write(tempDir+"/manifest.json", string(manifestJson), 0644); err != nil {
+		return "", err
+	}
+
+	bundle := backupPath + "/" + website.Name + "_" + carbon.Now().ToShortDateTimeString() + ".tar.gz"
+	if _, err = tools.Exec(`tar -czf '` + bundle + `' -C '` + tempDir + `' .`); err != nil {
+		return "", err
+	}
+	if err = tools.Chmod(bundle, 0600); err != nil {
+		return "", err
+	}
+
+	return bundle, nil
+}
+
+// Import 导入网站迁移包，重建网站、数据库、数据库用户和证书
+func (r *WebsiteImpl) Import(request requests.Import) (models.Website, error) {
+	tempDir, err := tools.TempDir("website_import")
+	if err != nil {
+		return models.Website{}, err
+	}
+	defer tools.Remove(tempDir)
+
+	if _, err = tools.Exec(`tar -xzf '` + request.File + `' -C '` + tempDir + `'`); err != nil {
+		return models.Website{}, errors.New("解压迁移包失败: " + err.Error())
+	}
+	manifestJson, err := tools.Read(tempDir + "/manifest.json")
+	if err != nil {
+		return models.Website{}, errors.New("迁移包中缺少清单文件")
+	}
+	var manifest internal.WebsiteManifest
+	if err = json.Unmarshal([]byte(manifestJson), &manifest); err != nil {
+		return models.Website{}, errors.New("迁移包清单格式错误")
+	}
+	if manifest.Version != 1 {
+		return models.Website{}, errors.New("不支持的迁移包版本")
+	}
+	if len(request.Name) > 0 {
+		manifest.Name = request.Name
+	}
+	if len(request.Domains) > 0 {
+		manifest.Domains = request.Domains
+	}
+	if err = r.validateManifest(manifest); err != nil {
+		return models.Website{}, err
+	}
+
+	name := manifest.Name
+	domains := manifest.Domains
+	path := request.Path
+	if len(path) == 0 {
+		path = r.setting.Get(models.SettingKeyWebsitePath) + "/" + name
+	}
+	if id, _ := r.GetIDByName(name); id != 0 {
+		return models.Website{}, errors.New("网站 " + name + " 已存在")
+	}
+	if tools.Exists(path) {
+		return models.Website{}, errors.New("网站目录 " + path + " 已存在")
+	}
+	if manifest.Php != 0 && !tools.Exists("/www/server/openresty/conf/enable-php-"+strconv.Itoa(manifest.Php)+".conf") {
+		return models.Website{}, errors.New("PHP-" + strconv.Itoa(manifest.Php) + " 未安装")
+	}
+
+	website := internal.PanelWebsite{
+		Name:    name,
+		Status:  true,
+		Domains: domains,
+		Ports:   manifest.Ports,
+		Path:    path,
+		Php:     strconv.Itoa(manifest.Php),
+		Remark:  manifest.Remark,
+	}
+	if manifest.Database != nil {
+		website.Db = true
+		website.DbType = manifest.Database.Type
+		website.DbName = manifest.Database.Name
+		website.DbUser = manifest.Database.User
+		website.DbPassword = request.DbPassword
+		if len(website.DbPassword) == 0 {
+			return models.Website{}, errors.New("迁移包不包含数据库密码，请填写数据库密码")
+		}
+		if r.databaseExists(website.DbType, website.DbName) {
+			return models.Website{}, errors.New("数据库 " + website.DbName + " 已存在")
+		}
+	}
+
+	w, err := r.Add(website)
+	if err != nil {
+		return models.Website{}, err
+	}
+	// 导入中途失败时删除已创建的网站和数据库，避免留下不完整的网站
+	imported := false
+	defer func() {
+		if !imported {
+			r.rollbackImport(w, manifest.Database)
+		}
+	}()
+
+	// 网站文件
+	if err = tools.Remove(path + "/index.html"); err != nil {
+		return w, err
+	}
+	if _, err = tools.Exec(`tar -xzf '` + tempDir + `/site.tar.gz' -C '` + path + `'`); err != nil {
+		return w, errors.New("解压网站文件失败: " + err.Error())
+	}
+	if err = tools.Chown(path, "www", "www"); err != nil {
+		return w, err
+	}
+
+	// 数据库
+	if manifest.Database != nil && tools.Exists(tempDir+"/database.sql") {
+		if err = r.importDatabase(website.DbType, website.DbName, website.DbUser, tempDir+"/database.sql"); err != nil {
+			return w, errors.New("导入数据库失败: " + err.Error())
+		}
+	}
+
+	// 配置
+	setting, err := r.GetConfig(w.ID)
+	if err != nil {
+		return w, err
+	}
+	config := requests.SaveConfig{
+		ID:           w.ID,
+		Domains:      setting.Domains,
+		Ports:        setting.Ports,
+		Hsts:         manifest.Hsts,
+		HttpRedirect: manifest.HttpRedirect,
+		OpenBasedir:  manifest.OpenBasedir,
+		Waf:          manifest.Waf,
+		WafCache:     manifest.WafCache,
+		WafMode:      manifest.WafMode,
+		WafCcDeny:    manifest.WafCcDeny,
+		Index:        manifest.Index,
+		Path:         path,
+		Root:         path + manifest.Root,
+		Raw:          setting.Raw,
+		Php:          manifest.Php,
+	}
+	config.Rewrite, _ = tools.Read(tempDir + "/rewrite.conf")
+	if manifest.Ssl && tools.Exists(tempDir+"/ssl/cert.pem") {
+		config.Ssl = true
+		if config.SslCertificate, err = tools.Read(tempDir + "/ssl/cert.pem"); err != nil {
+			return w, err
+		}
+		if config.SslCertificateKey, err = tools.Read(tempDir + "/ssl/key.pem"); err != nil {
+			return w, err
+		}
+	}
+	if !tools.Exists(config.Root) {
+		config.Root = path
+	}
+	if err = r.SaveConfig(config); err != nil {
+		return w, err
+	}
+
+	// 证书
+	if manifest.Cert != nil && config.Ssl {
+		cert := models.Cert{
+			WebsiteID: &w.ID,
+			Type:      manifest.Cert.Type,
+			Domains:   manifest.Cert.Domains,
+			AutoRenew: false,
+			Cert:      config.SslCertificate,
+			Key:       config.SslCertificateKey,
+		}
+		if err = facades.Orm().Query().Create(&cert); err != nil {
+			return w, err
+		}
+		fingerprint, _ := acme.Fingerprint([]byte(cert.Cert))
+		now := carbon.DateTime{Carbon: carbon.Now()}
+		if err = facades.Orm().Query().Create(&models.CertDeployment{CertID: cert.ID, WebsiteID: w.ID, Fingerprint: fingerprint, DeployedAt: &now}); err != nil {
+			return w, err
+		}
+	}
+
+	imported = true
+	return w, facades.Orm().Query().Where("id", w.ID).First(&w)
+}
+
+// rollbackImport 回滚导入失败的网站
+func (r *WebsiteImpl) rollbackImport(website models.Website, database *internal.WebsiteManifestDatabase) {
+	_, _ = facades.Orm().Query().Where("website_id", website.ID).Delete(&models.CertDeployment{})
+	_, _ = facades.Orm().Query().Where("website_id", website.ID).Delete(&models.Cert{})
+	if err := r.Delete(website.ID); err != nil {
+		facades.Log().Infof("[面板][WebsiteService] 回滚导入的网站%s失败: %s", website.Name, err.Error())
+	}
+	if database != nil {
+		r.dropDatabase(database.Type, database.Name, database.User)
+	}
+}
+
+// validateManifest 检查迁移包清单，清单来自上传的文件，写入配置文件、路径和命令前必须校验
+func (r *WebsiteImpl) validateManifest(manifest internal.WebsiteManifest) error {
+	if !regexp.MustCompile(`^[a-zA-Z0-9_-]+(\.[a-zA-Z0-9_-]+)*$`).MatchString(manifest.Name) || slices.Contains([]string{"phpmyadmin", "mysql", "panel", "ssh"}, manifest.Name) {
+		return errors.New("迁移包中的网站名不合法")
+	}
+	if len(manifest.Domains) == 0 {
+		return errors.New("迁移包中没有域名")
+	}
+	for _, domain := range manifest.Domains {
+		if !regexp.MustCompile(`^[a-zA-Z0-9*_.\-\[\]:]+$`).MatchString(domain) {
+			return errors.New("迁移包中的域名 " + domain + " 不合法")
+		}
+	}
+	if len(manifest.Ports) == 0 {
+		return errors.New("迁移包中没有端口")
+	}
+	for _, port := range manifest.Ports {
+		if port == 0 || port > 65535 {
+			return errors.New("迁移包中的端口不合法")
+		}
+	}
+	// 以下配置会写入网站配置文件，不允许分号、引号、括号和换行
+	value := regexp.MustCompile(`^[a-zA-Z0-9_=/!., -]*$`)
+	for _, item := range []string{manifest.Index, manifest.WafMode, manifest.WafCcDeny, manifest.WafCache} {
+		if !value.MatchString(item) {
+			return errors.New("迁移包中的网站配置不合法")
+		}
+	}
+	if !regexp.MustCompile(`^(/[a-zA-Z0-9_.@\-]+)*$`).MatchString(manifest.Root) || slices.Contains(strings.Split(manifest.Root, "/"), "..") {
+		return errors.New("迁移包中的运行目录不合法")
+	}
+	if manifest.Database != nil {
+		if !slices.Contains([]string{"mysql", "postgresql"}, manifest.Database.Type) {
+			return errors.New("迁移包中的数据库类型不合法")
+		}
+		database := regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+		if !database.MatchString(manifest.Database.Name) || !database.MatchString(manifest.Database.User) {
+			return errors.New("迁移包中的数据库名或用户名不合法")
+		}
+	}
+
+	return nil
+}
+
+// dumpDatabase 导出数据库到文件
+func (r *WebsiteImpl) dumpDatabase(dbType, name, file string) error {
+	switch dbType {
+	case "mysql":
+		if err := os.Setenv("MYSQL_PWD", r.setting.Get(models.SettingKeyMysqlRootPassword)); err != nil {
+			return err
+		}
+		defer os.Unsetenv("MYSQL_PWD")
+		_, err := tools.Exec("/www/server/mysql/bin/mysqldump -uroot --single-transaction '" + name + "' > '" + file + "'")
+		return err
+	case "postgresql":
+		_, err := tools.Exec(`su - postgres -c "pg_dump --no-owner --no-privileges ` + name + `" > '` + file + `'`)
+		return err
+	}
+
+	return errors.New("不支持的数据库类型")
+}
+
+// importDatabase 导入数据库文件
+func (r *WebsiteImpl) importDatabase(dbType, name, user, file string) error {
+	switch dbType {
+	case "mysql":
+		if err := os.Setenv("MYSQL_PWD", r.setting.Get(models.SettingKeyMysqlRootPassword)); err != nil {
+			return err
+		}
+		defer os.Unsetenv("MYSQL_PWD")
+		_, err := tools.Exec("/www/server/mysql/bin/mysql -uroot '" + name + "' < '" + file + "'")
+		return err
+	case "postgresql":
+		// 以数据库用户的身份导入，使导入的对象归属于该用户
+		_, err := tools.Exec(`(echo "SET ROLE ` + user + `;"; cat '` + file + `') | su - postgres -c "psql -v ON_ERROR_STOP=1 ` + name + `"`)
+		return err
+	}
+
+	return errors.New("不支持的数据库类型")
+}
+
+// dropDatabase 删除数据库和数据库用户
+func (r *WebsiteImpl) dropDatabase(dbType, name, user string) {
+	switch dbType {
+	case "mysql":
+		if err := os.Setenv("MYSQL_PWD", r.setting.Get(models.SettingKeyMysqlRootPassword)); err != nil {
+			return
+		}
+		defer os.Unsetenv("MYSQL_PWD")
+		_, _ = tools.Exec(`/www/server/mysql/bin/mysql -uroot -e "DROP DATABASE IF EXISTS ` + name + `; DROP USER IF EXISTS '` + user + `'@'localhost'; FLUSH PRIVILEGES;"`)
+	case "postgresql":
+		_, _ = tools.Exec(`echo "DROP DATABASE IF EXISTS ` + name + `;" | su - postgres -c "psql"`)
+		_, _ = tools.Exec(`echo "DROP USER IF EXISTS ` + user + `;" | su - postgres -c "psql"`)
+		_, _ = tools.Exec(`sed -i '/^host    ` + name + `    ` + user + `    /d' /www/server/postgresql/data/pg_hba.conf`)
+		_ = tools.ServiceReload("postgresql")
+	}
+}
+
+// databaseExists 判断数据库是否已存在
+func (r *WebsiteImpl) databaseExists(dbType, name string) bool {
+	switch dbType {
+	case "mysql":
+		if err := os.Setenv("MYSQL_PWD", r.setting.Get(models.SettingKeyMysqlRootPassword)); err != nil {
+			return false
+		}
+		defer os.Unsetenv("MYSQL_PWD")
+		out, _ := tools.Exec(`/www/server/mysql/bin/mysql -uroot -N -e "SHOW DATABASES LIKE '` + name + `';"`)
+		return len(out) > 0
+	case "postgresql":
+		out, _ := tools.Exec(`su - postgres -c "psql -tAc \"SELECT 1 FROM pg_database WHERE datname = '` + name + `'\""`)
+		return out == "1"
+	}
+
+	return false
+}
+
+// GetPhpPool 获取网站的独立 PHP-FPM 应用池
+func (r *WebsiteImpl) GetPhpPool(id uint) (models.PhpPool, error) {
+	var pool models.PhpPool
+	err := facades.Orm().Query().Where("website_id", id).First(&pool)
+
+	return pool, err
+}
+
+// SavePhpPool 创建或更新网站的独立 PHP-FPM 应用池
+func (r *WebsiteImpl) SavePhpPool(request requests.SavePhpPool) error {
+	var website models.Website
+	if err := facades.Orm().Query().Where("id", request.ID).FirstOrFail(&website); err != nil {
+		return err
+	}
+
+	return r.savePhpPool(website, models.PhpPool{
+		User:            request.User,
+		Pm:              request.Pm,
+		MaxChildren:     request.MaxChildren,
+		StartServers:    request.StartServers,
+		MinSpareServers: request.MinSpareServers,
+		MaxSpareServers: request.MaxSpareServers,
+		Ini:             request.Ini,
+	})
+}
+
+// savePhpPool 校验并保存应用池，写入 PHP-FPM 配置和网站配置
+func (r *WebsiteImpl) savePhpPool(website models.Website, settings models.PhpPool) error {
+	if website.Php == 0 {
+		return errors.New("网站未使用 PHP")
+	}
+
+	var pool models.PhpPool
+	if err := facades.Orm().Query().Where("website_id", website.ID).First(&pool); err != nil {
+		return err
+	}
+	if err := r.validatePhpPool(settings, pool.User); err != nil {
+		return err
+	}
+	if pool.User != settings.User {
+		if _, err := tools.Exec("useradd -r -M -d '" + website.Path + "' -s /sbin/nologin '" + settings.User + "'"); err != nil {
+			return errors.New("创建用户失败: " + err.Error())
+		}
+	}
+
+	oldUser := pool.User
+	pool.WebsiteID = website.ID
+	pool.User = settings.User
+	pool.Pm = settings.Pm
+	pool.MaxChildren = settings.MaxChildren
+	pool.StartServers = settings.StartServers
+	pool.MinSpareServers = settings.MinSpareServers
+	pool.MaxSpareServers = settings.MaxSpareServers
+	pool.Ini = settings.Ini
+	if err := facades.Orm().Query().Save(&pool); err != nil {
+		return err
+	}
+	if len(oldUser) > 0 && oldUser != pool.User {
+		r.deletePoolUser(oldUser)
+	}
+
+	if err := tools.Chown(website.Path, pool.User, "www"); err != nil {
+		return err
+	}
+	if err := r.writePhpPool(website, pool); err != nil {
+		return err
+	}
+
+	return r.updatePhpConfig(website)
+}
+
+// validatePhpPool 校验应用池配置和用户，currentUser 为应用池当前使用的用户，新建时为空
+func (r *WebsiteImpl) validatePhpPool(settings models.PhpPool, currentUser string) error {
+	switch settings.Pm {
+	case "dynamic":
+		if settings.MinSpareServers < 1 || settings.MaxSpareServers < settings.MinSpareServers {
+			return errors.New("最小空闲进程数需大于 0 且不大于最大空闲进程数")
+		}
+		if settings.StartServers < settings.MinSpareServers || settings.StartServers > settings.MaxSpareServers {
+			return errors.New("启动进程数需介于最小和最大空闲进程数之间")
+		}
+		if settings.MaxChildren < settings.MaxSpareServers {
+			return errors.New("最大子进程数不能小于最大空闲进程数")
+		}
+	}
+	for key, value := range settings.Ini {
+		if !regexp.MustCompile(`^[a-z0-9_.]+$`).MatchString(key) || strings.ContainsAny(value, "\r\n") {
+			return errors.New("php.ini 配置项 " + key + " 格式错误")
+		}
+	}
+
+	// 每个用户只能被一个应用池使用，网站之间互相隔离，也不能占用系统已有用户
+	if currentUser != settings.User {
+		var count int64
+		if err := facades.Orm().Query().Model(&models.PhpPool{}).Where("user", settings.User).Count(&count); err != nil {
+			return err
+		}
+		if count > 0 {
+			return errors.New("用户 " + settings.User + " 已被其他网站的应用池使用，请更换用户名")
+		}
+		if _, err := tools.Exec("id -u '" + settings.User + "'"); err == nil {
+			return errors.New("系统用户 " + settings.User + " 已存在，请更换用户名")
+		}
+	}
+
+	return nil
+}
+
+// DeletePhpPool 删除网站的独立 PHP-FPM 应用池，网站改回使用公共应用池
+func (r *WebsiteImpl) DeletePhpPool(id uint) error {
+	var website models.Website
+	if err := facades.Orm().Query().Where("id", id).FirstOrFail(&website); err != nil {
+		return err
+	}
+	var pool models.PhpPool
+	if err := facades.Orm().Query().Where("website_id", website.ID).FirstOrFail(&pool); err != nil {
+		return errors.New("网站未使用独立应用池")
+	}
+
+	if err := r.removePhpPool(website, pool); err != nil {
+		return err
+	}
+	if err := tools.Chown(website.Path, "www", "www"); err != nil {
+		return err
+	}
+
+	return r.updatePhpConfig(website)
+}
+
+// InspectCert 检查网站当前使用的证书
+func (r *WebsiteImpl) InspectCert(id uint) (acme.Inspection, error) {
+	setting, err := r.GetConfig(id)
+	if err != nil {
+		return acme.Inspection{}, err
+	}
+	if !setting.Ssl || setting.SslCertificate == "" {
+		return acme.Inspection{}, errors.New("网站未开启 HTTPS")
+	}
+
+	// 部署的是私有 CA 签发的证书时信任该 CA 的根证书
+	var roots []byte
+	var deployments []models.CertDeployment
+	if err = facades.Orm().Query().With("Cert.CA").Where("website_id", id).Find(&deployments); err != nil {
+		return acme.Inspection{}, err
+	}
+	for _, deployment := range deployments {
+		if deployment.Cert != nil && deployment.Cert.CA != nil {
+			roots = append(roots, deployment.Cert.CA.RootCert...)
+		}
+	}
+
+	return acme.Inspect(context.Background(), []byte(setting.SslCertificate), []byte(setting.SslCertificateKey), setting.Domains, roots)
+}
+
+// phpConfig 生成 php 标记位的配置，使用独立应用池的网站直接连接应用池的 socket
+func (r *WebsiteImpl) phpConfig(website models.Website) string {
+	var pool models.PhpPool
+	if err := facades.Orm().Query().Where("website_id", website.ID).First(&pool); err != nil || pool.ID == 0 || website.Php == 0 {
+		return `
+    include enable-php-` + strconv.Itoa(website.Php) + `.conf;
+    `
+	}
+
+	return `
+    location ~ \.php$ {
+        try_files $uri =404;
+        fastcgi_pass unix:` + PhpPoolSocket(website.Php, website.Name) + `;
+        fastcgi_index index.php;
+        include fastcgi.conf;
+        include pathinfo.conf;
+    }
+    `
+}
+
+// updatePhpConfig 更新网站配置文件的 php 标记位
+func (r *WebsiteImpl) updatePhpConfig(website models.Website) error {
+	return r.updateVhost(website, func(raw string) (string, error) {
+		return r.replaceMarker(raw, "php", r.phpConfig(website))
+	})
+}
+
+// updateVhost 修改网站配置文件，内容有变化时写入并重载 OpenResty
+func (r *WebsiteImpl) updateVhost(website models.Website, update func(raw string) (string, error)) error {
+	raw, err := tools.Read("/www/server/vhost/" + website.Name + ".conf")
+	if err != nil {
+		return err
+	}
+	updated, err := update(raw)
+	if err != nil {
+		return err
+	}
+	if updated == raw {
+		return nil
+	}
+	if err = tools.Write("/www/server/vhost/"+website.Name+".conf", updated, 0644); err != nil {
+		return err
+	}
+
+	return tools.ServiceReload("openresty")
+}
+
+// replaceMarker 替换配置文件中标记位之间的内容
+func (r *WebsiteImpl) replaceMarker(raw, marker, content string) (string, error) {
+	if !r.hasMarker(raw, marker) {
+		return "", errors.New("配置文件中缺少" + marker + "标记位")
+	}
+	old := tools.Cut(raw, "# "+marker+"标记位开始", "# "+marker+"标记位结束")
+
+	return strings.Replace(raw, old, content, -1), nil
+}
+
+// hasMarker 判断配置文件中是否存在非空的标记位
+func (r *WebsiteImpl) hasMarker(raw, marker string) bool {
+	return len(strings.TrimSpace(tools.Cut(raw, "# "+marker+"标记位开始", "# "+marker+"标记位结束"))) != 0
+}
+
+// writePhpPool 写入应用池配置并重载 PHP-FPM
+func (r *WebsiteImpl) writePhpPool(website models.Website, pool models.PhpPool) error {
+	php := strconv.Itoa(website.Php)
+	var config strings.Builder
+	config.WriteString("[" + website.Name + "]\n")
+	config.WriteString("listen = " + PhpPoolSocket(website.Php, website.Name) + "\n")
+	config.WriteString("listen.owner = www\nlisten.group = www\nlisten.mode = 0660\n")
+	config.WriteString("user = " + pool.User + "\ngroup = " + pool.User + "\n")
+	config.WriteString("pm = " + pool.Pm + "\n")
+	config.WriteString("pm.max_children = " + strconv.Itoa(pool.MaxChildren) + "\n")
+	switch pool.Pm {
+	case "dynamic":
+		config.WriteString("pm.start_servers = " + strconv.Itoa(pool.StartServers) + "\n")
+		config.WriteString("pm.min_spare_servers = " + strconv.Itoa(pool.MinSpareServers) + "\n")
+		config.WriteString("pm.max_spare_servers = " + strconv.Itoa(pool.MaxSpareServers) + "\n")
+	case "ondemand":
+		config.WriteString("pm.process_idle_timeout = 10s\n")
+	}
+	config.WriteString("pm.status_path = /phpfpm_status\n")
+	config.WriteString("request_terminate_timeout = 100\nrequest_slowlog_timeout = 30\n")
+	config.WriteString("slowlog = var/log/slow-" + website.Name + ".log\n")
+	keys := make([]string, 0, len(pool.Ini))
+	for key := range pool.Ini {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		config.WriteString("php_admin_value[" + key + "] = " + pool.Ini[key] + "\n")
+	}
+
+	// 确保主配置引入了应用池目录
+	fpmConfig, err := tools.Read("/www/server/php/" + php + "/etc/php-fpm.conf")
+	if err != nil {
+		return err
+	}
+	include := "include = /www/server/php/" + php + "/etc/php-fpm.d/*.conf"
+	if !strings.Contains(fpmConfig, include) {
+		if err = tools.Write("/www/server/php/"+php+"/etc/php-fpm.conf", strings.TrimRight(fpmConfig, "\n")+"\n\n"+include+"\n", 0644); err != nil {
+			return err
+		}
+	}
+	if err = tools.Write("/www/server/php/"+php+"/etc/php-fpm.d/"+website.Name+".conf", config.String(), 0644); err != nil {
+		return err
+	}
+
+	return tools.ServiceReload("php-fpm-" + php)
+}
+
+// removePhpPool 删除应用池配置、记录和不再使用的用户
+func (r *WebsiteImpl) removePhpPool(website models.Website, pool models.PhpPool) error {
+	if _, err := facades.Orm().Query().Delete(&pool); err != nil {
+		return err
+	}
+	r.deletePoolUser(pool.User)
+
+	if website.Php == 0 {
+		return nil
+	}
+	php := strconv.Itoa(website.Php)
+	if err := tools.Remove("/www/server/php/" + php + "/etc/php-fpm.d/" + website.Name + ".conf"); err != nil {
+		return err
+	}
+
+	return tools.ServiceReload("php-fpm-" + php)
+}
+
+// movePhpPool 网站切换 PHP 版本时迁移应用池
+func (r *WebsiteImpl) movePhpPool(website models.Website, php int) error {
+	var pool models.PhpPool
+	if err := facades.Orm().Query().Where("website_id", website.ID).First(&pool); err != nil {
+		return err
+	}
+	if pool.ID == 0 || website.Php == php {
+		return nil
+	}
+	if php == 0 {
+		return errors.New("网站使用了独立应用池，请先删除应用池再关闭 PHP")
+	}
+
+	old := strconv.Itoa(website.Php)
+	if err := tools.Remove("/www/server/php/" + old + "/etc/php-fpm.d/" + website.Name + ".conf"); err != nil {
+		return err
+	}
+	if err := tools.ServiceReload("php-fpm-" + old); err != nil {
+		return err
+	}
+	moved := website
+	moved.Php = php
+	if err := r.writePhpPool(moved, pool); err != nil {
+		// 新版本写入失败时恢复到原版本
+		_ = r.writePhpPool(website, pool)
+		return err
+	}
+
+	return nil
+}
+
+// deletePoolUser 删除不再被应用池使用的用户
+func (r *WebsiteImpl) deletePoolUser(user string) {
+	var count int64
+	if err := facades.Orm().Query().Model(&models.PhpPool{}).Where("user", user).Count(&count); err != nil || count > 0 {
+		return
+	}
+	_, _ = tools.Exec("userdel '" + user + "'")
+}
+
+// PhpPoolSocket 获取独立应用池的 socket 路径
+func PhpPoolSocket(php int, name string) string {
+	return "/tmp/php-cgi-" + strconv.Itoa(php) + "-" + name + ".sock"
+}
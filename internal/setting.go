@@ -1,7 +1,24 @@
 package internal
 
+import (
+	requests "panel/app/http/requests/setting"
+	"panel/app/models"
+)
+
 type Setting interface {
 	Get(key string, defaultValue ...string) string
 	Set(key, value string) error
 	Delete(key string) error
+	HTTPS() (PanelHTTPS, error)
+	UpdateSSL(enabled bool) error
+	ScheduleRestart() error
+	UpdateHTTPS(request requests.HTTPS) (*models.Task, error)
+	DeleteHTTPS() error
+}
+
+// PanelHTTPS 面板 HTTPS 设置
+type PanelHTTPS struct {
+	Enabled  bool         `json:"enabled"`  // 面板是否已开启 HTTPS
+	Redirect bool         `json:"redirect"` // HTTP 请求是否跳转到 HTTPS
+	Cert     *models.Cert `json:"cert"`     // 面板绑定的证书，未绑定时为空
 }
@@ -1,8 +1,12 @@
 package internal
 
 import (
+	"io"
+
 	requests "panel/app/http/requests/website"
 	"panel/app/models"
+	"panel/pkg/acme"
+	"panel/pkg/htaccess"
 )
 
 type Website interface {
@@ -13,6 +17,19 @@ type Website interface {
 	GetConfig(id uint) (WebsiteSetting, error)
 	GetConfigByName(name string) (WebsiteSetting, error)
 	GetIDByName(name string) (uint, error)
+	ConvertHtaccess(id uint, content string) (htaccess.Result, error)
+	SaveRewrite(id uint, rewrite string) error
+	UpdateStatus(id uint, status bool) error
+	UpdatePHP(id uint, php int) error
+	UpdateHttpRedirect(id uint, enable bool) error
+	Batch(request requests.Batch) error
+	BatchRun(action string, ids []uint, php int, log io.Writer) error
+	Export(request requests.Export) (string, error)
+	Import(request requests.Import) (models.Website, error)
+	GetPhpPool(id uint) (models.PhpPool, error)
+	SavePhpPool(request requests.SavePhpPool) error
+	DeletePhpPool(id uint) error
+	InspectCert(id uint) (acme.Inspection, error)
 }
 
 type PanelWebsite struct {
@@ -29,6 +46,8 @@ type PanelWebsite struct {
 	DbName     string   `json:"db_name"`
 	DbUser     string   `json:"db_user"`
 	DbPassword string   `json:"db_password"`
+
+	PhpPool *models.PhpPool `json:"php_pool"` // 独立 PHP-FPM 应用池，为空时使用公共应用池
 }
 
 // WebsiteSetting 网站设置
@@ -59,3 +78,40 @@ type WebsiteSetting struct {
 	Raw               string   `json:"raw"`
 	Log               string   `json:"log"`
 }
+
+// WebsiteManifest 网站迁移包清单
+type WebsiteManifest struct {
+	Version      int                      `json:"version"`
+	PanelVersion string                   `json:"panel_version"`
+	CreatedAt    string                   `json:"created_at"`
+	Name         string                   `json:"name"`
+	Domains      []string                 `json:"domains"`
+	Ports        []uint                   `json:"ports"`
+	Root         string                   `json:"root"` // 运行目录相对于网站目录的路径
+	Index        string                   `json:"index"`
+	Php          int                      `json:"php"`
+	OpenBasedir  bool                     `json:"open_basedir"`
+	Ssl          bool                     `json:"ssl"`
+	HttpRedirect bool                     `json:"http_redirect"`
+	Hsts         bool                     `json:"hsts"`
+	Waf          bool                     `json:"waf"`
+	WafMode      string                   `json:"waf_mode"`
+	WafCcDeny    string                   `json:"waf_cc_deny"`
+	WafCache     string                   `json:"waf_cache"`
+	Remark       string                   `json:"remark"`
+	Database     *WebsiteManifestDatabase `json:"database"`
+	Cert         *WebsiteManifestCert     `json:"cert"`
+}
+
+// WebsiteManifestDatabase 迁移包中的数据库信息
+type WebsiteManifestDatabase struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	User string `json:"user"`
+}
+
+// WebsiteManifestCert 迁移包中的证书信息
+type WebsiteManifestCert struct {
+	Type    string   `json:"type"`
+	Domains []string `json:"domains"`
+}
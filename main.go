@@ -18,8 +18,12 @@ package main
 
 import (
 	"github.com/goravel/framework/facades"
+	"github.com/spf13/cast"
 
+	"panel/app/models"
 	"panel/bootstrap"
+	"panel/internal/services"
+	"panel/pkg/tlsserver"
 )
 
 //	@title			耗子 Linux 面板 API
@@ -44,7 +48,14 @@ func main() {
 	// 启动 HTTP 服务
 	if facades.Config().GetBool("panel.ssl") {
 		go func() {
-			if err := facades.Route().RunTLS(); err != nil {
+			// 使用自定义 HTTPS 服务，证书续签后无需重启面板
+			err := tlsserver.Serve(tlsserver.Config{
+				Addr:     ":" + facades.Config().GetString("http.tls.port"),
+				CertFile: facades.Config().GetString("http.tls.ssl.cert"),
+				KeyFile:  facades.Config().GetString("http.tls.ssl.key"),
+				Redirect: cast.ToBool(services.NewSettingImpl().Get(models.SettingKeyPanelHTTPSRedirect)),
+			}, facades.Route())
+			if err != nil {
 				facades.Log().Infof("Route run error: %v", err)
 			}
 		}()
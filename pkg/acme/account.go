@@ -0,0 +1,54 @@
+package acme
+
+import (
+	"context"
+
+	"github.com/mholt/acmez/v2/acme"
+)
+
+// AccountStatusValid 和 AccountStatusDeactivated 为 ACME 账号状态
+const (
+	AccountStatusValid       = acme.StatusValid
+	AccountStatusDeactivated = acme.StatusDeactivated
+)
+
+// UpdateContact 更新 CA 上的账号联系邮箱
+func (c *Client) UpdateContact(ctx context.Context, email string) error {
+	account := c.Account
+	account.Contact = []string{"mailto:" + email}
+	account, err := c.zClient.UpdateAccount(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	c.Account = account
+	return nil
+}
+
+// RolloverKey 将账号密钥更换为新生成的 keyType 类型密钥，返回新私钥
+func (c *Client) RolloverKey(ctx context.Context, keyType KeyType) ([]byte, error) {
+	key, err := generatePrivateKey(keyType)
+	if err != nil {
+		return nil, err
+	}
+	account, err := c.zClient.AccountKeyRollover(ctx, c.Account, key)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Account = account
+	return EncodePrivateKey(key)
+}
+
+// Deactivate 在 CA 上停用账号，停用后无法恢复，账号签发的证书不受影响
+func (c *Client) Deactivate(ctx context.Context) error {
+	account := c.Account
+	account.Status = AccountStatusDeactivated
+	account, err := c.zClient.UpdateAccount(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	c.Account = account
+	return nil
+}
@@ -0,0 +1,243 @@
+package acme
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+)
+
+// CertUsage 私有 CA 签发的证书用途
+type CertUsage string
+
+const (
+	UsageServer CertUsage = "server"
+	UsageClient CertUsage = "client"
+)
+
+// CA 私有 CA，由根证书签发中间证书，中间证书签发终端证书
+type CA struct {
+	RootCert         []byte
+	RootKey          []byte
+	IntermediateCert []byte
+	IntermediateKey  []byte
+}
+
+// IssueRequest 私有 CA 签发请求
+type IssueRequest struct {
+	CommonName string
+	Domains    []string // 域名、IP 或邮箱
+	Usage      CertUsage
+	Days       int
+	KeyType    KeyType
+	CRLURL     string // CRL 分发地址，可选
+}
+
+// Revocation 吊销记录
+type Revocation struct {
+	Serial    string    `json:"serial"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+// GenerateCA 生成私有 CA 的根证书和中间证书
+func GenerateCA(name string, keyType KeyType, days int) (CA, error) {
+	if days <= 0 {
+		return CA{}, errors.New("有效期必须大于 0")
+	}
+	now := time.Now()
+
+	rootKey, err := generatePrivateKey(keyType)
+	if err != nil {
+		return CA{}, err
+	}
+	rootSerial, err := randomSerial()
+	if err != nil {
+		return CA{}, err
+	}
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          rootSerial,
+		Subject:               pkix.Name{CommonName: name + " Root CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(0, 0, days),
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLen:            1,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, rootKey.Public(), rootKey)
+	if err != nil {
+		return CA{}, err
+	}
+	rootCert, err := x509.ParseCertificate(rootDER)
+	if err != nil {
+		return CA{}, err
+	}
+
+	interKey, err := generatePrivateKey(keyType)
+	if err != nil {
+		return CA{}, err
+	}
+	interSerial, err := randomSerial()
+	if err != nil {
+		return CA{}, err
+	}
+	interTemplate := &x509.Certificate{
+		SerialNumber:          interSerial,
+		Subject:               pkix.Name{CommonName: name + " Intermediate CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              rootCert.NotAfter,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	interDER, err := x509.CreateCertificate(rand.Reader, interTemplate, rootCert, interKey.Public(), rootKey)
+	if err != nil {
+		return CA{}, err
+	}
+
+	ca := CA{
+		RootCert:         pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: rootDER}),
+		IntermediateCert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: interDER}),
+	}
+	if ca.RootKey, err = EncodePrivateKey(rootKey); err != nil {
+		return CA{}, err
+	}
+	if ca.IntermediateKey, err = EncodePrivateKey(interKey); err != nil {
+		return CA{}, err
+	}
+
+	return ca, nil
+}
+
+// Issue 使用中间证书签发证书，返回证书链（不含根证书）、私钥和十六进制序列号
+func (ca CA) Issue(request IssueRequest) ([]byte, []byte, string, error) {
+	if len(request.Domains) == 0 {
+		return nil, nil, "", errors.New("至少需要一个域名、IP 或邮箱")
+	}
+	if request.Days <= 0 {
+		return nil, nil, "", errors.New("有效期必须大于 0")
+	}
+	issuer, issuerKey, err := ca.intermediate()
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	key, err := generatePrivateKey(request.KeyType)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: request.CommonName},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.AddDate(0, 0, request.Days),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	if template.NotAfter.After(issuer.NotAfter) {
+		template.NotAfter = issuer.NotAfter
+	}
+	if _, ok := key.(*rsa.PrivateKey); ok {
+		template.KeyUsage |= x509.KeyUsageKeyEncipherment
+	}
+	switch request.Usage {
+	case UsageServer:
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	case UsageClient:
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	default:
+		return nil, nil, "", fmt.Errorf("未知的证书用途 %s", request.Usage)
+	}
+	for _, domain := range request.Domains {
+		switch {
+		case net.ParseIP(domain) != nil:
+			template.IPAddresses = append(template.IPAddresses, net.ParseIP(domain))
+		case strings.Contains(domain, "@"):
+			template.EmailAddresses = append(template.EmailAddresses, domain)
+		default:
+			template.DNSNames = append(template.DNSNames, domain)
+		}
+	}
+	if template.Subject.CommonName == "" {
+		template.Subject.CommonName = request.Domains[0]
+	}
+	if request.CRLURL != "" {
+		template.CRLDistributionPoints = []string{request.CRLURL}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, key.Public(), issuerKey)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	keyPEM, err := EncodePrivateKey(key)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	chain := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	chain = append(chain, ca.IntermediateCert...)
+	return chain, keyPEM, serial.Text(16), nil
+}
+
+// CRL 使用中间证书生成吊销列表
+func (ca CA) CRL(revocations []Revocation, number int64, nextUpdate time.Time) ([]byte, error) {
+	issuer, issuerKey, err := ca.intermediate()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []x509.RevocationListEntry
+	for _, revocation := range revocations {
+		serial, ok := new(big.Int).SetString(revocation.Serial, 16)
+		if !ok {
+			return nil, fmt.Errorf("序列号 %s 格式错误", revocation.Serial)
+		}
+		entries = append(entries, x509.RevocationListEntry{
+			SerialNumber:   serial,
+			RevocationTime: revocation.RevokedAt,
+		})
+	}
+
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		RevokedCertificateEntries: entries,
+		Number:                    big.NewInt(number),
+		ThisUpdate:                time.Now(),
+		NextUpdate:                nextUpdate,
+	}, issuer, issuerKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}), nil
+}
+
+func (ca CA) intermediate() (*x509.Certificate, crypto.Signer, error) {
+	certs, err := ParseCertificates(ca.IntermediateCert)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := parsePrivateKey(ca.IntermediateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return certs[0], key, nil
+}
+
+func randomSerial() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
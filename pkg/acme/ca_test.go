@@ -0,0 +1,84 @@
+package acme
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CATestSuite struct {
+	suite.Suite
+}
+
+func TestCATestSuite(t *testing.T) {
+	suite.Run(t, &CATestSuite{})
+}
+
+func (s *CATestSuite) TestIssueAndRevoke() {
+	ca, err := GenerateCA("HaoZi", KeyEC256, 3650)
+	s.Require().Nil(err)
+
+	roots := x509.NewCertPool()
+	s.True(roots.AppendCertsFromPEM(ca.RootCert))
+	intermediates := x509.NewCertPool()
+	s.True(intermediates.AppendCertsFromPEM(ca.IntermediateCert))
+
+	chain, key, serial, err := ca.Issue(IssueRequest{
+		Domains: []string{"internal.haozi.dev", "10.0.0.1"},
+		Usage:   UsageServer,
+		Days:    30,
+		KeyType: KeyEC256,
+		CRLURL:  "http://10.0.0.1/ca.crl",
+	})
+	s.Require().Nil(err)
+	s.NotEmpty(key)
+	certs, err := ParseCertificates(chain)
+	s.Require().Nil(err)
+	s.Len(certs, 2)
+	s.Equal("internal.haozi.dev", certs[0].Subject.CommonName)
+	s.Equal([]string{"http://10.0.0.1/ca.crl"}, certs[0].CRLDistributionPoints)
+	_, err = certs[0].Verify(x509.VerifyOptions{
+		DNSName:       "internal.haozi.dev",
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	s.Nil(err)
+
+	// 客户端证书不能用于服务端认证
+	chain, _, _, err = ca.Issue(IssueRequest{
+		Domains: []string{"admin@haozi.dev"},
+		Usage:   UsageClient,
+		Days:    30,
+		KeyType: KeyRSA2048,
+	})
+	s.Require().Nil(err)
+	certs, err = ParseCertificates(chain)
+	s.Require().Nil(err)
+	s.Equal([]string{"admin@haozi.dev"}, certs[0].EmailAddresses)
+	_, err = certs[0].Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates})
+	s.Error(err)
+	_, err = certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	s.Nil(err)
+
+	data, err := ca.CRL([]Revocation{{Serial: serial, RevokedAt: time.Now()}}, 2, time.Now().Add(24*time.Hour))
+	s.Require().Nil(err)
+	block, _ := pem.Decode(data)
+	s.Require().NotNil(block)
+	crl, err := x509.ParseRevocationList(block.Bytes)
+	s.Require().Nil(err)
+	s.Len(crl.RevokedCertificateEntries, 1)
+	s.Equal(serial, crl.RevokedCertificateEntries[0].SerialNumber.Text(16))
+	intermediate, err := ParseCertificates(ca.IntermediateCert)
+	s.Require().Nil(err)
+	s.Nil(crl.CheckSignatureFrom(intermediate[0]))
+
+	_, _, _, err = ca.Issue(IssueRequest{Domains: []string{"haozi.dev"}, Usage: "unknown", Days: 1, KeyType: KeyEC256})
+	s.Error(err)
+}
@@ -2,6 +2,7 @@ package acme
 
 import (
 	"context"
+	"crypto/x509"
 	"sort"
 
 	"github.com/libdns/libdns"
@@ -55,8 +56,22 @@ func (c *Client) UseHTTP(path string) {
 	}
 }
 
+// UseHTTPServer 使用临时监听 addr 的 HTTP 服务进行 HTTP 验证
+func (c *Client) UseHTTPServer(addr string) {
+	c.zClient.ChallengeSolvers = map[string]acmez.Solver{
+		acme.ChallengeTypeHTTP01: &httpServerSolver{
+			addr: addr,
+		},
+	}
+}
+
 // ObtainSSL 签发 SSL 证书
 func (c *Client) ObtainSSL(ctx context.Context, domains []string, keyType KeyType) (Certificate, error) {
+	return c.obtain(ctx, domains, keyType, nil)
+}
+
+// obtain 签发证书，续签时 replaces 为被替换的旧证书
+func (c *Client) obtain(ctx context.Context, domains []string, keyType KeyType, replaces *x509.Certificate) (Certificate, error) {
 	certPrivateKey, err := generatePrivateKey(keyType)
 	if err != nil {
 		return Certificate{}, err
@@ -66,7 +81,17 @@ func (c *Client) ObtainSSL(ctx context.Context, domains []string, keyType KeyTyp
 		return Certificate{}, err
 	}
 
-	certs, err := c.zClient.ObtainCertificateForSANs(ctx, c.Account, certPrivateKey, domains)
+	csr, err := acmez.NewCSR(certPrivateKey, domains)
+	if err != nil {
+		return Certificate{}, err
+	}
+	params, err := acmez.OrderParametersFromCSR(c.Account, csr)
+	if err != nil {
+		return Certificate{}, err
+	}
+	params.Replaces = replaces
+
+	certs, err := c.zClient.ObtainCertificate(ctx, params)
 	if err != nil {
 		return Certificate{}, err
 	}
@@ -91,12 +116,30 @@ func (c *Client) ObtainSSLManual() (Certificate, error) {
 
 // RenewSSL 续签 SSL 证书
 func (c *Client) RenewSSL(ctx context.Context, certUrl string, domains []string, keyType KeyType) (Certificate, error) {
-	_, err := c.zClient.GetCertificateChain(ctx, c.Account, certUrl)
+	chains, err := c.zClient.GetCertificateChain(ctx, c.Account, certUrl)
 	if err != nil {
 		return Certificate{}, err
 	}
 
-	return c.ObtainSSL(ctx, domains, keyType)
+	// CA 支持 ARI 时在订单中标明被替换的证书
+	var replaces *x509.Certificate
+	if old := c.selectPreferredChain(chains); old.RenewalInfo != nil {
+		if certs, err := ParseCertificates(old.ChainPEM); err == nil {
+			replaces = certs[0]
+		}
+	}
+
+	return c.obtain(ctx, domains, keyType, replaces)
+}
+
+// RenewalInfo 获取证书的 ARI 续签信息，CA 不支持 ARI 时返回的错误包含 acme.ErrUnsupported
+func (c *Client) RenewalInfo(ctx context.Context, certPEM []byte) (RenewalInfo, error) {
+	certs, err := ParseCertificates(certPEM)
+	if err != nil {
+		return RenewalInfo{}, err
+	}
+
+	return c.zClient.GetRenewalInfo(ctx, certs[0])
 }
 
 // GetDNSRecords 获取 DNS 解析（手动设置）
@@ -0,0 +1,115 @@
+package acme
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"net"
+)
+
+// CSRSubject CSR 主题信息，OV/EV 证书通常需要填写组织信息
+type CSRSubject struct {
+	Organization       string
+	OrganizationalUnit string
+	Country            string
+	Province           string
+	Locality           string
+}
+
+// GenerateCSR 生成私钥和 CSR，第一个域名作为通用名称，返回 CSR 和私钥
+func GenerateCSR(domains []string, keyType KeyType, subject CSRSubject) ([]byte, []byte, error) {
+	if len(domains) == 0 {
+		return nil, nil, errors.New("至少需要一个域名")
+	}
+	key, err := generatePrivateKey(keyType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: domains[0]},
+	}
+	if subject.Organization != "" {
+		template.Subject.Organization = []string{subject.Organization}
+	}
+	if subject.OrganizationalUnit != "" {
+		template.Subject.OrganizationalUnit = []string{subject.OrganizationalUnit}
+	}
+	if subject.Country != "" {
+		template.Subject.Country = []string{subject.Country}
+	}
+	if subject.Province != "" {
+		template.Subject.Province = []string{subject.Province}
+	}
+	if subject.Locality != "" {
+		template.Subject.Locality = []string{subject.Locality}
+	}
+	for _, domain := range domains {
+		if ip := net.ParseIP(domain); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, domain)
+		}
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err := EncodePrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}), keyPEM, nil
+}
+
+// MatchCertificate 检查 CA 签发的证书是否与 CSR 的私钥匹配，返回按签发顺序整理的证书链
+// certPEM 和 chainPEM 可以按任意顺序包含站点证书和中间证书，自签名的根证书会被去除
+func MatchCertificate(certPEM, chainPEM, keyPEM []byte) ([]byte, error) {
+	key, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	certs, err := ParseCertificates(append(append([]byte{}, certPEM...), append([]byte("\n"), chainPEM...)...))
+	if err != nil {
+		return nil, err
+	}
+
+	var leaf *x509.Certificate
+	var rest []*x509.Certificate
+	for _, cert := range certs {
+		if leaf == nil && publicKeyEqual(cert.PublicKey, key.Public()) {
+			leaf = cert
+			continue
+		}
+		rest = append(rest, cert)
+	}
+	if leaf == nil {
+		return nil, errors.New("证书与待签发的私钥不匹配")
+	}
+
+	chain := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw})
+	for current := leaf; ; {
+		index := -1
+		for i, cert := range rest {
+			if current.CheckSignatureFrom(cert) == nil {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			break
+		}
+		current = rest[index]
+		rest = append(rest[:index], rest[index+1:]...)
+		if current.CheckSignatureFrom(current) == nil {
+			break
+		}
+		chain = append(chain, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: current.Raw})...)
+	}
+
+	return chain, nil
+}
@@ -0,0 +1,74 @@
+package acme
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CSRTestSuite struct {
+	suite.Suite
+}
+
+func TestCSRTestSuite(t *testing.T) {
+	suite.Run(t, &CSRTestSuite{})
+}
+
+func (s *CSRTestSuite) TestGenerateAndMatch() {
+	csrPEM, keyPEM, err := GenerateCSR([]string{"haozi.dev", "www.haozi.dev"}, KeyEC256, CSRSubject{
+		Organization: "HaoZi Technology Co., Ltd.",
+		Country:      "CN",
+	})
+	s.Require().Nil(err)
+
+	block, _ := pem.Decode(csrPEM)
+	s.Require().NotNil(block)
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	s.Require().Nil(err)
+	s.Nil(csr.CheckSignature())
+	s.Equal("haozi.dev", csr.Subject.CommonName)
+	s.Equal([]string{"HaoZi Technology Co., Ltd."}, csr.Subject.Organization)
+	s.Equal([]string{"haozi.dev", "www.haozi.dev"}, csr.DNSNames)
+
+	// 模拟商业 CA 根据 CSR 签发证书
+	ca, err := GenerateCA("HaoZi", KeyEC256, 1)
+	s.Require().Nil(err)
+	issuer, issuerKey, err := ca.intermediate()
+	s.Require().Nil(err)
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      csr.Subject,
+		DNSNames:     csr.DNSNames,
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}, issuer, csr.PublicKey, issuerKey)
+	s.Require().Nil(err)
+	leaf := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	// 供应商提供的证书链顺序混乱且包含根证书
+	bundle := append(append([]byte{}, ca.RootCert...), ca.IntermediateCert...)
+	chain, err := MatchCertificate(leaf, bundle, keyPEM)
+	s.Require().Nil(err)
+	certs, err := ParseCertificates(chain)
+	s.Require().Nil(err)
+	s.Len(certs, 2)
+	s.Equal(der, certs[0].Raw)
+	s.Nil(certs[0].CheckSignatureFrom(certs[1]))
+
+	// 站点证书包含在证书链中
+	chain, err = MatchCertificate(append(append([]byte{}, ca.IntermediateCert...), leaf...), nil, keyPEM)
+	s.Nil(err)
+	certs, err = ParseCertificates(chain)
+	s.Nil(err)
+	s.Equal(der, certs[0].Raw)
+
+	_, otherKey, err := GenerateCSR([]string{"haozi.dev"}, KeyEC256, CSRSubject{})
+	s.Require().Nil(err)
+	_, err = MatchCertificate(leaf, bundle, otherKey)
+	s.Error(err)
+}
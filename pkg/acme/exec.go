@@ -0,0 +1,166 @@
+package acme
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/miekg/dns"
+)
+
+// execProvider 调用用户提供的脚本管理记录，适用于面板不支持的 DNS 服务商
+// 脚本通过环境变量获取记录信息：
+// ACME_FQDN 记录完整域名，ACME_ZONE 区域，ACME_NAME 相对区域的记录名，ACME_VALUE 记录值，ACME_TTL 记录 TTL
+type execProvider struct {
+	present     string
+	cleanup     string
+	ttl         time.Duration
+	timeout     time.Duration // 等待记录生效的超时时间
+	nameservers []string      // 用于检查记录是否生效的 DNS 服务器，为空时使用区域的权威服务器
+}
+
+func newExecProvider(param DNSParam) (*execProvider, error) {
+	if len(strings.TrimSpace(param.PresentScript)) == 0 {
+		return nil, errors.New("未配置添加记录脚本")
+	}
+
+	provider := &execProvider{
+		present: param.PresentScript,
+		cleanup: param.CleanupScript,
+		ttl:     time.Duration(param.TTL) * time.Second,
+		timeout: time.Duration(param.PropagationTimeout) * time.Second,
+	}
+	if provider.ttl == 0 {
+		provider.ttl = 120 * time.Second
+	}
+	if provider.timeout == 0 {
+		provider.timeout = 5 * time.Minute
+	}
+
+	return provider, nil
+}
+
+func (p *execProvider) AppendRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	for i := range records {
+		records[i].TTL = p.ttl
+		if err := p.run(ctx, p.present, zone, records[i]); err != nil {
+			return nil, fmt.Errorf("添加记录脚本执行失败: %w", err)
+		}
+	}
+	for _, record := range records {
+		if err := p.wait(ctx, zone, record); err != nil {
+			return nil, err
+		}
+	}
+
+	return records, nil
+}
+
+func (p *execProvider) DeleteRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	if len(strings.TrimSpace(p.cleanup)) == 0 {
+		return records, nil
+	}
+	for _, record := range records {
+		if err := p.run(ctx, p.cleanup, zone, record); err != nil {
+			return nil, fmt.Errorf("删除记录脚本执行失败: %w", err)
+		}
+	}
+
+	return records, nil
+}
+
+// run 执行脚本，失败时返回退出码和输出
+func (p *execProvider) run(ctx context.Context, script, zone string, record libdns.Record) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "bash", "-c", script)
+	cmd.Env = append(os.Environ(),
+		"ACME_FQDN="+libdns.AbsoluteName(record.Name, dns.Fqdn(zone)),
+		"ACME_ZONE="+dns.Fqdn(zone),
+		"ACME_NAME="+record.Name,
+		"ACME_VALUE="+record.Value,
+		"ACME_TTL="+strconv.Itoa(int(record.TTL/time.Second)),
+	)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("退出码 %d，输出: %s", exitErr.ExitCode(), strings.TrimSpace(output.String()))
+		}
+		return fmt.Errorf("%w，输出: %s", err, strings.TrimSpace(output.String()))
+	}
+
+	return nil
+}
+
+// wait 等待所有 DNS 服务器上的记录生效
+func (p *execProvider) wait(ctx context.Context, zone string, record libdns.Record) error {
+	fqdn := libdns.AbsoluteName(record.Name, dns.Fqdn(zone))
+	nameservers := p.nameservers
+	if len(nameservers) == 0 {
+		ns, err := net.LookupNS(strings.TrimSuffix(dns.Fqdn(zone), "."))
+		if err != nil || len(ns) == 0 {
+			return fmt.Errorf("获取区域 %q 的权威 DNS 服务器失败: %v", zone, err)
+		}
+		for _, item := range ns {
+			nameservers = append(nameservers, net.JoinHostPort(strings.TrimSuffix(item.Host, "."), "53"))
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	pending := nameservers
+	for {
+		var remain []string
+		for _, server := range pending {
+			if !txtExists(ctx, server, fqdn, record.Value) {
+				remain = append(remain, server)
+			}
+		}
+		if len(remain) == 0 {
+			return nil
+		}
+		pending = remain
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("等待记录 %q 生效超时，未生效的 DNS 服务器: %s", fqdn, strings.Join(pending, ", "))
+		case <-ticker.C:
+		}
+	}
+}
+
+// txtExists 检查 DNS 服务器上是否存在指定的 TXT 记录
+func txtExists(ctx context.Context, server, fqdn, value string) bool {
+	msg := new(dns.Msg)
+	msg.SetQuestion(fqdn, dns.TypeTXT)
+	msg.RecursionDesired = false
+
+	client := &dns.Client{Timeout: 5 * time.Second}
+	reply, _, err := client.ExchangeContext(ctx, msg, server)
+	if err != nil || reply.Rcode != dns.RcodeSuccess {
+		return false
+	}
+	for _, rr := range reply.Answer {
+		if txt, ok := rr.(*dns.TXT); ok && strings.Join(txt.Txt, "") == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,98 @@
+package acme
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExecTestSuite struct {
+	suite.Suite
+	server  *dns.Server
+	address string
+	dir     string
+}
+
+func TestExecTestSuite(t *testing.T) {
+	suite.Run(t, &ExecTestSuite{})
+}
+
+// SetupSuite 启动一个本地 DNS 服务器，返回脚本写入文件的 TXT 记录
+func (s *ExecTestSuite) SetupSuite() {
+	s.dir = s.T().TempDir()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	s.address = conn.LocalAddr().String()
+
+	s.server = &dns.Server{
+		PacketConn: conn,
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+			reply := new(dns.Msg)
+			reply.SetReply(req)
+			if value, err := os.ReadFile(filepath.Join(s.dir, req.Question[0].Name)); err == nil {
+				reply.Answer = append(reply.Answer, &dns.TXT{
+					Hdr: dns.RR_Header{Name: req.Question[0].Name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: 60},
+					Txt: []string{strings.TrimSpace(string(value))},
+				})
+			}
+			_ = w.WriteMsg(reply)
+		}),
+	}
+	go func() {
+		_ = s.server.ActivateAndServe()
+	}()
+}
+
+func (s *ExecTestSuite) TearDownSuite() {
+	_ = s.server.Shutdown()
+}
+
+func (s *ExecTestSuite) provider(present, cleanup string) *execProvider {
+	provider, err := newExecProvider(DNSParam{PresentScript: present, CleanupScript: cleanup, TTL: 60, PropagationTimeout: 3})
+	s.Require().NoError(err)
+	provider.nameservers = []string{s.address}
+
+	return provider
+}
+
+func (s *ExecTestSuite) TestPresentAndCleanup() {
+	provider := s.provider(
+		`echo "$ACME_VALUE" > "`+s.dir+`/$ACME_FQDN"; echo "$ACME_ZONE $ACME_NAME $ACME_TTL" > "`+s.dir+`/env"`,
+		`rm -f "`+s.dir+`/$ACME_FQDN"`,
+	)
+	records := []libdns.Record{{Type: "TXT", Name: "_acme-challenge", Value: "token"}}
+
+	_, err := provider.AppendRecords(context.Background(), "example.com.", records)
+	s.NoError(err)
+	env, err := os.ReadFile(filepath.Join(s.dir, "env"))
+	s.NoError(err)
+	s.Equal("example.com. _acme-challenge 60\n", string(env))
+
+	_, err = provider.DeleteRecords(context.Background(), "example.com.", records)
+	s.NoError(err)
+	s.NoFileExists(filepath.Join(s.dir, "_acme-challenge.example.com."))
+}
+
+func (s *ExecTestSuite) TestScriptFailed() {
+	provider := s.provider(`echo "api error"; exit 3`, "")
+	_, err := provider.AppendRecords(context.Background(), "example.com.", []libdns.Record{{Type: "TXT", Name: "_acme-challenge", Value: "token"}})
+	s.Error(err)
+	s.Contains(err.Error(), "退出码 3")
+	s.Contains(err.Error(), "api error")
+}
+
+func (s *ExecTestSuite) TestPropagationTimeout() {
+	provider := s.provider(`true`, "")
+	provider.timeout = time.Second
+	_, err := provider.AppendRecords(context.Background(), "example.org.", []libdns.Record{{Type: "TXT", Name: "_acme-challenge", Value: "token"}})
+	s.Error(err)
+	s.Contains(err.Error(), "超时")
+}
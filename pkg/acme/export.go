@@ -0,0 +1,102 @@
+package acme
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// ExportFormat 证书导出格式
+type ExportFormat string
+
+const (
+	ExportFullchain    ExportFormat = "fullchain"    // 完整证书链 PEM
+	ExportLeaf         ExportFormat = "leaf"         // 站点证书 PEM
+	ExportIntermediate ExportFormat = "intermediate" // 中间证书 PEM
+	ExportKey          ExportFormat = "key"          // 私钥 PEM
+	ExportDER          ExportFormat = "der"          // 站点证书 DER
+	ExportPFX          ExportFormat = "pfx"          // PKCS#12，使用 AES 加密
+	ExportPFXLegacy    ExportFormat = "pfx_legacy"   // PKCS#12，使用 3DES 加密，兼容 Windows Server 2016 之前的系统和旧版 Java
+	ExportBundle       ExportFormat = "bundle"       // 包含全部 PEM 文件的 zip 压缩包
+)
+
+// Exported 导出的证书文件
+type Exported struct {
+	Data        []byte
+	ContentType string
+	Extension   string
+}
+
+// Export 按格式导出证书，password 仅用于 PFX
+func Export(chainPEM, keyPEM []byte, format ExportFormat, password string) (Exported, error) {
+	leaf, err := LeafPEM(chainPEM)
+	if err != nil {
+		return Exported{}, err
+	}
+	intermediate, err := IntermediatePEM(chainPEM)
+	if err != nil {
+		return Exported{}, err
+	}
+
+	switch format {
+	case ExportFullchain:
+		return Exported{Data: chainPEM, ContentType: "application/x-pem-file", Extension: ".fullchain.pem"}, nil
+	case ExportLeaf:
+		return Exported{Data: leaf, ContentType: "application/x-pem-file", Extension: ".crt"}, nil
+	case ExportIntermediate:
+		if len(intermediate) == 0 {
+			return Exported{}, errors.New("证书链中没有中间证书")
+		}
+		return Exported{Data: intermediate, ContentType: "application/x-pem-file", Extension: ".chain.pem"}, nil
+	case ExportDER:
+		block, _ := pem.Decode(leaf)
+		return Exported{Data: block.Bytes, ContentType: "application/pkix-cert", Extension: ".der"}, nil
+	}
+
+	if len(keyPEM) == 0 {
+		return Exported{}, errors.New("证书没有私钥")
+	}
+	switch format {
+	case ExportKey:
+		return Exported{Data: keyPEM, ContentType: "application/x-pem-file", Extension: ".key"}, nil
+	case ExportPFX, ExportPFXLegacy:
+		encode := EncodePFX
+		if format == ExportPFXLegacy {
+			encode = EncodeLegacyPFX
+		}
+		pfx, err := encode(chainPEM, keyPEM, password)
+		if err != nil {
+			return Exported{}, fmt.Errorf("生成 PFX 失败: %w", err)
+		}
+		return Exported{Data: pfx, ContentType: "application/x-pkcs12", Extension: ".pfx"}, nil
+	case ExportBundle:
+		var buf bytes.Buffer
+		writer := zip.NewWriter(&buf)
+		files := []struct {
+			name string
+			data []byte
+		}{
+			{"cert.pem", leaf},
+			{"chain.pem", intermediate},
+			{"fullchain.pem", chainPEM},
+			{"privkey.pem", keyPEM},
+		}
+		for _, file := range files {
+			w, err := writer.Create(file.name)
+			if err != nil {
+				return Exported{}, err
+			}
+			if _, err = w.Write(file.data); err != nil {
+				return Exported{}, err
+			}
+		}
+		if err = writer.Close(); err != nil {
+			return Exported{}, err
+		}
+		return Exported{Data: buf.Bytes(), ContentType: "application/zip", Extension: ".zip"}, nil
+	}
+
+	return Exported{}, fmt.Errorf("未知的导出格式 %s", format)
+}
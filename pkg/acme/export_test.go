@@ -0,0 +1,54 @@
+package acme
+
+import (
+	"archive/zip"
+	"bytes"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func (s *SSLTestSuite) TestExport() {
+	ca, err := GenerateCA("HaoZi", KeyEC256, 1)
+	s.Require().Nil(err)
+	chain, key, _, err := ca.Issue(IssueRequest{
+		Domains: []string{"haozi.dev"},
+		Usage:   UsageServer,
+		Days:    1,
+		KeyType: KeyEC256,
+	})
+	s.Require().Nil(err)
+	certs, err := ParseCertificates(chain)
+	s.Require().Nil(err)
+
+	exported, err := Export(chain, key, ExportDER, "")
+	s.Nil(err)
+	s.Equal(certs[0].Raw, exported.Data)
+
+	exported, err = Export(chain, key, ExportIntermediate, "")
+	s.Nil(err)
+	s.Equal(ca.IntermediateCert, exported.Data)
+
+	for _, format := range []ExportFormat{ExportPFX, ExportPFXLegacy} {
+		exported, err = Export(chain, key, format, "secret")
+		s.Nil(err)
+		_, leaf, intermediates, err := pkcs12.DecodeChain(exported.Data, "secret")
+		s.Nil(err, format)
+		s.Equal(certs[0].Raw, leaf.Raw)
+		s.Len(intermediates, 1)
+	}
+
+	exported, err = Export(chain, key, ExportBundle, "")
+	s.Nil(err)
+	reader, err := zip.NewReader(bytes.NewReader(exported.Data), int64(len(exported.Data)))
+	s.Require().Nil(err)
+	var names []string
+	for _, file := range reader.File {
+		names = append(names, file.Name)
+	}
+	s.Equal([]string{"cert.pem", "chain.pem", "fullchain.pem", "privkey.pem"}, names)
+
+	_, err = Export(chain, nil, ExportPFX, "")
+	s.Error(err)
+	_, err = Export(chain, key, ExportFormat("jks"), "")
+	s.Error(err)
+}
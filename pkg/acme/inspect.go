@@ -0,0 +1,265 @@
+package acme
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// Inspection 证书检查结果
+type Inspection struct {
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	Serial       string    `json:"serial"`
+	NotBefore    time.Time `json:"not_before"`
+	NotAfter     time.Time `json:"not_after"`
+	DaysLeft     int       `json:"days_left"`
+	Expired      bool      `json:"expired"`
+	DNSNames     []string  `json:"dns_names"`
+	KeyAlgorithm string    `json:"key_algorithm"`
+	KeySize      int       `json:"key_size"`
+	KeyMatch     bool      `json:"key_match"` // 私钥与证书是否匹配
+
+	Chain         []ChainCert `json:"chain"`
+	ChainOrdered  bool        `json:"chain_ordered"`  // 证书链中每张证书都由下一张签发
+	ChainComplete bool        `json:"chain_complete"` // 证书链可以验证到受信任的根证书
+	ChainError    string      `json:"chain_error"`
+
+	UncoveredDomains []string `json:"uncovered_domains"` // 证书未覆盖的网站域名
+	ExtraNames       []string `json:"extra_names"`       // 证书中不属于网站的域名
+
+	OCSP OCSPStatus `json:"ocsp"`
+}
+
+// ChainCert 证书链中的证书
+type ChainCert struct {
+	Subject  string    `json:"subject"`
+	Issuer   string    `json:"issuer"`
+	NotAfter time.Time `json:"not_after"`
+	IsCA     bool      `json:"is_ca"`
+}
+
+// OCSPStatus OCSP 查询结果
+type OCSPStatus struct {
+	Server     string     `json:"server"`
+	Status     string     `json:"status"` // good, revoked, unknown
+	RevokedAt  *time.Time `json:"revoked_at"`
+	ThisUpdate *time.Time `json:"this_update"`
+	NextUpdate *time.Time `json:"next_update"`
+	Error      string     `json:"error"`
+}
+
+// Inspect 检查证书链、私钥和域名覆盖情况，并查询 OCSP 状态
+// domains 为使用证书的域名，roots 为额外信任的根证书（如私有 CA），均可为空
+func Inspect(ctx context.Context, certPEM, keyPEM []byte, domains []string, roots []byte) (Inspection, error) {
+	certs, err := ParseCertificates(certPEM)
+	if err != nil {
+		return Inspection{}, err
+	}
+	leaf := certs[0]
+
+	result := Inspection{
+		Subject:          leaf.Subject.String(),
+		Issuer:           leaf.Issuer.String(),
+		Serial:           leaf.SerialNumber.Text(16),
+		NotBefore:        leaf.NotBefore,
+		NotAfter:         leaf.NotAfter,
+		DaysLeft:         int(time.Until(leaf.NotAfter).Hours() / 24),
+		Expired:          time.Now().After(leaf.NotAfter),
+		DNSNames:         leaf.DNSNames,
+		UncoveredDomains: []string{},
+		ExtraNames:       []string{},
+	}
+	result.KeyAlgorithm, result.KeySize = keyInfo(leaf.PublicKey)
+	if len(keyPEM) > 0 {
+		if key, err := parsePrivateKey(keyPEM); err == nil {
+			result.KeyMatch = publicKeyEqual(leaf.PublicKey, key.Public())
+		}
+	}
+
+	result.ChainOrdered = true
+	for i, cert := range certs {
+		result.Chain = append(result.Chain, ChainCert{
+			Subject:  cert.Subject.String(),
+			Issuer:   cert.Issuer.String(),
+			NotAfter: cert.NotAfter,
+			IsCA:     cert.IsCA,
+		})
+		if i+1 < len(certs) && cert.CheckSignatureFrom(certs[i+1]) != nil {
+			result.ChainOrdered = false
+		}
+	}
+	if err = verifyChain(certs, roots); err != nil {
+		result.ChainError = err.Error()
+	} else {
+		result.ChainComplete = true
+	}
+
+	for _, domain := range domains {
+		if leaf.VerifyHostname(domain) != nil {
+			result.UncoveredDomains = append(result.UncoveredDomains, domain)
+		}
+	}
+	if len(domains) > 0 {
+		for _, name := range leaf.DNSNames {
+			if !nameUsed(name, domains) {
+				result.ExtraNames = append(result.ExtraNames, name)
+			}
+		}
+	}
+
+	var issuer *x509.Certificate
+	if len(certs) > 1 && leaf.CheckSignatureFrom(certs[1]) == nil {
+		issuer = certs[1]
+	}
+	result.OCSP = QueryOCSP(ctx, leaf, issuer)
+
+	return result, nil
+}
+
+// QueryOCSP 查询证书的 OCSP 状态，issuer 为空时无法查询
+func QueryOCSP(ctx context.Context, leaf, issuer *x509.Certificate) OCSPStatus {
+	var status OCSPStatus
+	if len(leaf.OCSPServer) == 0 {
+		status.Error = "证书没有 OCSP 服务器"
+		return status
+	}
+	status.Server = leaf.OCSPServer[0]
+	if issuer == nil {
+		status.Error = "证书链中缺少签发者证书，无法查询 OCSP"
+		return status
+	}
+
+	response, err := queryOCSP(ctx, status.Server, leaf, issuer)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+
+	switch response.Status {
+	case ocsp.Good:
+		status.Status = "good"
+	case ocsp.Revoked:
+		status.Status = "revoked"
+		status.RevokedAt = &response.RevokedAt
+	default:
+		status.Status = "unknown"
+	}
+	status.ThisUpdate = &response.ThisUpdate
+	if !response.NextUpdate.IsZero() {
+		status.NextUpdate = &response.NextUpdate
+	}
+
+	return status
+}
+
+func queryOCSP(ctx context.Context, server string, leaf, issuer *x509.Certificate) (*ocsp.Response, error) {
+	request, err := ocsp.CreateRequest(leaf, issuer, &ocsp.RequestOptions{Hash: crypto.SHA1})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server, bytes.NewReader(request))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/ocsp-request")
+	req.Header.Set("Accept", "application/ocsp-response")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("OCSP 查询失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OCSP 服务器返回 %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	return ocsp.ParseResponseForCert(body, leaf, issuer)
+}
+
+func verifyChain(certs []*x509.Certificate, roots []byte) error {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if len(roots) > 0 {
+		pool.AppendCertsFromPEM(roots)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err = certs[0].Verify(x509.VerifyOptions{
+		Roots:         pool,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err == nil {
+		return nil
+	}
+
+	var unknown x509.UnknownAuthorityError
+	if errors.As(err, &unknown) {
+		return errors.New("证书链不完整或根证书不受信任")
+	}
+	var invalid x509.CertificateInvalidError
+	if errors.As(err, &invalid) && invalid.Reason == x509.Expired {
+		return errors.New("证书链中存在已过期或尚未生效的证书")
+	}
+
+	return err
+}
+
+func keyInfo(key any) (string, int) {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return "RSA", key.N.BitLen()
+	case *ecdsa.PublicKey:
+		return "ECDSA", key.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return "Ed25519", 256
+	}
+
+	return "Unknown", 0
+}
+
+func publicKeyEqual(a, b crypto.PublicKey) bool {
+	key, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && key.Equal(b)
+}
+
+// nameUsed 判断证书中的名称是否被网站域名使用
+func nameUsed(name string, domains []string) bool {
+	for _, domain := range domains {
+		if strings.EqualFold(name, domain) {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(name, "*."); ok {
+			if _, parent, found := strings.Cut(domain, "."); found && strings.EqualFold(parent, suffix) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,89 @@
+package acme
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+func (s *CATestSuite) TestInspect() {
+	ca, err := GenerateCA("HaoZi", KeyEC256, 3650)
+	s.Require().Nil(err)
+	chain, key, _, err := ca.Issue(IssueRequest{
+		Domains: []string{"haozi.dev", "*.haozi.dev"},
+		Usage:   UsageServer,
+		Days:    30,
+		KeyType: KeyEC384,
+	})
+	s.Require().Nil(err)
+
+	result, err := Inspect(context.Background(), chain, key, []string{"www.haozi.dev", "haozi.net"}, ca.RootCert)
+	s.Require().Nil(err)
+	s.True(result.KeyMatch)
+	s.Equal("ECDSA", result.KeyAlgorithm)
+	s.Equal(384, result.KeySize)
+	s.Len(result.Chain, 2)
+	s.True(result.ChainOrdered)
+	s.True(result.ChainComplete)
+	s.Equal(29, result.DaysLeft)
+	s.Equal([]string{"haozi.net"}, result.UncoveredDomains)
+	s.Equal([]string{"haozi.dev"}, result.ExtraNames)
+	s.NotEmpty(result.OCSP.Error)
+
+	// 顺序错误、缺少根证书信任、私钥不匹配
+	certs, err := ParseCertificates(chain)
+	s.Require().Nil(err)
+	_, otherKey, _, err := ca.Issue(IssueRequest{Domains: []string{"haozi.dev"}, Usage: UsageServer, Days: 1, KeyType: KeyEC256})
+	s.Require().Nil(err)
+	reversed := append(append([]byte{}, ca.IntermediateCert...), chain[:len(chain)-len(ca.IntermediateCert)]...)
+	result, err = Inspect(context.Background(), reversed, otherKey, nil, nil)
+	s.Require().Nil(err)
+	s.False(result.KeyMatch)
+	s.False(result.ChainOrdered)
+	s.False(result.ChainComplete)
+	s.NotEmpty(result.ChainError)
+	s.Equal(certs[1].Subject.String(), result.Subject)
+}
+
+func (s *CATestSuite) TestQueryOCSP() {
+	ca, err := GenerateCA("HaoZi", KeyEC256, 3650)
+	s.Require().Nil(err)
+	chain, _, _, err := ca.Issue(IssueRequest{Domains: []string{"haozi.dev"}, Usage: UsageServer, Days: 30, KeyType: KeyEC256})
+	s.Require().Nil(err)
+	certs, err := ParseCertificates(chain)
+	s.Require().Nil(err)
+	issuer, issuerKey, err := ca.intermediate()
+	s.Require().Nil(err)
+
+	revokedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		request, err := ocsp.ParseRequest(body)
+		s.Nil(err)
+		response, err := ocsp.CreateResponse(issuer, issuer, ocsp.Response{
+			Status:       ocsp.Revoked,
+			SerialNumber: request.SerialNumber,
+			ThisUpdate:   time.Now().Truncate(time.Second),
+			NextUpdate:   time.Now().Add(time.Hour).Truncate(time.Second),
+			RevokedAt:    revokedAt,
+		}, issuerKey)
+		s.Nil(err)
+		_, _ = w.Write(response)
+	}))
+	defer server.Close()
+
+	certs[0].OCSPServer = []string{server.URL}
+	status := QueryOCSP(context.Background(), certs[0], certs[1])
+	s.Empty(status.Error)
+	s.Equal("revoked", status.Status)
+	s.Require().NotNil(status.RevokedAt)
+	s.True(revokedAt.Equal(*status.RevokedAt))
+
+	status = QueryOCSP(context.Background(), certs[0], nil)
+	s.Equal("", status.Status)
+	s.NotEmpty(status.Error)
+}
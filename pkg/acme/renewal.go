@@ -0,0 +1,80 @@
+package acme
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/mholt/acmez/v2/acme"
+)
+
+const (
+	renewalCheckInterval = 6 * time.Hour  // CA 未给出 Retry-After 时查询 ARI 的间隔
+	minCheckInterval     = time.Hour      // 查询 ARI 的最小间隔
+	minRetryInterval     = time.Hour      // 续签失败后的首次重试间隔
+	maxRetryInterval     = 24 * time.Hour // 续签失败后的最大重试间隔
+)
+
+// RenewalInfo ARI 续签信息
+type RenewalInfo = acme.RenewalInfo
+
+// RenewalTime 计算证书的续签时间
+// CA 提供了 ARI 建议窗口时在窗口内随机选择，否则在有效期的三分之二处续签，并提前一段随机时间避免集中续签
+func RenewalTime(certPEM []byte, ari *RenewalInfo) (time.Time, error) {
+	if ari != nil && ari.HasWindow() {
+		if !ari.SelectedTime.IsZero() {
+			return ari.SelectedTime, nil
+		}
+		window := ari.SuggestedWindow.End.Sub(ari.SuggestedWindow.Start)
+		return ari.SuggestedWindow.Start.Add(jitter(window)), nil
+	}
+
+	certs, err := ParseCertificates(certPEM)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	lifetime := certs[0].NotAfter.Sub(certs[0].NotBefore)
+	return certs[0].NotBefore.Add(lifetime * 2 / 3).Add(-jitter(lifetime / 30)), nil
+}
+
+// InWindow 判断时间是否在 ARI 建议窗口内
+func InWindow(t time.Time, ari RenewalInfo) bool {
+	return ari.HasWindow() && !t.Before(ari.SuggestedWindow.Start) && !t.After(ari.SuggestedWindow.End)
+}
+
+// RenewalCheckTime 计算下次查询 ARI 的时间
+func RenewalCheckTime(ari *RenewalInfo) time.Time {
+	now := time.Now()
+	if ari != nil && ari.RetryAfter != nil {
+		return maxTime(*ari.RetryAfter, now.Add(minCheckInterval))
+	}
+
+	return now.Add(renewalCheckInterval)
+}
+
+// RetryBackoff 计算第 failures 次续签失败后的重试间隔，按指数增长并加入随机抖动
+func RetryBackoff(failures uint) time.Duration {
+	backoff := minRetryInterval
+	for i := uint(1); i < failures && backoff < maxRetryInterval; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, maxRetryInterval)
+
+	return backoff + jitter(backoff/10)
+}
+
+func jitter(n time.Duration) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(n)))
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
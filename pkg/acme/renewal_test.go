@@ -0,0 +1,75 @@
+package acme
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mholt/acmez/v2/acme"
+	"github.com/stretchr/testify/suite"
+)
+
+type RenewalTestSuite struct {
+	suite.Suite
+}
+
+func TestRenewalTestSuite(t *testing.T) {
+	suite.Run(t, &RenewalTestSuite{})
+}
+
+func (s *RenewalTestSuite) TestRenewalTime() {
+	chain, _, err := GenerateSelfSignedSSL([]string{"haozi.dev"})
+	s.Require().Nil(err)
+	certs, err := ParseCertificates(chain)
+	s.Require().Nil(err)
+
+	// 没有 ARI 时在有效期的三分之二处续签
+	lifetime := certs[0].NotAfter.Sub(certs[0].NotBefore)
+	at, err := RenewalTime(chain, nil)
+	s.Nil(err)
+	target := certs[0].NotBefore.Add(lifetime * 2 / 3)
+	s.False(at.After(target))
+	s.True(at.After(target.Add(-lifetime / 30)))
+
+	// 有 ARI 时使用建议窗口
+	var ari acme.RenewalInfo
+	ari.SuggestedWindow.Start = time.Now().Add(time.Hour)
+	ari.SuggestedWindow.End = time.Now().Add(2 * time.Hour)
+	at, err = RenewalTime(chain, &ari)
+	s.Nil(err)
+	s.True(InWindow(at, ari))
+
+	ari.SelectedTime = ari.SuggestedWindow.Start.Add(time.Minute)
+	at, err = RenewalTime(nil, &ari)
+	s.Nil(err)
+	s.Equal(ari.SelectedTime, at)
+
+	_, err = RenewalTime([]byte("invalid"), nil)
+	s.Error(err)
+}
+
+func (s *RenewalTestSuite) TestRenewalCheckTime() {
+	s.WithinDuration(time.Now().Add(renewalCheckInterval), RenewalCheckTime(nil), time.Minute)
+
+	retryAfter := time.Now().Add(3 * time.Hour)
+	s.Equal(retryAfter, RenewalCheckTime(&acme.RenewalInfo{RetryAfter: &retryAfter}))
+
+	// 过短的 Retry-After 按最小间隔处理
+	retryAfter = time.Now()
+	s.WithinDuration(time.Now().Add(minCheckInterval), RenewalCheckTime(&acme.RenewalInfo{RetryAfter: &retryAfter}), time.Minute)
+}
+
+func (s *RenewalTestSuite) TestRetryBackoff() {
+	cases := map[uint]time.Duration{
+		0:  time.Hour,
+		1:  time.Hour,
+		2:  2 * time.Hour,
+		3:  4 * time.Hour,
+		6:  24 * time.Hour,
+		20: 24 * time.Hour,
+	}
+	for failures, base := range cases {
+		backoff := RetryBackoff(failures)
+		s.GreaterOrEqual(backoff, base, failures)
+		s.Less(backoff, base+base/10+1, failures)
+	}
+}
@@ -0,0 +1,129 @@
+package acme
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/miekg/dns"
+)
+
+// rfc2136Provider 通过 RFC 2136 动态更新管理自建 DNS 服务器（BIND、Knot 等）的记录
+type rfc2136Provider struct {
+	server    string // host:port
+	keyName   string
+	algorithm string
+	secret    string
+}
+
+func newRFC2136Provider(param DNSParam) (*rfc2136Provider, error) {
+	if len(param.Server) == 0 {
+		return nil, fmt.Errorf("未配置 DNS 服务器地址")
+	}
+	port := param.Port
+	if port == 0 {
+		port = 53
+	}
+
+	provider := &rfc2136Provider{
+		server: net.JoinHostPort(param.Server, strconv.Itoa(int(port))),
+	}
+	if len(param.TSIGKey) > 0 {
+		algorithm, ok := tsigAlgorithms[param.TSIGAlgorithm]
+		if !ok {
+			return nil, fmt.Errorf("不支持的 TSIG 算法 %q", param.TSIGAlgorithm)
+		}
+		provider.keyName = dns.Fqdn(param.TSIGKey)
+		provider.algorithm = algorithm
+		provider.secret = param.TSIGSecret
+	}
+
+	return provider, nil
+}
+
+// tsigAlgorithms 支持的 TSIG 算法
+var tsigAlgorithms = map[string]string{
+	"hmac-md5":    dns.HmacMD5,
+	"hmac-sha1":   dns.HmacSHA1,
+	"hmac-sha224": dns.HmacSHA224,
+	"hmac-sha256": dns.HmacSHA256,
+	"hmac-sha384": dns.HmacSHA384,
+	"hmac-sha512": dns.HmacSHA512,
+}
+
+func (p *rfc2136Provider) AppendRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	msg := new(dns.Msg)
+	msg.SetUpdate(dns.Fqdn(zone))
+	rrs, err := p.toRRs(zone, records)
+	if err != nil {
+		return nil, err
+	}
+	msg.Insert(rrs)
+
+	if err = p.exchange(ctx, msg); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+func (p *rfc2136Provider) DeleteRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	msg := new(dns.Msg)
+	msg.SetUpdate(dns.Fqdn(zone))
+	rrs, err := p.toRRs(zone, records)
+	if err != nil {
+		return nil, err
+	}
+	msg.Remove(rrs)
+
+	if err = p.exchange(ctx, msg); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+func (p *rfc2136Provider) toRRs(zone string, records []libdns.Record) ([]dns.RR, error) {
+	rrs := make([]dns.RR, 0, len(records))
+	for _, record := range records {
+		if record.Type != "TXT" {
+			return nil, fmt.Errorf("不支持的记录类型 %q", record.Type)
+		}
+		ttl := uint32(record.TTL / time.Second)
+		if ttl == 0 {
+			ttl = 60
+		}
+		rrs = append(rrs, &dns.TXT{
+			Hdr: dns.RR_Header{
+				Name:   libdns.AbsoluteName(record.Name, dns.Fqdn(zone)),
+				Rrtype: dns.TypeTXT,
+				Class:  dns.ClassINET,
+				Ttl:    ttl,
+			},
+			Txt: []string{record.Value},
+		})
+	}
+
+	return rrs, nil
+}
+
+func (p *rfc2136Provider) exchange(ctx context.Context, msg *dns.Msg) error {
+	client := &dns.Client{Net: "tcp", Timeout: 30 * time.Second}
+	if len(p.keyName) > 0 {
+		client.TsigSecret = map[string]string{p.keyName: p.secret}
+		msg.SetTsig(p.keyName, p.algorithm, 300, time.Now().Unix())
+	}
+
+	reply, _, err := client.ExchangeContext(ctx, msg, p.server)
+	if err != nil {
+		return fmt.Errorf("发送动态更新到 %s 失败: %w", p.server, err)
+	}
+	if reply.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("DNS 服务器拒绝了动态更新: %s", dns.RcodeToString[reply.Rcode])
+	}
+
+	return nil
+}
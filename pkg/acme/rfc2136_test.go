@@ -0,0 +1,128 @@
+package acme
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/libdns/libdns"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/suite"
+)
+
+type RFC2136TestSuite struct {
+	suite.Suite
+	server  *dns.Server
+	address string
+	mu      sync.Mutex
+	records map[string][]string
+}
+
+func TestRFC2136TestSuite(t *testing.T) {
+	suite.Run(t, &RFC2136TestSuite{})
+}
+
+// SetupSuite 启动一个只接受 TSIG 签名更新的本地 DNS 服务器
+func (s *RFC2136TestSuite) SetupSuite() {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	s.address = listener.Addr().String()
+	s.records = make(map[string][]string)
+
+	mux := dns.NewServeMux()
+	mux.HandleFunc("example.internal.", func(w dns.ResponseWriter, req *dns.Msg) {
+		reply := new(dns.Msg)
+		reply.SetReply(req)
+		if req.IsTsig() == nil || w.TsigStatus() != nil {
+			reply.Rcode = dns.RcodeRefused
+			_ = w.WriteMsg(reply)
+			return
+		}
+
+		s.mu.Lock()
+		for _, rr := range req.Ns {
+			txt, ok := rr.(*dns.TXT)
+			if !ok {
+				continue
+			}
+			if rr.Header().Class == dns.ClassNONE {
+				s.records[txt.Hdr.Name] = nil
+			} else {
+				s.records[txt.Hdr.Name] = append(s.records[txt.Hdr.Name], txt.Txt...)
+			}
+		}
+		s.mu.Unlock()
+
+		reply.SetTsig(req.IsTsig().Hdr.Name, dns.HmacSHA256, 300, int64(req.IsTsig().TimeSigned))
+		_ = w.WriteMsg(reply)
+	})
+
+	s.server = &dns.Server{
+		Listener:   listener,
+		Handler:    mux,
+		TsigSecret: map[string]string{"acme.": "c2VjcmV0LXNlY3JldC1zZWNyZXQ="},
+		// 默认只接受查询和通知，需要允许动态更新
+		MsgAcceptFunc: func(dns.Header) dns.MsgAcceptAction {
+			return dns.MsgAccept
+		},
+	}
+	go func() {
+		_ = s.server.ActivateAndServe()
+	}()
+}
+
+func (s *RFC2136TestSuite) TearDownSuite() {
+	_ = s.server.Shutdown()
+}
+
+func (s *RFC2136TestSuite) provider(secret string) DNSProvider {
+	host, port, _ := net.SplitHostPort(s.address)
+	portNumber, _ := strconv.Atoi(port)
+	solver := dnsSolver{dns: RFC2136, param: DNSParam{
+		Server:        host,
+		Port:          uint(portNumber),
+		Zone:          "example.internal",
+		TSIGKey:       "acme",
+		TSIGAlgorithm: "hmac-sha256",
+		TSIGSecret:    secret,
+	}}
+	provider, err := solver.getDNSProvider()
+	s.Require().NoError(err)
+
+	return provider
+}
+
+func (s *RFC2136TestSuite) TestAppendAndDelete() {
+	provider := s.provider("c2VjcmV0LXNlY3JldC1zZWNyZXQ=")
+	records := []libdns.Record{{Type: "TXT", Name: "_acme-challenge.www", Value: "token"}}
+
+	_, err := provider.AppendRecords(context.Background(), "example.internal.", records)
+	s.NoError(err)
+	s.mu.Lock()
+	s.Equal([]string{"token"}, s.records["_acme-challenge.www.example.internal."])
+	s.mu.Unlock()
+
+	_, err = provider.DeleteRecords(context.Background(), "example.internal.", records)
+	s.NoError(err)
+	s.mu.Lock()
+	s.Empty(s.records["_acme-challenge.www.example.internal."])
+	s.mu.Unlock()
+}
+
+func (s *RFC2136TestSuite) TestBadSecret() {
+	provider := s.provider("d3Jvbmc=")
+	_, err := provider.AppendRecords(context.Background(), "example.internal.", []libdns.Record{{Type: "TXT", Name: "_acme-challenge", Value: "token"}})
+	s.Error(err)
+}
+
+func (s *RFC2136TestSuite) TestZone() {
+	solver := dnsSolver{param: DNSParam{Zone: "example.internal."}}
+	zone, err := solver.getZone("_acme-challenge.www.example.internal")
+	s.NoError(err)
+	s.Equal("example.internal", zone)
+
+	_, err = solver.getZone("_acme-challenge.example.com")
+	s.Error(err)
+}
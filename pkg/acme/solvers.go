@@ -3,8 +3,12 @@ package acme
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/libdns/alidns"
@@ -53,6 +57,65 @@ func (s httpSolver) CleanUp(_ context.Context, challenge acme.Challenge) error {
 	return nil
 }
 
+// httpServerSolver 在验证期间临时监听 addr 响应 HTTP 挑战，适用于没有网站目录的场景（如面板自身）
+type httpServerSolver struct {
+	addr   string
+	mu     sync.Mutex
+	server *http.Server
+	tokens map[string]string
+}
+
+func (s *httpServerSolver) Present(_ context.Context, challenge acme.Challenge) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.server == nil {
+		listener, err := net.Listen("tcp", s.addr)
+		if err != nil {
+			return fmt.Errorf("无法监听 %s 响应 HTTP 挑战，请检查端口是否被占用或改用 DNS 验证: %w", s.addr, err)
+		}
+		s.server = &http.Server{Handler: s, ReadHeaderTimeout: 10 * time.Second}
+		go func(server *http.Server) {
+			_ = server.Serve(listener)
+		}(s.server)
+	}
+	if s.tokens == nil {
+		s.tokens = make(map[string]string)
+	}
+	s.tokens[challenge.HTTP01ResourcePath()] = challenge.KeyAuthorization
+
+	return nil
+}
+
+// CleanUp 所有挑战完成后关闭临时监听
+func (s *httpServerSolver) CleanUp(_ context.Context, challenge acme.Challenge) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.tokens, challenge.HTTP01ResourcePath())
+	if len(s.tokens) > 0 || s.server == nil {
+		return nil
+	}
+
+	err := s.server.Close()
+	s.server = nil
+	return err
+}
+
+func (s *httpServerSolver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	keyAuth, ok := s.tokens[r.URL.Path]
+	s.mu.Unlock()
+
+	if !ok || r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	_, _ = w.Write([]byte(keyAuth))
+}
+
 type dnsSolver struct {
 	dns     DnsType
 	param   DNSParam
@@ -66,9 +129,9 @@ func (s dnsSolver) Present(ctx context.Context, challenge acme.Challenge) error
 	if err != nil {
 		return fmt.Errorf("获取 DNS 提供商失败: %w", err)
 	}
-	zone, err := publicsuffix.EffectiveTLDPlusOne(dnsName)
+	zone, err := s.getZone(dnsName)
 	if err != nil {
-		return fmt.Errorf("获取域名 %q 的顶级域失败: %w", dnsName, err)
+		return err
 	}
 
 	rec := libdns.Record{
@@ -85,7 +148,7 @@ func (s dnsSolver) Present(ctx context.Context, challenge acme.Challenge) error
 		return fmt.Errorf("预期添加 1 条记录，但实际添加了 %d 条记录", len(results))
 	}
 
-	s.records = &results
+	*s.records = append(*s.records, results...)
 	return nil
 }
 
@@ -95,15 +158,30 @@ func (s dnsSolver) CleanUp(ctx context.Context, challenge acme.Challenge) error
 	if err != nil {
 		return fmt.Errorf("获取 DNS 提供商失败: %w", err)
 	}
-	zone, err := publicsuffix.EffectiveTLDPlusOne(dnsName)
+	zone, err := s.getZone(dnsName)
 	if err != nil {
-		return fmt.Errorf("获取域名 %q 的顶级域失败: %w", dnsName, err)
+		return err
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer cancel()
 
-	_, err = provider.DeleteRecords(ctx, zone+".", *s.records)
+	// 只删除本次挑战添加的记录
+	name := libdns.RelativeName(dnsName+".", zone+".")
+	var records, remain []libdns.Record
+	for _, record := range *s.records {
+		if record.Name == name && record.Value == challenge.DNS01KeyAuthorization() {
+			records = append(records, record)
+		} else {
+			remain = append(remain, record)
+		}
+	}
+	*s.records = remain
+	if len(records) == 0 {
+		return nil
+	}
+
+	_, err = provider.DeleteRecords(ctx, zone+".", records)
 	if err != nil {
 		return fmt.Errorf("域名 %q 删除临时记录 %q 失败: %w", zone, dnsName, err)
 	}
@@ -111,6 +189,23 @@ func (s dnsSolver) CleanUp(ctx context.Context, challenge acme.Challenge) error
 	return nil
 }
 
+// getZone 获取记录所在的区域，自建 DNS 使用配置的区域
+func (s dnsSolver) getZone(dnsName string) (string, error) {
+	if zone := strings.TrimSuffix(s.param.Zone, "."); len(zone) > 0 {
+		if dnsName != zone && !strings.HasSuffix(dnsName, "."+zone) {
+			return "", fmt.Errorf("域名 %q 不属于区域 %q", dnsName, zone)
+		}
+		return zone, nil
+	}
+
+	zone, err := publicsuffix.EffectiveTLDPlusOne(dnsName)
+	if err != nil {
+		return "", fmt.Errorf("获取域名 %q 的顶级域失败: %w", dnsName, err)
+	}
+
+	return zone, nil
+}
+
 func (s dnsSolver) getDNSProvider() (DNSProvider, error) {
 	var dns DNSProvider
 
@@ -128,6 +223,18 @@ func (s dnsSolver) getDNSProvider() (DNSProvider, error) {
 		dns = &cloudflare.Provider{
 			APIToken: s.param.APIkey,
 		}
+	case RFC2136:
+		provider, err := newRFC2136Provider(s.param)
+		if err != nil {
+			return nil, err
+		}
+		dns = provider
+	case Exec:
+		provider, err := newExecProvider(s.param)
+		if err != nil {
+			return nil, err
+		}
+		dns = provider
 	default:
 		return nil, fmt.Errorf("未知的 DNS 提供商 %q", s.dns)
 	}
@@ -141,6 +248,8 @@ const (
 	DnsPod     DnsType = "dnspod"
 	AliYun     DnsType = "aliyun"
 	CloudFlare DnsType = "cloudflare"
+	RFC2136    DnsType = "rfc2136"
+	Exec       DnsType = "exec"
 )
 
 type DNSParam struct {
@@ -149,6 +258,20 @@ type DNSParam struct {
 	AccessKey string `form:"access_key" json:"access_key"`
 	SecretKey string `form:"secret_key" json:"secret_key"`
 	APIkey    string `form:"api_key" json:"api_key"`
+
+	// RFC 2136
+	Server        string `form:"server" json:"server"`
+	Port          uint   `form:"port" json:"port"`
+	Zone          string `form:"zone" json:"zone"`
+	TSIGKey       string `form:"tsig_key" json:"tsig_key"`
+	TSIGAlgorithm string `form:"tsig_algorithm" json:"tsig_algorithm"`
+	TSIGSecret    string `form:"tsig_secret" json:"tsig_secret"`
+
+	// 脚本
+	PresentScript      string `form:"present_script" json:"present_script"`
+	CleanupScript      string `form:"cleanup_script" json:"cleanup_script"`
+	TTL                uint   `form:"ttl" json:"ttl"`
+	PropagationTimeout uint   `form:"propagation_timeout" json:"propagation_timeout"` // 等待记录生效的秒数
 }
 
 type DNSProvider interface {
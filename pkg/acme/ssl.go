@@ -3,12 +3,17 @@ package acme
 import (
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/hex"
 	"encoding/pem"
+	"errors"
 	"math/big"
 	"net"
 	"time"
+
+	"software.sslmate.com/src/go-pkcs12"
 )
 
 // GenerateSelfSignedSSL 生成自签名证书
@@ -96,3 +101,87 @@ func GenerateSelfSignedSSL(domains []string) ([]byte, []byte, error) {
 
 	return pemBytes, keyBytes, nil
 }
+
+// Fingerprint 获取 PEM 证书链中第一张证书的 SHA256 指纹
+func Fingerprint(certPEM []byte) (string, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return "", errors.New("证书格式错误")
+	}
+
+	sum := sha256.Sum256(block.Bytes)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ParseCertificates 解析 PEM 证书链
+func ParseCertificates(certPEM []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, certPEM = pem.Decode(certPEM)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("证书格式错误")
+	}
+
+	return certs, nil
+}
+
+// LeafPEM 获取证书链中的站点证书
+func LeafPEM(chainPEM []byte) ([]byte, error) {
+	certs, err := ParseCertificates(chainPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certs[0].Raw}), nil
+}
+
+// IntermediatePEM 获取证书链中站点证书之后的中间证书，没有中间证书时为空
+func IntermediatePEM(chainPEM []byte) ([]byte, error) {
+	certs, err := ParseCertificates(chainPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	var intermediate []byte
+	for _, cert := range certs[1:] {
+		intermediate = append(intermediate, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+
+	return intermediate, nil
+}
+
+// EncodePFX 将证书链和私钥编码为 PFX (PKCS#12)
+func EncodePFX(chainPEM, keyPEM []byte, password string) ([]byte, error) {
+	return encodePFX(pkcs12.Modern, chainPEM, keyPEM, password)
+}
+
+// EncodeLegacyPFX 将证书链和私钥编码为使用 3DES 加密的 PFX，兼容旧系统
+func EncodeLegacyPFX(chainPEM, keyPEM []byte, password string) ([]byte, error) {
+	return encodePFX(pkcs12.LegacyDES, chainPEM, keyPEM, password)
+}
+
+func encodePFX(encoder *pkcs12.Encoder, chainPEM, keyPEM []byte, password string) ([]byte, error) {
+	certs, err := ParseCertificates(chainPEM)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	return encoder.Encode(key, certs[0], certs[1:], password)
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/suite"
+	"software.sslmate.com/src/go-pkcs12"
 )
 
 type SSLTestSuite struct {
@@ -20,3 +21,25 @@ func (s *SSLTestSuite) TestGenerateSelfSignedSSL() {
 	s.NotNil(pem)
 	s.NotNil(key)
 }
+
+func (s *SSLTestSuite) TestExportFormats() {
+	chain, key, err := GenerateSelfSignedSSL([]string{"haozi.dev"})
+	s.Require().Nil(err)
+
+	leaf, err := LeafPEM(chain)
+	s.Nil(err)
+	chainFingerprint, err := Fingerprint(chain)
+	s.Nil(err)
+	leafFingerprint, err := Fingerprint(leaf)
+	s.Nil(err)
+	s.Equal(chainFingerprint, leafFingerprint)
+
+	pfx, err := EncodePFX(chain, key, "secret")
+	s.Nil(err)
+	_, cert, _, err := pkcs12.DecodeChain(pfx, "secret")
+	s.Nil(err)
+	s.Equal([]string{"haozi.dev"}, cert.DNSNames)
+
+	_, err = Fingerprint([]byte("invalid"))
+	s.Error(err)
+}
@@ -0,0 +1,89 @@
+package acme
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// stepMessages ACME 流程日志对应的步骤说明
+var stepMessages = map[string]string{
+	"creating order":                                       "创建订单",
+	"authorization already valid":                          "授权已有效，跳过验证",
+	"trying to solve challenge":                            "开始验证",
+	"challenge accepted":                                   "CA 已接受验证请求，等待验证结果",
+	"challenge failed":                                     "验证失败",
+	"authorization finalized":                              "授权完成",
+	"validations succeeded; finalizing order":              "验证全部通过，提交 CSR 完成订单",
+	"successfully downloaded available certificate chains": "下载证书链",
+	"no certificate chains offered by server":              "CA 没有提供证书链",
+	"got renewal info":                                     "获取 ARI 续签信息",
+	"HTTP request failed; retrying":                        "请求失败，正在重试",
+}
+
+// stepFields 输出到步骤日志中的字段
+var stepFields = []string{"identifier", "identifiers", "challenge_type", "status", "window_start", "window_end", "error", "problem"}
+
+// SetLog 将签发过程的每个步骤写入 w
+func (c *Client) SetLog(w io.Writer) {
+	c.zClient.Client.Logger = zap.New(&stepCore{w: w, mu: &sync.Mutex{}})
+}
+
+// stepCore 将 acmez 的日志转换为易读的步骤日志
+type stepCore struct {
+	w      io.Writer
+	mu     *sync.Mutex
+	fields []zapcore.Field
+}
+
+func (c *stepCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+func (c *stepCore) With(fields []zapcore.Field) zapcore.Core {
+	return &stepCore{w: c.w, mu: c.mu, fields: append(append([]zapcore.Field{}, c.fields...), fields...)}
+}
+
+func (c *stepCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if _, ok := stepMessages[entry.Message]; ok || entry.Level >= zapcore.WarnLevel {
+		return checked.AddCore(entry, c)
+	}
+
+	return checked
+}
+
+func (c *stepCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	step, ok := stepMessages[entry.Message]
+	if !ok {
+		step = entry.Message
+	}
+
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range append(c.fields, fields...) {
+		field.AddTo(encoder)
+	}
+	var details []string
+	for _, key := range stepFields {
+		if value, ok := encoder.Fields[key]; ok {
+			details = append(details, fmt.Sprintf("%s=%v", key, value))
+		}
+	}
+
+	line := "|-" + step
+	if len(details) > 0 {
+		line += " (" + strings.Join(details, ", ") + ")"
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := fmt.Fprintln(c.w, line)
+	return err
+}
+
+func (c *stepCore) Sync() error {
+	return nil
+}
@@ -0,0 +1,35 @@
+package acme
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+type StepLogTestSuite struct {
+	suite.Suite
+}
+
+func TestStepLogTestSuite(t *testing.T) {
+	suite.Run(t, &StepLogTestSuite{})
+}
+
+func (s *StepLogTestSuite) TestSetLog() {
+	var buf bytes.Buffer
+	client := &Client{}
+	client.zClient, _ = getClient(CALetsEncrypt)
+	client.SetLog(&buf)
+
+	logger := client.zClient.Client.Logger
+	logger.Debug("creating order", zap.Strings("identifiers", []string{"haozi.dev"}))
+	logger.Debug("http request", zap.String("method", "POST"))
+	logger.With(zap.String("identifier", "haozi.dev")).Info("trying to solve challenge", zap.String("challenge_type", "dns-01"))
+	logger.Error("challenge failed", zap.Error(errors.New("timeout")))
+
+	s.Equal("|-创建订单 (identifiers=[haozi.dev])\n"+
+		"|-开始验证 (identifier=haozi.dev, challenge_type=dns-01)\n"+
+		"|-验证失败 (error=timeout)\n", buf.String())
+}
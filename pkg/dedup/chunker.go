@@ -0,0 +1,90 @@
+package dedup
+
+import (
+	"errors"
+	"io"
+	"math/bits"
+)
+
+// 默认分块大小，媒体文件通常较大，平均 1 MiB 可以在去重率和块数量之间取得平衡
+const (
+	DefaultMinSize = 256 << 10
+	DefaultAvgSize = 1 << 20
+	DefaultMaxSize = 4 << 20
+)
+
+// gear 滚动哈希表，由固定种子生成，修改会导致已有的块无法复用
+var gear = func() [256]uint64 {
+	var table [256]uint64
+	seed := uint64(0x70616e656c) // "panel"
+	for i := range table {
+		// splitmix64
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		table[i] = z ^ (z >> 31)
+	}
+	return table
+}()
+
+// Chunker 基于内容的分块器，插入或删除数据只影响附近的块
+type Chunker struct {
+	reader io.Reader
+	min    int
+	max    int
+	mask   uint64
+	buf    []byte // 读取缓冲，每次至少保留 max 字节用于查找分块点
+	start  int
+	end    int
+	eof    bool
+}
+
+// NewChunker 创建分块器，avg 必须是 2 的幂
+func NewChunker(reader io.Reader, min, avg, max int) (*Chunker, error) {
+	if min <= 0 || avg < min || max < avg || avg&(avg-1) != 0 {
+		return nil, errors.New("分块大小配置错误")
+	}
+
+	// 使用哈希的高位判断分块点，高位受最近 64 字节影响
+	n := bits.TrailingZeros(uint(avg))
+	return &Chunker{
+		reader: reader,
+		min:    min,
+		max:    max,
+		mask:   (uint64(1)<<n - 1) << (64 - n),
+		buf:    make([]byte, 2*max),
+	}, nil
+}
+
+// Next 返回下一个块，返回的切片在下次调用前有效，没有更多数据时返回 io.EOF
+func (c *Chunker) Next() ([]byte, error) {
+	if c.end-c.start < c.max && !c.eof {
+		c.end = copy(c.buf, c.buf[c.start:c.end])
+		c.start = 0
+		n, err := io.ReadFull(c.reader, c.buf[c.end:])
+		c.end += n
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			c.eof = true
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	data := c.buf[c.start:c.end]
+	if len(data) == 0 {
+		return nil, io.EOF
+	}
+	size := min(len(data), c.max)
+	var hash uint64
+	for i := 0; i < size; i++ {
+		hash = hash<<1 + gear[data[i]]
+		if i+1 >= c.min && hash&c.mask == 0 {
+			size = i + 1
+			break
+		}
+	}
+	c.start += size
+
+	return data[:size], nil
+}
@@ -0,0 +1,140 @@
+package dedup
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DedupTestSuite struct {
+	suite.Suite
+}
+
+func TestDedupTestSuite(t *testing.T) {
+	suite.Run(t, &DedupTestSuite{})
+}
+
+func (s *DedupTestSuite) TestChunker() {
+	data := make([]byte, 256<<10)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	first := s.chunk(data)
+	s.Greater(len(first), 10)
+	s.Equal(data, bytes.Join(first, nil))
+
+	// 在开头插入数据后，后面的块保持不变
+	second := s.chunk(append([]byte("inserted"), data...))
+	s.Equal(first[len(first)-1], second[len(second)-1])
+	s.Equal(first[len(first)/2], second[len(second)/2+len(second)-len(first)])
+
+	_, err := NewChunker(bytes.NewReader(data), 1024, 3000, 16<<10)
+	s.Error(err)
+}
+
+func (s *DedupTestSuite) TestBackupAndRestore() {
+	source := s.T().TempDir()
+	data := make([]byte, 512<<10)
+	rand.New(rand.NewSource(2)).Read(data)
+	s.Require().Nil(os.MkdirAll(filepath.Join(source, "uploads", "2024"), 0755))
+	s.Require().Nil(os.WriteFile(filepath.Join(source, "uploads", "2024", "video.mp4"), data, 0644))
+	s.Require().Nil(os.WriteFile(filepath.Join(source, "index.php"), []byte("<?php echo 1;"), 0640))
+	s.Require().Nil(os.Symlink("uploads", filepath.Join(source, "media")))
+
+	repository, err := Open(s.T().TempDir())
+	s.Require().Nil(err)
+	repository.SetChunkSize(1<<10, 4<<10, 16<<10)
+
+	first, err := repository.Backup(source, "site")
+	s.Require().Nil(err)
+	s.Equal(2, first.Stats.Files)
+	s.Equal(2, first.Stats.Dirs)
+	s.Equal(int64(len(data)+13), first.Stats.Size)
+	s.Equal(first.Stats.Size, first.Stats.NewSize+first.Stats.ReusedSize)
+
+	// 修改小文件并在大文件中间插入数据，大部分块应该被复用
+	changed := append(append(append([]byte{}, data[:100<<10]...), []byte("changed")...), data[100<<10:]...)
+	s.Require().Nil(os.WriteFile(filepath.Join(source, "uploads", "2024", "video.mp4"), changed, 0644))
+	s.Require().Nil(os.WriteFile(filepath.Join(source, "index.php"), []byte("<?php echo 2;"), 0640))
+	second, err := repository.Backup(source, "site")
+	s.Require().Nil(err)
+	s.Less(second.Stats.NewSize, int64(64<<10))
+	s.Greater(second.Stats.ReusedSize, int64(400<<10))
+
+	snapshots, err := repository.Snapshots("site")
+	s.Require().Nil(err)
+	s.Len(snapshots, 2)
+	s.Nil(snapshots[0].Files)
+	snapshots, err = repository.Snapshots("other")
+	s.Nil(err)
+	s.Empty(snapshots)
+
+	// 每个快照都可以完整恢复
+	target := s.T().TempDir()
+	s.Require().Nil(repository.Restore(first.ID, target))
+	s.fileEqual(data, filepath.Join(target, "uploads", "2024", "video.mp4"))
+	s.fileEqual([]byte("<?php echo 1;"), filepath.Join(target, "index.php"))
+	info, err := os.Stat(filepath.Join(target, "index.php"))
+	s.Nil(err)
+	s.Equal(os.FileMode(0640), info.Mode().Perm())
+	link, err := os.Readlink(filepath.Join(target, "media"))
+	s.Nil(err)
+	s.Equal("uploads", link)
+
+	// 删除第一个快照后清理只属于它的块，第二个快照不受影响
+	s.Require().Nil(repository.Delete(first.ID))
+	pruned, err := repository.Prune()
+	s.Require().Nil(err)
+	s.Greater(pruned.Chunks, 0)
+	target = s.T().TempDir()
+	s.Require().Nil(repository.Restore(second.ID, target))
+	s.fileEqual(changed, filepath.Join(target, "uploads", "2024", "video.mp4"))
+	s.fileEqual([]byte("<?php echo 2;"), filepath.Join(target, "index.php"))
+
+	pruned, err = repository.Prune()
+	s.Nil(err)
+	s.Equal(0, pruned.Chunks)
+
+	s.Error(repository.Restore(first.ID, target))
+	s.Error(repository.Delete("../lock"))
+}
+
+func (s *DedupTestSuite) TestCorruptedChunk() {
+	source := s.T().TempDir()
+	s.Require().Nil(os.WriteFile(filepath.Join(source, "index.html"), []byte("hello"), 0644))
+
+	root := s.T().TempDir()
+	repository, err := Open(root)
+	s.Require().Nil(err)
+	snapshot, err := repository.Backup(source, "site")
+	s.Require().Nil(err)
+
+	chunk := snapshot.Files[0].Chunks[0]
+	s.Require().Nil(os.WriteFile(repository.chunkPath(chunk), []byte("broken"), 0600))
+	s.Error(repository.Restore(snapshot.ID, s.T().TempDir()))
+}
+
+func (s *DedupTestSuite) chunk(data []byte) [][]byte {
+	chunker, err := NewChunker(bytes.NewReader(data), 1<<10, 4<<10, 16<<10)
+	s.Require().Nil(err)
+
+	var chunks [][]byte
+	for {
+		chunk, err := chunker.Next()
+		if err == io.EOF {
+			return chunks
+		}
+		s.Require().Nil(err)
+		chunks = append(chunks, append([]byte{}, chunk...))
+	}
+}
+
+func (s *DedupTestSuite) fileEqual(expected []byte, file string) {
+	data, err := os.ReadFile(file)
+	s.Require().Nil(err)
+	s.True(bytes.Equal(expected, data))
+}
@@ -0,0 +1,520 @@
+// Package dedup 去重备份，文件按内容分块存储，快照只记录块的引用
+package dedup
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Repository 本地去重仓库
+//
+// 目录结构:
+//
+//	chunks/ab/abcdef...  按 SHA256 命名的压缩块
+//	snapshots/ID.json    快照
+//	lock                 修改仓库时加锁，防止清理删除正在备份的块
+type Repository struct {
+	root string
+	min  int
+	avg  int
+	max  int
+}
+
+// File 快照中的文件
+type File struct {
+	Path    string      `json:"path"` // 以 / 分隔的相对路径
+	Mode    fs.FileMode `json:"mode"`
+	ModTime time.Time   `json:"mod_time"`
+	Size    int64       `json:"size,omitempty"`
+	Link    string      `json:"link,omitempty"`   // 软链接目标
+	Chunks  []string    `json:"chunks,omitempty"` // 文件内容的块
+}
+
+// Stats 快照统计，New 为本次新增的数据，Reused 为复用已有块的数据
+type Stats struct {
+	Files        int           `json:"files"`
+	Dirs         int           `json:"dirs"`
+	Size         int64         `json:"size"`
+	NewChunks    int           `json:"new_chunks"`
+	NewSize      int64         `json:"new_size"`
+	StoredSize   int64         `json:"stored_size"` // 新增块压缩后的大小
+	ReusedChunks int           `json:"reused_chunks"`
+	ReusedSize   int64         `json:"reused_size"`
+	Duration     time.Duration `json:"duration"`
+}
+
+// Snapshot 快照
+type Snapshot struct {
+	ID     string    `json:"id"`
+	Tag    string    `json:"tag"` // 快照分组，例如网站名称
+	Source string    `json:"source"`
+	Time   time.Time `json:"time"`
+	Stats  Stats     `json:"stats"`
+	Files  []File    `json:"files,omitempty"`
+}
+
+// PruneStats 清理统计
+type PruneStats struct {
+	Chunks int   `json:"chunks"`
+	Size   int64 `json:"size"`
+}
+
+// Open 打开仓库，不存在时创建
+func Open(root string) (*Repository, error) {
+	if !filepath.IsAbs(root) {
+		return nil, errors.New("仓库目录必须是绝对路径")
+	}
+	for _, dir := range []string{"chunks", "snapshots"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0700); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Repository{root: root, min: DefaultMinSize, avg: DefaultAvgSize, max: DefaultMaxSize}, nil
+}
+
+// SetChunkSize 设置分块大小，已有的块仍然可以读取，但新旧分块之间无法复用
+func (r *Repository) SetChunkSize(min, avg, max int) {
+	r.min, r.avg, r.max = min, avg, max
+}
+
+// Backup 为 source 目录创建快照
+func (r *Repository) Backup(source, tag string) (*Snapshot, error) {
+	unlock, err := r.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	start := time.Now()
+	id, err := newID(start)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &Snapshot{ID: id, Tag: tag, Source: source, Time: start}
+
+	// 同一次备份中重复的块只计算一次
+	seen := make(map[string]bool)
+	err = filepath.WalkDir(source, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(source, file)
+		if err != nil || rel == "." {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		item := File{Path: filepath.ToSlash(rel), Mode: info.Mode(), ModTime: info.ModTime()}
+		switch {
+		case info.IsDir():
+			snapshot.Stats.Dirs++
+		case info.Mode()&fs.ModeSymlink != 0:
+			if item.Link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		case info.Mode().IsRegular():
+			item.Size = info.Size()
+			if item.Chunks, err = r.storeFile(file, &snapshot.Stats, seen); err != nil {
+				return fmt.Errorf("备份文件 %s 失败: %w", rel, err)
+			}
+			snapshot.Stats.Files++
+			snapshot.Stats.Size += item.Size
+		default:
+			// 跳过设备文件、套接字等
+			return nil
+		}
+
+		snapshot.Files = append(snapshot.Files, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot.Stats.Duration = time.Since(start)
+	if err = r.writeSnapshot(snapshot); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// Restore 将快照完整恢复到 target 目录
+func (r *Repository) Restore(id, target string) error {
+	snapshot, err := r.Snapshot(id)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(target, 0755); err != nil {
+		return err
+	}
+
+	for _, item := range snapshot.Files {
+		if !filepath.IsLocal(filepath.FromSlash(item.Path)) {
+			return fmt.Errorf("快照中的路径 %s 不合法", item.Path)
+		}
+		file := filepath.Join(target, filepath.FromSlash(item.Path))
+
+		switch {
+		case item.Mode.IsDir():
+			err = os.MkdirAll(file, 0755)
+		case item.Mode&fs.ModeSymlink != 0:
+			_ = os.Remove(file)
+			err = os.Symlink(item.Link, file)
+		default:
+			err = r.restoreFile(item, file)
+		}
+		if err != nil {
+			return fmt.Errorf("恢复文件 %s 失败: %w", item.Path, err)
+		}
+	}
+
+	// 最后设置目录权限和时间，写入文件会修改目录的修改时间
+	for i := len(snapshot.Files) - 1; i >= 0; i-- {
+		item := snapshot.Files[i]
+		if item.Mode&fs.ModeSymlink != 0 {
+			continue
+		}
+		file := filepath.Join(target, filepath.FromSlash(item.Path))
+		if err = os.Chmod(file, item.Mode.Perm()); err != nil {
+			return err
+		}
+		if err = os.Chtimes(file, item.ModTime, item.ModTime); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Snapshots 快照列表，按时间倒序，不包含文件列表，tag 为空时返回所有快照
+func (r *Repository) Snapshots(tag string) ([]Snapshot, error) {
+	entries, err := os.ReadDir(filepath.Join(r.root, "snapshots"))
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := []Snapshot{}
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok {
+			continue
+		}
+		snapshot, err := r.Snapshot(id)
+		if err != nil {
+			return nil, err
+		}
+		if tag != "" && snapshot.Tag != tag {
+			continue
+		}
+		snapshot.Files = nil
+		snapshots = append(snapshots, *snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Time.After(snapshots[j].Time)
+	})
+
+	return snapshots, nil
+}
+
+// Snapshot 获取快照
+func (r *Repository) Snapshot(id string) (*Snapshot, error) {
+	if !validID(id) {
+		return nil, errors.New("快照 ID 不合法")
+	}
+
+	data, err := os.ReadFile(filepath.Join(r.root, "snapshots", id+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errors.New("快照不存在")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot Snapshot
+	if err = json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// Delete 删除快照，块需要调用 Prune 清理
+func (r *Repository) Delete(id string) error {
+	if !validID(id) {
+		return errors.New("快照 ID 不合法")
+	}
+
+	unlock, err := r.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = os.Remove(filepath.Join(r.root, "snapshots", id+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return errors.New("快照不存在")
+	}
+
+	return err
+}
+
+// Prune 删除没有被任何快照引用的块
+func (r *Repository) Prune() (PruneStats, error) {
+	unlock, err := r.lock()
+	if err != nil {
+		return PruneStats{}, err
+	}
+	defer unlock()
+
+	entries, err := os.ReadDir(filepath.Join(r.root, "snapshots"))
+	if err != nil {
+		return PruneStats{}, err
+	}
+	referenced := make(map[string]bool)
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok {
+			continue
+		}
+		snapshot, err := r.Snapshot(id)
+		if err != nil {
+			// 无法读取快照时不能确定块是否被引用，停止清理
+			return PruneStats{}, err
+		}
+		for _, item := range snapshot.Files {
+			for _, chunk := range item.Chunks {
+				referenced[chunk] = true
+			}
+		}
+	}
+
+	var stats PruneStats
+	err = filepath.WalkDir(filepath.Join(r.root, "chunks"), func(file string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || referenced[entry.Name()] {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if err = os.Remove(file); err != nil {
+			return err
+		}
+		stats.Chunks++
+		stats.Size += info.Size()
+		return nil
+	})
+
+	return stats, err
+}
+
+// storeFile 分块存储文件，返回块列表
+func (r *Repository) storeFile(file string, stats *Stats, seen map[string]bool) ([]string, error) {
+	reader, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	chunker, err := NewChunker(reader, r.min, r.avg, r.max)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := []string{}
+	for {
+		data, err := chunker.Next()
+		if errors.Is(err, io.EOF) {
+			return chunks, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		sum := sha256.Sum256(data)
+		hash := hex.EncodeToString(sum[:])
+		chunks = append(chunks, hash)
+		if seen[hash] {
+			stats.ReusedChunks++
+			stats.ReusedSize += int64(len(data))
+			continue
+		}
+		seen[hash] = true
+
+		stored, err := r.writeChunk(hash, data)
+		if err != nil {
+			return nil, err
+		}
+		if stored == 0 {
+			stats.ReusedChunks++
+			stats.ReusedSize += int64(len(data))
+		} else {
+			stats.NewChunks++
+			stats.NewSize += int64(len(data))
+			stats.StoredSize += stored
+		}
+	}
+}
+
+func (r *Repository) chunkPath(hash string) string {
+	return filepath.Join(r.root, "chunks", hash[:2], hash)
+}
+
+// writeChunk 压缩写入块，块已存在时返回 0
+func (r *Repository) writeChunk(hash string, data []byte) (int64, error) {
+	file := r.chunkPath(hash)
+	if _, err := os.Stat(file); err == nil {
+		return 0, nil
+	}
+
+	var buf bytes.Buffer
+	writer, err := flate.NewWriter(&buf, flate.BestSpeed)
+	if err != nil {
+		return 0, err
+	}
+	if _, err = writer.Write(data); err != nil {
+		return 0, err
+	}
+	if err = writer.Close(); err != nil {
+		return 0, err
+	}
+
+	if err = writeFile(file, buf.Bytes()); err != nil {
+		return 0, err
+	}
+
+	return int64(buf.Len()), nil
+}
+
+// readChunk 读取块并校验内容
+func (r *Repository) readChunk(hash string) ([]byte, error) {
+	if len(hash) != sha256.Size*2 {
+		return nil, fmt.Errorf("块 %s 不合法", hash)
+	}
+	compressed, err := os.ReadFile(r.chunkPath(hash))
+	if err != nil {
+		return nil, fmt.Errorf("读取块 %s 失败: %w", hash, err)
+	}
+	data, err := io.ReadAll(flate.NewReader(bytes.NewReader(compressed)))
+	if err != nil {
+		return nil, fmt.Errorf("解压块 %s 失败: %w", hash, err)
+	}
+	if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != hash {
+		return nil, fmt.Errorf("块 %s 已损坏", hash)
+	}
+
+	return data, nil
+}
+
+func (r *Repository) restoreFile(item File, file string) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	writer, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, item.Mode.Perm())
+	if err != nil {
+		return err
+	}
+
+	for _, hash := range item.Chunks {
+		data, err := r.readChunk(hash)
+		if err != nil {
+			_ = writer.Close()
+			return err
+		}
+		if _, err = writer.Write(data); err != nil {
+			_ = writer.Close()
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+func (r *Repository) writeSnapshot(snapshot *Snapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	return writeFile(filepath.Join(r.root, "snapshots", snapshot.ID+".json"), data)
+}
+
+// lock 对仓库加排他锁，CLI 计划任务和面板可能同时操作同一个仓库
+func (r *Repository) lock() (func(), error) {
+	file, err := os.OpenFile(filepath.Join(r.root, "lock"), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return func() {
+		_ = syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+		_ = file.Close()
+	}, nil
+}
+
+// writeFile 先写入临时文件再重命名，避免中断时留下不完整的文件
+func writeFile(file string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(file), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err = temp.Write(data); err != nil {
+		_ = temp.Close()
+		return err
+	}
+	if err = temp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(temp.Name(), file)
+}
+
+func newID(t time.Time) (string, error) {
+	data := make([]byte, 3)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+
+	return t.Format("20060102150405") + "-" + hex.EncodeToString(data), nil
+}
+
+func validID(id string) bool {
+	if len(id) == 0 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c == '-') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,247 @@
+// Package encryption 备份加密，使用 age 格式，可以直接用 age 命令行工具解密
+package encryption
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// Type 密钥类型
+type Type string
+
+const (
+	TypePassphrase Type = "passphrase" // 密码，使用 scrypt 派生密钥
+	TypeX25519     Type = "x25519"     // age 公钥，可以只保存公钥，恢复时再提供私钥
+)
+
+// Extension 加密后的备份文件后缀
+const Extension = ".age"
+
+// Key 加密密钥
+type Key struct {
+	Type Type
+	// Recipient 公钥，TypePassphrase 时为空
+	Recipient string
+	// Identity TypePassphrase 时为密码，TypeX25519 时为私钥，只保存公钥时为空
+	Identity string
+}
+
+// GenerateX25519 生成 age X25519 密钥对
+func GenerateX25519() (recipient string, identity string, err error) {
+	key, err := age.GenerateX25519Identity()
+	if err != nil {
+		return "", "", err
+	}
+
+	return key.Recipient().String(), key.String(), nil
+}
+
+// ParseX25519 从私钥获取公钥
+func ParseX25519(identity string) (string, error) {
+	key, err := age.ParseX25519Identity(strings.TrimSpace(identity))
+	if err != nil {
+		return "", err
+	}
+
+	return key.Recipient().String(), nil
+}
+
+// NewFingerprint 生成密钥指纹，写入加密后的文件名用于识别加密使用的密钥
+//
+// 指纹是随机的，不会泄露密码或公钥的任何信息
+func NewFingerprint() (string, error) {
+	data := make([]byte, 4)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(data), nil
+}
+
+// Name 加密后的文件名，格式为 原文件名.指纹.age
+func Name(name, fingerprint string) string {
+	return name + "." + fingerprint + Extension
+}
+
+// ParseName 解析加密后的文件名，返回原文件名和指纹
+func ParseName(name string) (plain string, fingerprint string, encrypted bool) {
+	base, ok := strings.CutSuffix(name, Extension)
+	if !ok {
+		return name, "", false
+	}
+	index := strings.LastIndex(base, ".")
+	if index == -1 {
+		return base, "", true
+	}
+
+	return base[:index], base[index+1:], true
+}
+
+// Validate 检查公钥和私钥格式，私钥存在时检查是否与公钥匹配
+func (k Key) Validate() error {
+	if _, err := k.recipient(); err != nil {
+		return err
+	}
+	if k.Type == TypeX25519 && k.Identity != "" {
+		recipient, err := ParseX25519(k.Identity)
+		if err != nil {
+			return err
+		}
+		if recipient != strings.TrimSpace(k.Recipient) {
+			return errors.New("私钥与公钥不匹配")
+		}
+	}
+
+	return nil
+}
+
+func (k Key) recipient() (age.Recipient, error) {
+	switch k.Type {
+	case TypePassphrase:
+		if k.Identity == "" {
+			return nil, errors.New("密码不能为空")
+		}
+		return age.NewScryptRecipient(k.Identity)
+	case TypeX25519:
+		return age.ParseX25519Recipient(strings.TrimSpace(k.Recipient))
+	}
+
+	return nil, errors.New("不支持的密钥类型")
+}
+
+func (k Key) identity() (age.Identity, error) {
+	if k.Identity == "" {
+		return nil, errors.New("未保存私钥，无法解密")
+	}
+
+	switch k.Type {
+	case TypePassphrase:
+		return age.NewScryptIdentity(k.Identity)
+	case TypeX25519:
+		return age.ParseX25519Identity(strings.TrimSpace(k.Identity))
+	}
+
+	return nil, errors.New("不支持的密钥类型")
+}
+
+// Encrypt 加密 src 写入 dst
+func Encrypt(dst io.Writer, src io.Reader, key Key) error {
+	recipient, err := key.recipient()
+	if err != nil {
+		return err
+	}
+
+	writer, err := age.Encrypt(dst, recipient)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(writer, src); err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+// Decrypt 使用密钥解密 src 写入 dst
+func Decrypt(dst io.Writer, src io.Reader, key Key) error {
+	identity, err := key.identity()
+	if err != nil {
+		return err
+	}
+
+	return DecryptWith(dst, src, identity)
+}
+
+// DecryptWith 使用任意 age 身份解密，用于命令行解密
+func DecryptWith(dst io.Writer, src io.Reader, identities ...age.Identity) error {
+	reader, err := age.Decrypt(src, identities...)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(dst, reader)
+	return err
+}
+
+// ParseIdentities 解析密码或 age 私钥文件内容
+func ParseIdentities(passphrase string, identityFile string) ([]age.Identity, error) {
+	if passphrase != "" {
+		identity, err := age.NewScryptIdentity(passphrase)
+		if err != nil {
+			return nil, err
+		}
+		return []age.Identity{identity}, nil
+	}
+
+	file, err := os.Open(identityFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return age.ParseIdentities(file)
+}
+
+// EncryptFile 加密文件，返回加密后的文件路径，成功后删除原文件
+func EncryptFile(file string, key Key, fingerprint string) (string, error) {
+	target := filepath.Join(filepath.Dir(file), Name(filepath.Base(file), fingerprint))
+	if err := transformFile(file, target, func(dst io.Writer, src io.Reader) error {
+		return Encrypt(dst, src, key)
+	}); err != nil {
+		return "", err
+	}
+
+	return target, os.Remove(file)
+}
+
+// DecryptFile 使用密钥解密文件到 target，不删除加密文件
+func DecryptFile(file, target string, key Key) error {
+	identity, err := key.identity()
+	if err != nil {
+		return err
+	}
+
+	return DecryptFileWith(file, target, identity)
+}
+
+// DecryptFileWith 使用任意 age 身份解密文件到 target，不删除加密文件
+func DecryptFileWith(file, target string, identities ...age.Identity) error {
+	return transformFile(file, target, func(dst io.Writer, src io.Reader) error {
+		return DecryptWith(dst, src, identities...)
+	})
+}
+
+// transformFile 先写入临时文件，成功后再重命名，避免留下不完整的文件
+func transformFile(file, target string, transform func(dst io.Writer, src io.Reader) error) error {
+	src, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.CreateTemp(filepath.Dir(target), ".encryption-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(dst.Name())
+
+	if err = transform(dst, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(dst.Name(), 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(dst.Name(), target)
+}
@@ -0,0 +1,96 @@
+package encryption
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type EncryptionTestSuite struct {
+	suite.Suite
+}
+
+func TestEncryptionTestSuite(t *testing.T) {
+	suite.Run(t, &EncryptionTestSuite{})
+}
+
+func (s *EncryptionTestSuite) TestPassphrase() {
+	key := Key{Type: TypePassphrase, Identity: "correct horse"}
+	s.Nil(key.Validate())
+
+	var encrypted bytes.Buffer
+	s.Require().Nil(Encrypt(&encrypted, strings.NewReader("backup"), key))
+	s.NotContains(encrypted.String(), "backup")
+
+	var decrypted bytes.Buffer
+	s.Require().Nil(Decrypt(&decrypted, bytes.NewReader(encrypted.Bytes()), key))
+	s.Equal("backup", decrypted.String())
+
+	s.Error(Decrypt(&decrypted, bytes.NewReader(encrypted.Bytes()), Key{Type: TypePassphrase, Identity: "wrong"}))
+	s.Error(Key{Type: TypePassphrase}.Validate())
+}
+
+func (s *EncryptionTestSuite) TestX25519() {
+	recipient, identity, err := GenerateX25519()
+	s.Require().Nil(err)
+	parsed, err := ParseX25519(identity)
+	s.Nil(err)
+	s.Equal(recipient, parsed)
+
+	otherRecipient, _, err := GenerateX25519()
+	s.Require().Nil(err)
+	s.Error(Key{Type: TypeX25519, Recipient: otherRecipient, Identity: identity}.Validate())
+	s.Error(Key{Type: TypeX25519, Recipient: "age1invalid"}.Validate())
+
+	// 只保存公钥时可以加密但不能解密
+	publicOnly := Key{Type: TypeX25519, Recipient: recipient}
+	s.Nil(publicOnly.Validate())
+	var encrypted bytes.Buffer
+	s.Require().Nil(Encrypt(&encrypted, strings.NewReader("backup"), publicOnly))
+	var decrypted bytes.Buffer
+	s.Error(Decrypt(&decrypted, bytes.NewReader(encrypted.Bytes()), publicOnly))
+
+	s.Nil(Decrypt(&decrypted, bytes.NewReader(encrypted.Bytes()), Key{Type: TypeX25519, Recipient: recipient, Identity: identity}))
+	s.Equal("backup", decrypted.String())
+}
+
+func (s *EncryptionTestSuite) TestFile() {
+	dir := s.T().TempDir()
+	file := filepath.Join(dir, "site_20240520.zip")
+	s.Require().Nil(os.WriteFile(file, []byte("backup"), 0644))
+
+	recipient, identity, err := GenerateX25519()
+	s.Require().Nil(err)
+	fingerprint, err := NewFingerprint()
+	s.Require().Nil(err)
+	s.Len(fingerprint, 8)
+
+	encrypted, err := EncryptFile(file, Key{Type: TypeX25519, Recipient: recipient}, fingerprint)
+	s.Require().Nil(err)
+	s.Equal(filepath.Join(dir, "site_20240520.zip."+fingerprint+".age"), encrypted)
+	s.NoFileExists(file)
+
+	plain, parsedFingerprint, ok := ParseName(filepath.Base(encrypted))
+	s.True(ok)
+	s.Equal("site_20240520.zip", plain)
+	s.Equal(fingerprint, parsedFingerprint)
+	_, _, ok = ParseName("site_20240520.zip")
+	s.False(ok)
+
+	// 命令行使用私钥文件解密，文件格式与 age-keygen 相同
+	identityFile := filepath.Join(dir, "key.txt")
+	s.Require().Nil(os.WriteFile(identityFile, []byte("# public key: "+recipient+"\n"+identity+"\n"), 0600))
+	identities, err := ParseIdentities("", identityFile)
+	s.Require().Nil(err)
+	target := filepath.Join(dir, plain)
+	s.Require().Nil(DecryptFileWith(encrypted, target, identities...))
+	data, err := os.ReadFile(target)
+	s.Nil(err)
+	s.Equal("backup", string(data))
+
+	s.Error(DecryptFile(encrypted, target, Key{Type: TypePassphrase, Identity: "wrong"}))
+}
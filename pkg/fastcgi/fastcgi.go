@@ -0,0 +1,161 @@
+// Package fastcgi 精简的 FastCGI 客户端，用于直接请求 PHP-FPM
+package fastcgi
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	typeBeginRequest = 1
+	typeEndRequest   = 3
+	typeParams       = 4
+	typeStdin        = 5
+	typeStdout       = 6
+	typeStderr       = 7
+
+	roleResponder = 1
+	requestID     = 1
+	maxWrite      = 65535
+)
+
+// Response FastCGI 响应
+type Response struct {
+	Status int
+	Header http.Header
+	Body   string
+}
+
+// Get 通过 network/address 向 FastCGI 服务发起 GET 请求
+func Get(network, address, path string, timeout time.Duration) (*Response, error) {
+	conn, err := net.DialTimeout(network, address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if err = conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+
+	uri, query, _ := strings.Cut(path, "?")
+	params := map[string]string{
+		"GATEWAY_INTERFACE": "CGI/1.1",
+		"SERVER_PROTOCOL":   "HTTP/1.1",
+		"REQUEST_METHOD":    "GET",
+		"SCRIPT_NAME":       uri,
+		"SCRIPT_FILENAME":   uri,
+		"REQUEST_URI":       path,
+		"QUERY_STRING":      query,
+		"REMOTE_ADDR":       "127.0.0.1",
+		"SERVER_NAME":       "127.0.0.1",
+	}
+
+	var buf bytes.Buffer
+	writeRecord(&buf, typeBeginRequest, []byte{0, roleResponder, 0, 0, 0, 0, 0, 0})
+	var paramsBuf bytes.Buffer
+	for k, v := range params {
+		writePair(&paramsBuf, k, v)
+	}
+	writeRecord(&buf, typeParams, paramsBuf.Bytes())
+	writeRecord(&buf, typeParams, nil)
+	writeRecord(&buf, typeStdin, nil)
+	if _, err = conn.Write(buf.Bytes()); err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	reader := bufio.NewReader(conn)
+	for {
+		header := make([]byte, 8)
+		if _, err = io.ReadFull(reader, header); err != nil {
+			return nil, err
+		}
+		length := binary.BigEndian.Uint16(header[4:6])
+		padding := header[6]
+		content := make([]byte, int(length)+int(padding))
+		if _, err = io.ReadFull(reader, content); err != nil {
+			return nil, err
+		}
+		content = content[:length]
+
+		switch header[1] {
+		case typeStdout:
+			stdout.Write(content)
+		case typeStderr:
+			stderr.Write(content)
+		case typeEndRequest:
+			if stdout.Len() == 0 && stderr.Len() > 0 {
+				return nil, errors.New(strings.TrimSpace(stderr.String()))
+			}
+			return parseResponse(stdout.Bytes())
+		}
+	}
+}
+
+// parseResponse 解析 CGI 响应
+func parseResponse(raw []byte) (*Response, error) {
+	reader := textproto.NewReader(bufio.NewReader(bytes.NewReader(raw)))
+	mime, err := reader.ReadMIMEHeader()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	response := &Response{Status: http.StatusOK, Header: http.Header(mime)}
+	if status := response.Header.Get("Status"); len(status) > 0 {
+		code, _, _ := strings.Cut(status, " ")
+		if response.Status, err = strconv.Atoi(code); err != nil {
+			return nil, errors.New("无效的响应状态: " + status)
+		}
+	}
+	body, err := io.ReadAll(reader.R)
+	if err != nil {
+		return nil, err
+	}
+	response.Body = string(body)
+
+	return response, nil
+}
+
+func writeRecord(w *bytes.Buffer, recordType byte, content []byte) {
+	for {
+		chunk := content
+		if len(chunk) > maxWrite {
+			chunk = chunk[:maxWrite]
+		}
+		padding := -len(chunk) & 7
+		w.Write([]byte{1, recordType, 0, requestID, byte(len(chunk) >> 8), byte(len(chunk)), byte(padding), 0})
+		w.Write(chunk)
+		w.Write(make([]byte, padding))
+
+		content = content[len(chunk):]
+		if len(content) == 0 {
+			return
+		}
+	}
+}
+
+func writePair(w *bytes.Buffer, key, value string) {
+	writeSize(w, len(key))
+	writeSize(w, len(value))
+	w.WriteString(key)
+	w.WriteString(value)
+}
+
+func writeSize(w *bytes.Buffer, size int) {
+	if size <= 127 {
+		w.WriteByte(byte(size))
+		return
+	}
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, uint32(size)|1<<31)
+	w.Write(b)
+}
@@ -0,0 +1,64 @@
+package fastcgi
+
+import (
+	"net"
+	"net/http"
+	"net/http/fcgi"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type FastCGITestSuite struct {
+	suite.Suite
+	socket   string
+	listener net.Listener
+}
+
+func TestFastCGITestSuite(t *testing.T) {
+	suite.Run(t, &FastCGITestSuite{})
+}
+
+func (s *FastCGITestSuite) SetupSuite() {
+	s.socket = filepath.Join(s.T().TempDir(), "php-cgi.sock")
+	listener, err := net.Listen("unix", s.socket)
+	s.Require().NoError(err)
+	s.listener = listener
+
+	go func() {
+		_ = fcgi.Serve(listener, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/phpfpm_status" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("pool:                 test\nprocess manager:      dynamic\nquery: " + r.URL.RawQuery + "\n"))
+		}))
+	}()
+}
+
+func (s *FastCGITestSuite) TearDownSuite() {
+	s.NoError(s.listener.Close())
+}
+
+func (s *FastCGITestSuite) TestGet() {
+	response, err := Get("unix", s.socket, "/phpfpm_status?full", 5*time.Second)
+	s.NoError(err)
+	s.Equal(http.StatusOK, response.Status)
+	s.Equal("text/plain", response.Header.Get("Content-Type"))
+	s.Contains(response.Body, "pool:                 test")
+	s.Contains(response.Body, "query: full")
+}
+
+func (s *FastCGITestSuite) TestGetNotFound() {
+	response, err := Get("unix", s.socket, "/missing", 5*time.Second)
+	s.NoError(err)
+	s.Equal(http.StatusNotFound, response.Status)
+}
+
+func (s *FastCGITestSuite) TestGetDialError() {
+	_, err := Get("unix", filepath.Join(s.T().TempDir(), "none.sock"), "/phpfpm_status", time.Second)
+	s.Error(err)
+}
@@ -0,0 +1,776 @@
+// Package htaccess 将 Apache .htaccess 伪静态规则转换为 OpenResty 规则
+package htaccess
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Unsupported 无法转换的行
+type Unsupported struct {
+	Line    int    `json:"line"`
+	Content string `json:"content"`
+	Reason  string `json:"reason"`
+}
+
+// Result 转换结果
+type Result struct {
+	Rewrite     string        `json:"rewrite"`
+	Unsupported []Unsupported `json:"unsupported"`
+}
+
+type line struct {
+	number  int
+	content string
+}
+
+type condition struct {
+	line    line
+	test    string
+	pattern string
+	flags   map[string]string
+}
+
+type block struct {
+	config   string
+	tryFiles string
+}
+
+type converter struct {
+	base        string
+	rules       int
+	conditions  []condition
+	blocks      []block
+	unsupported []Unsupported
+}
+
+// variables Apache 服务器变量与 OpenResty 变量的对应关系
+var variables = map[string]string{
+	"REQUEST_FILENAME": "$request_filename",
+	"HTTP_HOST":        "$http_host",
+	"SERVER_NAME":      "$server_name",
+	"SERVER_PORT":      "$server_port",
+	"REQUEST_URI":      "$uri",
+	"QUERY_STRING":     "$args",
+	"REQUEST_METHOD":   "$request_method",
+	"REQUEST_SCHEME":   "$scheme",
+	"THE_REQUEST":      "$request",
+	"DOCUMENT_ROOT":    "$document_root",
+	"REMOTE_ADDR":      "$remote_addr",
+	"HTTP_USER_AGENT":  "$http_user_agent",
+	"HTTP_REFERER":     "$http_referer",
+	"HTTP_COOKIE":      "$http_cookie",
+}
+
+// flagAliases 规则标志的完整写法
+var flagAliases = map[string]string{
+	"LAST":        "L",
+	"REDIRECT":    "R",
+	"NOCASE":      "NC",
+	"QSAPPEND":    "QSA",
+	"QSDISCARD":   "QSD",
+	"FORBIDDEN":   "F",
+	"GONE":        "G",
+	"NOESCAPE":    "NE",
+	"PASSTHROUGH": "PT",
+	"ORNEXT":      "OR",
+}
+
+// redirectStatus Redirect 指令的状态关键字
+var redirectStatus = map[string]int{
+	"permanent": 301,
+	"temp":      302,
+	"seeother":  303,
+	"gone":      410,
+}
+
+var (
+	variablePattern    = regexp.MustCompile(`%\{([^}]+)}`)
+	condCapturePattern = regexp.MustCompile(`%(\d)`)
+	ruleCapturePattern = regexp.MustCompile(`\$\d`)
+	singleVariable     = regexp.MustCompile(`^\$[a-z_0-9]+$`)
+)
+
+// Convert 转换 .htaccess 内容
+func Convert(content string) Result {
+	c := &converter{base: "/"}
+	lines := readLines(content)
+	for i := 0; i < len(lines); i++ {
+		l := lines[i]
+		if strings.HasPrefix(l.content, "</") {
+			continue
+		}
+		if strings.HasPrefix(l.content, "<") {
+			tag := strings.Trim(strings.Fields(l.content)[0], "<>")
+			if strings.EqualFold(tag, "IfModule") {
+				continue
+			}
+			// 跳过整个配置块
+			c.unsupport(l, "不支持 <"+tag+"> 配置块")
+			for depth := 1; depth > 0 && i+1 < len(lines); {
+				i++
+				if strings.HasPrefix(lines[i].content, "</") {
+					depth--
+				} else if strings.HasPrefix(lines[i].content, "<") {
+					depth++
+				}
+			}
+			continue
+		}
+
+		c.parse(l)
+	}
+	for _, cond := range c.conditions {
+		c.unsupport(cond.line, "RewriteCond 之后缺少 RewriteRule")
+	}
+
+	var configs []string
+	for i, b := range c.blocks {
+		// try_files 在服务器级的 rewrite 之后执行，只有最后一条规则才能安全地使用
+		if len(b.tryFiles) > 0 && i == len(c.blocks)-1 {
+			configs = append(configs, b.tryFiles)
+			continue
+		}
+		configs = append(configs, b.config)
+	}
+
+	result := Result{Unsupported: c.unsupported}
+	if len(configs) > 0 {
+		result.Rewrite = strings.Join(configs, "\n") + "\n"
+	}
+	if result.Unsupported == nil {
+		result.Unsupported = []Unsupported{}
+	}
+
+	return result
+}
+
+// readLines 读取有效行，合并以反斜杠结尾的续行
+func readLines(content string) []line {
+	var lines []line
+	var current *line
+	for i, raw := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		text := strings.TrimSpace(raw)
+		if current == nil {
+			if len(text) == 0 || strings.HasPrefix(text, "#") {
+				continue
+			}
+			current = &line{number: i + 1}
+		}
+
+		if strings.HasSuffix(text, "\\") {
+			current.content += strings.TrimSuffix(text, "\\") + " "
+			continue
+		}
+		// 只有续行符的行拼接后为空，忽略
+		current.content = strings.TrimSpace(current.content + text)
+		if len(current.content) > 0 {
+			lines = append(lines, *current)
+		}
+		current = nil
+	}
+	if current != nil {
+		current.content = strings.TrimSpace(current.content)
+		if len(current.content) > 0 {
+			lines = append(lines, *current)
+		}
+	}
+
+	return lines
+}
+
+// parse 解析单行指令
+func (c *converter) parse(l line) {
+	args := splitArgs(l.content)
+	if len(args) == 0 {
+		return
+	}
+	directive := strings.ToLower(args[0])
+	args = args[1:]
+
+	switch directive {
+	case "rewriteengine":
+	case "rewritebase":
+		if len(args) != 1 {
+			c.unsupport(l, "参数错误")
+			return
+		}
+		c.base = "/" + strings.Trim(args[0], "/") + "/"
+		if c.base == "//" {
+			c.base = "/"
+		}
+	case "rewritecond":
+		if len(args) < 2 || len(args) > 3 {
+			c.unsupport(l, "参数错误")
+			return
+		}
+		flags, err := parseFlags(args[2:])
+		if err != nil {
+			c.unsupport(l, err.Error())
+			return
+		}
+		c.conditions = append(c.conditions, condition{line: l, test: args[0], pattern: args[1], flags: flags})
+	case "rewriterule":
+		c.rule(l, args)
+	case "redirect", "redirectpermanent", "redirecttemp", "redirectmatch":
+		c.redirect(l, directive, args)
+	case "options":
+		for _, option := range args {
+			switch strings.ToLower(option) {
+			case "-indexes", "+followsymlinks", "followsymlinks", "+symlinksifownermatch", "-multiviews":
+			default:
+				c.unsupport(l, "不支持的选项 "+option)
+				return
+			}
+		}
+	case "errordocument":
+		if len(args) != 2 || !strings.HasPrefix(args[1], "/") {
+			c.unsupport(l, "仅支持指向本地路径的错误页")
+			return
+		}
+		c.blocks = append(c.blocks, block{config: "error_page " + args[0] + " " + quote(args[1]) + ";"})
+	default:
+		c.unsupport(l, "不支持的指令 "+args[0])
+	}
+}
+
+// rule 转换 RewriteRule 及其之前的 RewriteCond
+func (c *converter) rule(l line, args []string) {
+	conditions := c.conditions
+	c.conditions = nil
+	fail := func(reason string) {
+		for _, cond := range conditions {
+			c.unsupport(cond.line, "所属的 RewriteRule 无法转换")
+		}
+		c.unsupport(l, reason)
+	}
+
+	if len(args) < 2 || len(args) > 3 {
+		fail("参数错误")
+		return
+	}
+	flags, err := parseFlags(args[2:])
+	if err != nil {
+		fail(err.Error())
+		return
+	}
+	for flag := range flags {
+		switch flag {
+		case "L", "END", "R", "NC", "QSA", "QSD", "F", "G", "NE", "PT":
+		default:
+			fail("不支持的标志 " + flag)
+			return
+		}
+	}
+
+	id := "htaccess_" + strconv.Itoa(c.rules+1)
+	pattern, negate := strings.CutPrefix(args[0], "!")
+	target := args[1]
+	_, nocase := flags["NC"]
+
+	// 条件分组，[OR] 连接的条件归为同一组
+	var setup []string
+	var groups [][]string
+	for i, cond := range conditions {
+		capture := ""
+		if i == len(conditions)-1 {
+			capture = id + "_c"
+		}
+		s, expr, err := c.condition(cond, id+"_t"+strconv.Itoa(i), capture)
+		if err != nil {
+			fail(err.Error())
+			return
+		}
+		setup = append(setup, s...)
+		if i > 0 {
+			if _, or := conditions[i-1].flags["OR"]; or {
+				groups[len(groups)-1] = append(groups[len(groups)-1], expr)
+				continue
+			}
+		}
+		groups = append(groups, []string{expr})
+	}
+
+	// 仅最后一个条件的捕获组可被 %N 引用
+	captures := 0
+	if len(groups) > 0 {
+		last := groups[len(groups)-1]
+		captures = strings.Count(last[len(last)-1], "(?<"+id+"_c")
+	}
+	regex := c.pattern(pattern)
+	if nocase {
+		regex = "(?i)" + regex
+	}
+
+	// 确定动作
+	var action string
+	var useURI bool
+	var code int
+	if value, ok := flags["R"]; ok {
+		code = 302
+		if len(value) > 0 {
+			if code, err = strconv.Atoi(value); err != nil || code < 300 || code > 399 {
+				fail("不支持的重定向状态码 " + value)
+				return
+			}
+		}
+	}
+	switch {
+	case has(flags, "F"):
+		action, useURI = "return 403;", true
+	case has(flags, "G"):
+		action, useURI = "return 410;", true
+	case target == "-":
+		if code != 0 {
+			fail("重定向缺少目标地址")
+			return
+		}
+		if !has(flags, "L") && !has(flags, "END") {
+			// 不做任何改写的规则无需转换
+			for _, cond := range conditions {
+				c.unsupport(cond.line, "所属的 RewriteRule 不做任何改写，已忽略")
+			}
+			return
+		}
+		action, useURI = "break;", true
+	default:
+		if target, err = c.target(target, id+"_c", captures); err != nil {
+			fail(err.Error())
+			return
+		}
+		if code == 0 && (strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://")) {
+			code = 302
+		}
+		switch code {
+		case 0:
+			flag := ""
+			if has(flags, "END") {
+				flag = " break"
+			} else if has(flags, "L") {
+				flag = " last"
+			}
+			action = "rewrite " + quote(regex) + " " + quote(rewriteArgs(target, flags)) + flag + ";"
+		case 301:
+			action = "rewrite " + quote(regex) + " " + quote(rewriteArgs(target, flags)) + " permanent;"
+		case 302:
+			action = "rewrite " + quote(regex) + " " + quote(rewriteArgs(target, flags)) + " redirect;"
+		default:
+			action, useURI = "return "+strconv.Itoa(code)+" "+quote(returnArgs(target, flags))+";", true
+		}
+		// 取反的匹配无法捕获，改为条件判断后整体改写
+		if negate && strings.HasPrefix(action, "rewrite ") {
+			if ruleCapturePattern.MatchString(target) {
+				fail("取反的匹配不能引用捕获组")
+				return
+			}
+			action = strings.Replace(action, quote(regex), "^", 1)
+			useURI = true
+		}
+	}
+
+	// 需要由条件匹配路径时，将匹配作为最后一组条件，以便动作中引用其捕获组
+	if useURI && (negate || !matchesAll(pattern) || ruleCapturePattern.MatchString(action)) {
+		operator := "~"
+		if negate {
+			operator = "!~"
+		}
+		if nocase {
+			operator += "*"
+		}
+		groups = append(groups, []string{"$uri " + operator + " " + quote(c.pattern(pattern))})
+	}
+
+	c.rules++
+	b := block{config: c.wrap(id, setup, groups, action)}
+	if code == 0 && !negate && !useURI && matchesAll(pattern) && isFrontController(conditions) &&
+		!strings.ContainsAny(target, "?$") && !strings.HasPrefix(target, "http") {
+		b.tryFiles = "location " + c.base + "\n{\n    try_files $uri $uri/ " + target + "$is_args$args;\n}"
+	}
+	c.blocks = append(c.blocks, b)
+}
+
+// redirect 转换 mod_alias 的重定向指令
+func (c *converter) redirect(l line, directive string, args []string) {
+	code := 302
+	switch directive {
+	case "redirectpermanent":
+		code = 301
+	case "redirect", "redirectmatch":
+		if len(args) > 0 {
+			if status, ok := redirectStatus[strings.ToLower(args[0])]; ok {
+				code = status
+				args = args[1:]
+			} else if status, err := strconv.Atoi(args[0]); err == nil {
+				code = status
+				args = args[1:]
+			}
+		}
+	}
+
+	if code < 300 || code > 599 || len(args) < 1 || len(args) > 2 || (code < 400) != (len(args) == 2) {
+		c.unsupport(l, "参数错误")
+		return
+	}
+
+	regex := args[0]
+	if directive != "redirectmatch" {
+		if !strings.HasPrefix(regex, "/") {
+			c.unsupport(l, "路径必须以 / 开头")
+			return
+		}
+		if strings.HasSuffix(regex, "/") {
+			regex = "^" + regexp.QuoteMeta(regex) + "(.*)$"
+		} else {
+			regex = "^" + regexp.QuoteMeta(regex) + "(/.*)?$"
+		}
+	}
+
+	var action string
+	switch {
+	case code >= 400:
+		action = "return " + strconv.Itoa(code) + ";"
+	case directive != "redirectmatch" && code == 301:
+		action = "rewrite " + quote(regex) + " " + quote(args[1]+"$1") + " permanent;"
+	case directive != "redirectmatch" && code == 302:
+		action = "rewrite " + quote(regex) + " " + quote(args[1]+"$1") + " redirect;"
+	case code == 301:
+		action = "rewrite " + quote(regex) + " " + quote(args[1]) + " permanent;"
+	case code == 302:
+		action = "rewrite " + quote(regex) + " " + quote(args[1]) + " redirect;"
+	default:
+		target := args[1]
+		if directive != "redirectmatch" {
+			target += "$1"
+		}
+		action = "return " + strconv.Itoa(code) + " " + quote(returnArgs(target, nil)) + ";"
+	}
+	if strings.HasPrefix(action, "rewrite ") {
+		c.blocks = append(c.blocks, block{config: action})
+		return
+	}
+
+	c.blocks = append(c.blocks, block{config: "if ($uri ~ " + quote(regex) + ")\n{\n    " + action + "\n}"})
+}
+
+// condition 转换单个 RewriteCond 为 if 条件表达式
+func (c *converter) condition(cond condition, temp, capture string) ([]string, string, error) {
+	if condCapturePattern.MatchString(cond.test) {
+		return nil, "", errors.New("不支持在条件中引用其他条件的捕获组")
+	}
+
+	pattern, negate := strings.CutPrefix(cond.pattern, "!")
+	_, nocase := cond.flags["NC"]
+	for flag := range cond.flags {
+		if flag != "NC" && flag != "OR" {
+			return nil, "", errors.New("不支持的条件标志 " + flag)
+		}
+	}
+
+	// HTTPS 在 OpenResty 中为 on 或空字符串
+	if strings.EqualFold(cond.test, "%{HTTPS}") {
+		value := strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(pattern, "="), "^"), "$"))
+		switch value {
+		case "on":
+		case "off":
+			negate = !negate
+		default:
+			return nil, "", errors.New("不支持的 HTTPS 条件 " + cond.pattern)
+		}
+		if negate {
+			return nil, `$https != "on"`, nil
+		}
+		return nil, `$https = "on"`, nil
+	}
+
+	test, err := c.variables(cond.test)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// 文件判断
+	switch pattern {
+	case "-f", "-d", "-x":
+		if negate {
+			pattern = "!" + pattern
+		}
+		if !singleVariable.MatchString(test) {
+			test = `"` + test + `"`
+		}
+		return nil, pattern + " " + test, nil
+	case "-s", "-l", "-L", "-h", "-F", "-U":
+		return nil, "", errors.New("不支持的文件判断 " + pattern)
+	}
+
+	var setup []string
+	if !singleVariable.MatchString(test) {
+		setup = append(setup, "set $"+temp+` "`+test+`";`)
+		test = "$" + temp
+	}
+
+	// 字符串比较
+	if value, ok := strings.CutPrefix(pattern, "="); ok {
+		operator := "="
+		if negate {
+			operator = "!="
+		}
+		return setup, test + " " + operator + ` "` + strings.Trim(value, `"`) + `"`, nil
+	}
+	if strings.HasPrefix(pattern, "<") || strings.HasPrefix(pattern, ">") || strings.HasPrefix(pattern, "-") {
+		return nil, "", errors.New("不支持的比较 " + pattern)
+	}
+
+	// 正则匹配
+	operator := "~"
+	if negate {
+		operator = "!~"
+	} else if len(capture) > 0 {
+		pattern = nameCaptures(pattern, capture)
+	}
+	if nocase {
+		operator += "*"
+	}
+
+	return setup, test + " " + operator + " " + quote(pattern), nil
+}
+
+// wrap 生成带条件的配置
+func (c *converter) wrap(id string, setup []string, groups [][]string, action string) string {
+	indent := func(s string) string {
+		return "{\n    " + s + "\n}"
+	}
+
+	var lines []string
+	lines = append(lines, setup...)
+	switch {
+	case len(groups) == 0:
+		lines = append(lines, action)
+	case len(groups) == 1 && len(groups[0]) == 1:
+		lines = append(lines, "if ("+groups[0][0]+")", indent(action))
+	default:
+		// OpenResty 的 if 不支持嵌套和逻辑运算，使用变量记录每组条件的匹配结果
+		var result, expected string
+		for i, group := range groups {
+			variable := id + "_g" + strconv.Itoa(i)
+			lines = append(lines, "set $"+variable+" 0;")
+			for _, expr := range group {
+				lines = append(lines, "if ("+expr+")", indent("set $"+variable+" 1;"))
+			}
+			result += "${" + variable + "}"
+			expected += "1"
+		}
+		lines = append(lines, "set $"+id+` "`+result+`";`, "if ($"+id+` = "`+expected+`")`, indent(action))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// pattern 将目录级的匹配规则转换为完整路径的匹配规则
+func (c *converter) pattern(pattern string) string {
+	if rest, ok := strings.CutPrefix(pattern, "^"); ok {
+		return "^" + regexp.QuoteMeta(c.base) + strings.TrimPrefix(rest, "/")
+	}
+
+	return pattern
+}
+
+// target 转换改写目标
+func (c *converter) target(target, capture string, captures int) (string, error) {
+	target, err := c.variables(target)
+	if err != nil {
+		return "", err
+	}
+	for _, match := range condCapturePattern.FindAllStringSubmatch(target, -1) {
+		if index, _ := strconv.Atoi(match[1]); index < 1 || index > captures {
+			return "", errors.New("引用的条件捕获组 " + match[0] + " 不存在")
+		}
+	}
+	target = condCapturePattern.ReplaceAllString(target, "$${"+capture+"$1}")
+
+	if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") || strings.HasPrefix(target, "/") || strings.HasPrefix(target, "$") {
+		return target, nil
+	}
+
+	return c.base + target, nil
+}
+
+// variables 替换服务器变量
+func (c *converter) variables(s string) (string, error) {
+	var err error
+	result := variablePattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := variablePattern.FindStringSubmatch(match)[1]
+		if header, ok := strings.CutPrefix(name, "HTTP:"); ok {
+			return "$http_" + strings.ReplaceAll(strings.ToLower(header), "-", "_")
+		}
+		if variable, ok := variables[strings.ToUpper(name)]; ok {
+			return variable
+		}
+		err = fmt.Errorf("不支持的变量 %%{%s}", name)
+		return match
+	})
+
+	return result, err
+}
+
+func (c *converter) unsupport(l line, reason string) {
+	c.unsupported = append(c.unsupported, Unsupported{Line: l.number, Content: l.content, Reason: reason})
+}
+
+// splitArgs 按空白分割参数，支持引号和反斜杠转义
+func splitArgs(s string) []string {
+	var args []string
+	var current strings.Builder
+	var quoted, started bool
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch == '\\' && i+1 < len(s) && (s[i+1] == ' ' || s[i+1] == '"'):
+			i++
+			current.WriteByte(s[i])
+			started = true
+		case ch == '"':
+			quoted = !quoted
+			started = true
+		case (ch == ' ' || ch == '\t') && !quoted:
+			if started {
+				args = append(args, current.String())
+				current.Reset()
+				started = false
+			}
+		default:
+			current.WriteByte(ch)
+			started = true
+		}
+	}
+	if started {
+		args = append(args, current.String())
+	}
+
+	return args
+}
+
+// parseFlags 解析 [L,R=301] 形式的标志
+func parseFlags(args []string) (map[string]string, error) {
+	flags := make(map[string]string)
+	if len(args) == 0 {
+		return flags, nil
+	}
+
+	raw := args[0]
+	if !strings.HasPrefix(raw, "[") || !strings.HasSuffix(raw, "]") {
+		return nil, errors.New("标志格式错误 " + raw)
+	}
+	for _, flag := range strings.Split(strings.Trim(raw, "[]"), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(flag), "=")
+		name = strings.ToUpper(name)
+		if alias, ok := flagAliases[name]; ok {
+			name = alias
+		}
+		if len(name) > 0 {
+			flags[name] = value
+		}
+	}
+
+	return flags, nil
+}
+
+// nameCaptures 将正则中的捕获组改为命名捕获组，供后续的 %N 引用
+func nameCaptures(pattern, prefix string) string {
+	var b strings.Builder
+	var class bool
+	index := 0
+	for i := 0; i < len(pattern); i++ {
+		ch := pattern[i]
+		switch {
+		case ch == '\\' && i+1 < len(pattern):
+			b.WriteByte(ch)
+			i++
+			b.WriteByte(pattern[i])
+			continue
+		case ch == '[':
+			class = true
+		case ch == ']':
+			class = false
+		case ch == '(' && !class && (i+1 >= len(pattern) || pattern[i+1] != '?'):
+			index++
+			b.WriteString("(?<" + prefix + strconv.Itoa(index) + ">")
+			continue
+		}
+		b.WriteByte(ch)
+	}
+
+	return b.String()
+}
+
+// rewriteArgs 处理 rewrite 的查询参数，OpenResty 默认会追加原查询参数
+func rewriteArgs(target string, flags map[string]string) string {
+	if strings.HasSuffix(target, "?") {
+		return target
+	}
+	if has(flags, "QSD") || (strings.Contains(target, "?") && !has(flags, "QSA")) {
+		return target + "?"
+	}
+
+	return target
+}
+
+// returnArgs 处理 return 的查询参数，return 不会自动追加原查询参数
+func returnArgs(target string, flags map[string]string) string {
+	if target, ok := strings.CutSuffix(target, "?"); ok {
+		return target
+	}
+	if has(flags, "QSD") {
+		return target
+	}
+	if strings.Contains(target, "?") {
+		if has(flags, "QSA") {
+			return target + "&$args"
+		}
+		return target
+	}
+
+	return target + "$is_args$args"
+}
+
+// matchesAll 判断是否为匹配所有路径的规则
+func matchesAll(pattern string) bool {
+	switch pattern {
+	case "^", ".", ".*", "^.*$", "^(.*)$", "(.*)", "^(.+)$", "^.+$", ".+":
+		return true
+	}
+
+	return false
+}
+
+// isFrontController 判断是否为“文件和目录不存在时交给入口文件”的条件
+func isFrontController(conditions []condition) bool {
+	if len(conditions) != 2 {
+		return false
+	}
+
+	found := make(map[string]bool)
+	for _, cond := range conditions {
+		if !strings.EqualFold(cond.test, "%{REQUEST_FILENAME}") || len(cond.flags) != 0 {
+			return false
+		}
+		found[cond.pattern] = true
+	}
+
+	return found["!-f"] && found["!-d"]
+}
+
+func has(flags map[string]string, flag string) bool {
+	_, ok := flags[flag]
+	return ok
+}
+
+// quote 为包含特殊字符的参数添加引号
+func quote(s string) string {
+	if strings.ContainsAny(s, " \t;{}\"'") {
+		return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+	}
+
+	return s
+}
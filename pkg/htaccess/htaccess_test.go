@@ -0,0 +1,184 @@
+package htaccess
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type HtaccessTestSuite struct {
+	suite.Suite
+}
+
+func TestHtaccessTestSuite(t *testing.T) {
+	suite.Run(t, &HtaccessTestSuite{})
+}
+
+func (s *HtaccessTestSuite) TestWordPress() {
+	result := Convert(`# BEGIN WordPress
+<IfModule mod_rewrite.c>
+RewriteEngine On
+RewriteBase /
+RewriteRule ^index\.php$ - [L]
+RewriteCond %{REQUEST_FILENAME} !-f
+RewriteCond %{REQUEST_FILENAME} !-d
+RewriteRule . /index.php [L]
+</IfModule>
+# END WordPress`)
+
+	s.Empty(result.Unsupported)
+	s.Equal(`if ($uri ~ ^/index\.php$)
+{
+    break;
+}
+location /
+{
+    try_files $uri $uri/ /index.php$is_args$args;
+}
+`, result.Rewrite)
+}
+
+func (s *HtaccessTestSuite) TestFrontControllerNotLast() {
+	result := Convert(`RewriteBase /app/
+RewriteCond %{REQUEST_FILENAME} !-f
+RewriteCond %{REQUEST_FILENAME} !-d
+RewriteRule ^(.*)$ index.php [L]
+RewriteRule ^old$ new [L]`)
+
+	s.Empty(result.Unsupported)
+	s.Equal(`set $htaccess_1_g0 0;
+if (!-f $request_filename)
+{
+    set $htaccess_1_g0 1;
+}
+set $htaccess_1_g1 0;
+if (!-d $request_filename)
+{
+    set $htaccess_1_g1 1;
+}
+set $htaccess_1 "${htaccess_1_g0}${htaccess_1_g1}";
+if ($htaccess_1 = "11")
+{
+    rewrite ^/app/(.*)$ /app/index.php last;
+}
+rewrite ^/app/old$ /app/new last;
+`, result.Rewrite)
+}
+
+func (s *HtaccessTestSuite) TestHttpsAndHost() {
+	result := Convert(`RewriteEngine on
+RewriteCond %{HTTPS} off
+RewriteRule ^(.*)$ https://%{HTTP_HOST}%{REQUEST_URI} [L,R=301]
+RewriteCond %{HTTP_HOST} ^www\.(.+)$ [NC]
+RewriteRule ^ https://%1%{REQUEST_URI} [R=301,L]`)
+
+	s.Empty(result.Unsupported)
+	s.Equal(`if ($https != "on")
+{
+    rewrite ^/(.*)$ https://$http_host$uri permanent;
+}
+if ($http_host ~* ^www\.(?<htaccess_2_c1>.+)$)
+{
+    rewrite ^/ "https://${htaccess_2_c1}$uri" permanent;
+}
+`, result.Rewrite)
+}
+
+func (s *HtaccessTestSuite) TestOrConditions() {
+	result := Convert(`RewriteCond %{HTTP_HOST} ^a\.com$ [OR]
+RewriteCond %{HTTP_HOST} ^b\.com$
+RewriteRule ^(.*)$ index.php?s=/$1 [QSA,L]`)
+
+	s.Empty(result.Unsupported)
+	s.Equal(`set $htaccess_1_g0 0;
+if ($http_host ~ ^a\.com$)
+{
+    set $htaccess_1_g0 1;
+}
+if ($http_host ~ ^b\.com$)
+{
+    set $htaccess_1_g0 1;
+}
+set $htaccess_1 "${htaccess_1_g0}";
+if ($htaccess_1 = "1")
+{
+    rewrite ^/(.*)$ /index.php?s=/$1 last;
+}
+`, result.Rewrite)
+}
+
+func (s *HtaccessTestSuite) TestFlags() {
+	result := Convert(`RewriteRule ^secret - [F,NC]
+RewriteRule ^old/(.*)$ /new/$1 [R=307]
+RewriteRule ^search$ /find?q=1 [L]
+RewriteRule !^static/ - [G]`)
+
+	s.Empty(result.Unsupported)
+	s.Equal(`if ($uri ~* ^/secret)
+{
+    return 403;
+}
+if ($uri ~ ^/old/(.*)$)
+{
+    return 307 /new/$1$is_args$args;
+}
+rewrite ^/search$ /find?q=1? last;
+if ($uri !~ ^/static/)
+{
+    return 410;
+}
+`, result.Rewrite)
+}
+
+func (s *HtaccessTestSuite) TestRedirect() {
+	result := Convert(`Redirect 301 /foo http://example.com/bar
+Redirect /dir/ /other/
+Redirect gone /dead
+RedirectMatch 303 ^/x(\d+)$ /y$1`)
+
+	s.Empty(result.Unsupported)
+	s.Equal(`rewrite ^/foo(/.*)?$ http://example.com/bar$1 permanent;
+rewrite ^/dir/(.*)$ /other/$1 redirect;
+if ($uri ~ ^/dead(/.*)?$)
+{
+    return 410;
+}
+if ($uri ~ ^/x(\d+)$)
+{
+    return 303 /y$1$is_args$args;
+}
+`, result.Rewrite)
+}
+
+func (s *HtaccessTestSuite) TestUnsupported() {
+	result := Convert(`Options -Indexes +ExecCGI
+<Files .env>
+Require all denied
+</Files>
+RewriteRule ^a b [E=X:1]
+RewriteCond %{ENV:X} 1
+RewriteRule ^c d
+RewriteCond %{HTTP_HOST} !^x
+RewriteRule ^ /%1
+RewriteCond %{REQUEST_FILENAME} !-f`)
+
+	s.Empty(result.Rewrite)
+	s.Equal([]Unsupported{
+		{Line: 1, Content: "Options -Indexes +ExecCGI", Reason: "不支持的选项 +ExecCGI"},
+		{Line: 2, Content: "<Files .env>", Reason: "不支持 <Files> 配置块"},
+		{Line: 5, Content: "RewriteRule ^a b [E=X:1]", Reason: "不支持的标志 E"},
+		{Line: 6, Content: "RewriteCond %{ENV:X} 1", Reason: "所属的 RewriteRule 无法转换"},
+		{Line: 7, Content: "RewriteRule ^c d", Reason: "不支持的变量 %{ENV:X}"},
+		{Line: 8, Content: "RewriteCond %{HTTP_HOST} !^x", Reason: "所属的 RewriteRule 无法转换"},
+		{Line: 9, Content: "RewriteRule ^ /%1", Reason: "引用的条件捕获组 %1 不存在"},
+		{Line: 10, Content: "RewriteCond %{REQUEST_FILENAME} !-f", Reason: "RewriteCond 之后缺少 RewriteRule"},
+	}, result.Unsupported)
+}
+
+func (s *HtaccessTestSuite) TestEmptyContinuation() {
+	s.NotPanics(func() {
+		result := Convert("\\\nRewriteEngine On\n\\\n\\")
+		s.Empty(result.Rewrite)
+		s.Empty(result.Unsupported)
+	})
+}
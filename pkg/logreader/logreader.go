@@ -0,0 +1,365 @@
+// Package logreader 从日志文件末尾向前分页读取、搜索和跟踪日志，不会将整个文件载入内存
+package logreader
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	chunkSize    = 64 << 10 // 每次向前读取的块大小
+	maxScanBytes = 64 << 20 // 单次查询最多扫描的字节数，超出后返回下一页偏移
+	maxLineBytes = 16 << 10 // 单行最大返回长度
+	maxPending   = 1000     // 按时间过滤时，无时间戳的续行最多缓存的行数
+)
+
+// Query 查询条件
+type Query struct {
+	Keyword    string    // 关键字
+	Regex      bool      // 关键字是否为正则表达式
+	IgnoreCase bool      // 是否忽略大小写
+	Since      time.Time // 开始时间，零值表示不限
+	Until      time.Time // 结束时间，零值表示不限
+	Before     int64     // 只读取此偏移之前的行，小于等于 0 表示从文件末尾开始
+	Limit      int       // 返回行数
+}
+
+// Line 日志行
+type Line struct {
+	Offset  int64      `json:"offset"`
+	Time    *time.Time `json:"time"`
+	Content string     `json:"content"`
+}
+
+// Page 查询结果，行按文件中的顺序排列
+type Page struct {
+	Lines []Line `json:"lines"`
+	More  bool   `json:"more"` // 是否还有更早的行
+	Next  int64  `json:"next"` // 读取更早的行时作为 Before 传入
+	Size  int64  `json:"size"` // 读取时的文件大小，可作为跟踪的起始偏移
+}
+
+// Matcher 行匹配器
+type Matcher func(line string) bool
+
+// NewMatcher 根据关键字创建行匹配器
+func NewMatcher(keyword string, regex, ignoreCase bool) (Matcher, error) {
+	if len(keyword) == 0 {
+		return func(string) bool { return true }, nil
+	}
+	if regex {
+		if ignoreCase {
+			keyword = "(?i)" + keyword
+		}
+		re, err := regexp.Compile(keyword)
+		if err != nil {
+			return nil, errors.New("正则表达式错误: " + err.Error())
+		}
+		return re.MatchString, nil
+	}
+	if ignoreCase {
+		keyword = strings.ToLower(keyword)
+		return func(line string) bool {
+			return strings.Contains(strings.ToLower(line), keyword)
+		}, nil
+	}
+
+	return func(line string) bool {
+		return strings.Contains(line, keyword)
+	}, nil
+}
+
+// Read 从 Before 开始向前读取满足条件的行
+// 设置了时间范围时，没有时间戳的行（如堆栈信息）归属于它前面最近的带时间戳的行
+func Read(path string, query Query) (Page, error) {
+	match, err := NewMatcher(query.Keyword, query.Regex, query.IgnoreCase)
+	if err != nil {
+		return Page{}, err
+	}
+	if query.Limit <= 0 {
+		query.Limit = 100
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return Page{}, err
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		return Page{}, err
+	}
+
+	page := Page{Size: stat.Size()}
+	end := stat.Size()
+	if query.Before > 0 && query.Before < end {
+		end = query.Before
+	}
+	scanner, err := newBackward(file, end)
+	if err != nil {
+		return Page{}, err
+	}
+
+	timed := !query.Since.IsZero() || !query.Until.IsZero()
+	var result, pending []Line
+	for {
+		if end-scanner.pos() > maxScanBytes {
+			page.More = true
+			page.Next = scanner.offset()
+			break
+		}
+		offset, content, ok, err := scanner.next()
+		if err != nil {
+			return Page{}, err
+		}
+		if !ok {
+			break
+		}
+
+		line := Line{Offset: offset, Content: content}
+		if t, ok := ParseTime(content); ok {
+			line.Time = &t
+		}
+		if !timed {
+			if match(content) {
+				result = append(result, line)
+			}
+			if len(result) >= query.Limit {
+				page.More = offset > 0
+				page.Next = offset
+				break
+			}
+			continue
+		}
+
+		// 时间过滤：续行先缓存，遇到带时间戳的行后整体判断
+		if line.Time == nil {
+			if len(pending) < maxPending {
+				pending = append(pending, line)
+			}
+			continue
+		}
+		if !query.Since.IsZero() && line.Time.Before(query.Since) {
+			// 日志按时间顺序写入，更早的行都不满足条件
+			break
+		}
+		group := append(pending, line)
+		pending = nil
+		if !query.Until.IsZero() && line.Time.After(query.Until) {
+			continue
+		}
+		for _, item := range group {
+			if match(item.Content) {
+				item.Time = line.Time
+				result = append(result, item)
+			}
+		}
+		if len(result) >= query.Limit {
+			page.More = offset > 0
+			page.Next = offset
+			break
+		}
+	}
+
+	// 倒序读取，翻转为文件中的顺序
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	page.Lines = result
+	if page.Lines == nil {
+		page.Lines = []Line{}
+	}
+
+	return page, nil
+}
+
+// Follow 从 offset 开始跟踪文件新写入的行，直到 ctx 结束
+// 文件被截断或轮转后从新文件开头继续读取
+func Follow(ctx context.Context, path string, offset int64, match Matcher, handler func([]Line) error) error {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	var inode uint64
+	if stat, err := os.Stat(path); err == nil {
+		inode = inodeOf(stat)
+		if offset < 0 || offset > stat.Size() {
+			offset = stat.Size()
+		}
+	}
+
+	start := offset
+	var partial []byte
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		stat, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if current := inodeOf(stat); current != inode || stat.Size() < offset {
+			inode = current
+			offset = 0
+			start = 0
+			partial = nil
+		}
+		if stat.Size() == offset {
+			continue
+		}
+
+		data, err := readRange(path, offset, stat.Size())
+		if err != nil {
+			return err
+		}
+		offset += int64(len(data))
+		data = append(partial, data...)
+
+		var lines []Line
+		for {
+			i := bytes.IndexByte(data, '\n')
+			if i < 0 {
+				break
+			}
+			content := trimLine(data[:i])
+			if match == nil || match(content) {
+				line := Line{Offset: start, Content: content}
+				if t, ok := ParseTime(content); ok {
+					line.Time = &t
+				}
+				lines = append(lines, line)
+			}
+			start += int64(i + 1)
+			data = data[i+1:]
+		}
+		// 未写完的行留到下次读取，过长时直接作为一行返回
+		partial = append([]byte(nil), data...)
+		if len(partial) > maxScanBytes {
+			if content := trimLine(partial); match == nil || match(content) {
+				lines = append(lines, Line{Offset: start, Content: content})
+			}
+			start += int64(len(partial))
+			partial = nil
+		}
+
+		if len(lines) > 0 {
+			if err = handler(lines); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// backward 从文件末尾向前逐行读取
+type backward struct {
+	file  *os.File
+	start int64  // buf 在文件中的起始偏移
+	buf   []byte // 尚未返回的数据
+	done  bool
+}
+
+func newBackward(file *os.File, end int64) (*backward, error) {
+	b := &backward{file: file, start: end, done: end == 0}
+	// 忽略文件末尾的换行符
+	if end > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, end-1); err != nil {
+			return nil, err
+		}
+		if last[0] == '\n' {
+			b.start = end - 1
+		}
+	}
+
+	return b, nil
+}
+
+// pos 已读取到的文件位置
+func (b *backward) pos() int64 {
+	return b.start
+}
+
+// offset 下一行结束后的位置
+func (b *backward) offset() int64 {
+	return b.start + int64(len(b.buf))
+}
+
+func (b *backward) next() (int64, string, bool, error) {
+	for {
+		if i := bytes.LastIndexByte(b.buf, '\n'); i >= 0 {
+			line := b.buf[i+1:]
+			b.buf = b.buf[:i]
+			return b.start + int64(i+1), trimLine(line), true, nil
+		}
+		if b.start == 0 {
+			if b.done {
+				return 0, "", false, nil
+			}
+			b.done = true
+			line := b.buf
+			b.buf = nil
+			return 0, trimLine(line), true, nil
+		}
+		// 没有换行符的超长行，读取到 maxScanBytes 后截断返回，避免缓冲区无限增长
+		if len(b.buf) >= maxScanBytes {
+			line := b.buf
+			b.buf = nil
+			return b.start, trimLine(line), true, nil
+		}
+
+		// 长行按已读取的长度加倍读取，减少拼接次数
+		n := min(int64(max(chunkSize, min(len(b.buf), maxScanBytes-len(b.buf)))), b.start)
+		data := make([]byte, n, n+int64(len(b.buf)))
+		if _, err := b.file.ReadAt(data, b.start-n); err != nil && !errors.Is(err, io.EOF) {
+			return 0, "", false, err
+		}
+		b.start -= n
+		b.buf = append(data, b.buf...)
+	}
+}
+
+func readRange(path string, from, to int64) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// 单次最多读取 maxScanBytes，其余留到下次
+	if to-from > maxScanBytes {
+		to = from + maxScanBytes
+	}
+	data := make([]byte, to-from)
+	n, err := file.ReadAt(data, from)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return data[:n], nil
+}
+
+func trimLine(line []byte) string {
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	if len(line) > maxLineBytes {
+		line = line[:maxLineBytes]
+	}
+
+	return string(line)
+}
+
+func inodeOf(stat os.FileInfo) uint64 {
+	if sys, ok := stat.Sys().(*syscall.Stat_t); ok {
+		return sys.Ino
+	}
+
+	return 0
+}
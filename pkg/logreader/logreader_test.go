@@ -0,0 +1,168 @@
+package logreader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LogReaderTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func TestLogReaderTestSuite(t *testing.T) {
+	suite.Run(t, &LogReaderTestSuite{})
+}
+
+func (s *LogReaderTestSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+}
+
+func (s *LogReaderTestSuite) write(name, content string) string {
+	path := filepath.Join(s.dir, name)
+	s.NoError(os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func (s *LogReaderTestSuite) contents(page Page) []string {
+	var lines []string
+	for _, line := range page.Lines {
+		lines = append(lines, line.Content)
+	}
+	return lines
+}
+
+func (s *LogReaderTestSuite) TestPaging() {
+	var builder strings.Builder
+	for i := 1; i <= 100000; i++ {
+		builder.WriteString("line " + strconv.Itoa(i) + "\n")
+	}
+	path := s.write("paging.log", builder.String())
+
+	page, err := Read(path, Query{Limit: 3})
+	s.NoError(err)
+	s.Equal([]string{"line 99998", "line 99999", "line 100000"}, s.contents(page))
+	s.True(page.More)
+
+	page, err = Read(path, Query{Limit: 2, Before: page.Next})
+	s.NoError(err)
+	s.Equal([]string{"line 99996", "line 99997"}, s.contents(page))
+
+	page, err = Read(path, Query{Limit: 5, Before: 14})
+	s.NoError(err)
+	s.Equal([]string{"line 1", "line 2"}, s.contents(page))
+	s.False(page.More)
+}
+
+func (s *LogReaderTestSuite) TestLongLine() {
+	path := s.write("long.log", "first\n"+strings.Repeat("a", maxScanBytes+chunkSize)+"\nlast\n")
+
+	page, err := Read(path, Query{Limit: 10})
+	s.NoError(err)
+	s.Len(page.Lines, 2)
+	s.Len(page.Lines[0].Content, maxLineBytes)
+	s.Equal("last", page.Lines[1].Content)
+	s.True(page.More)
+
+	page, err = Read(path, Query{Limit: 10, Before: page.Next})
+	s.NoError(err)
+	s.Len(page.Lines, 2)
+	s.Equal("first", page.Lines[0].Content)
+	s.NotEmpty(page.Lines[1].Content)
+	s.Empty(strings.Trim(page.Lines[1].Content, "a"))
+	s.False(page.More)
+}
+
+func (s *LogReaderTestSuite) TestSearch() {
+	path := s.write("search.log", "GET /index.php 200\nPOST /login 302\nGET /admin 403\nget /static 200")
+
+	page, err := Read(path, Query{Keyword: "GET"})
+	s.NoError(err)
+	s.Equal([]string{"GET /index.php 200", "GET /admin 403"}, s.contents(page))
+
+	page, err = Read(path, Query{Keyword: "get", IgnoreCase: true})
+	s.NoError(err)
+	s.Len(page.Lines, 3)
+
+	page, err = Read(path, Query{Keyword: `\s(302|403)$`, Regex: true})
+	s.NoError(err)
+	s.Equal([]string{"POST /login 302", "GET /admin 403"}, s.contents(page))
+
+	_, err = Read(path, Query{Keyword: "(", Regex: true})
+	s.Error(err)
+}
+
+func (s *LogReaderTestSuite) TestTimeWindow() {
+	path := s.write("time.log", `[2024-05-20 10:00:00] panel.INFO: start
+[2024-05-20 11:00:00] panel.ERROR: failed
+#0 stack frame
+#1 stack frame
+[2024-05-20 12:00:00] panel.INFO: done
+`)
+
+	page, err := Read(path, Query{
+		Since: time.Date(2024, 5, 20, 10, 30, 0, 0, time.Local),
+		Until: time.Date(2024, 5, 20, 11, 30, 0, 0, time.Local),
+	})
+	s.NoError(err)
+	s.Equal([]string{"[2024-05-20 11:00:00] panel.ERROR: failed", "#0 stack frame", "#1 stack frame"}, s.contents(page))
+	s.Equal(page.Lines[0].Time, page.Lines[2].Time)
+}
+
+func (s *LogReaderTestSuite) TestParseTime() {
+	cases := map[string]time.Time{
+		`1.1.1.1 - - [20/May/2024:10:00:00 +0800] "GET / HTTP/1.1" 200`: time.Date(2024, 5, 20, 2, 0, 0, 0, time.UTC),
+		`2024/05/20 10:00:00 [error] 1#1: *1 open() failed`:             time.Date(2024, 5, 20, 10, 0, 0, 0, time.Local),
+		`[20-May-2024 10:00:00] NOTICE: fpm is running`:                 time.Date(2024, 5, 20, 10, 0, 0, 0, time.Local),
+		`2024-05-20T10:00:00.123456Z 0 [System] [MY-010116] [Server]`:   time.Date(2024, 5, 20, 10, 0, 0, 123456000, time.UTC),
+		`2024-05-20 10:00:00,123 INFO success: app entered RUNNING`:     time.Date(2024, 5, 20, 10, 0, 0, 0, time.Local),
+	}
+	for line, expected := range cases {
+		t, ok := ParseTime(line)
+		s.True(ok, line)
+		s.True(expected.Equal(t), line)
+	}
+
+	_, ok := ParseTime("#0 stack frame")
+	s.False(ok)
+}
+
+func (s *LogReaderTestSuite) TestFollow() {
+	path := s.write("follow.log", "old\n")
+	match, err := NewMatcher("new", false, false)
+	s.NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var received []string
+	done := make(chan error)
+	go func() {
+		done <- Follow(ctx, path, -1, match, func(lines []Line) error {
+			for _, line := range lines {
+				received = append(received, line.Content)
+			}
+			if len(received) >= 2 {
+				cancel()
+			}
+			return nil
+		})
+	}()
+
+	time.Sleep(600 * time.Millisecond)
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	s.NoError(err)
+	_, _ = file.WriteString("new 1\nskip\nnew ")
+	time.Sleep(600 * time.Millisecond)
+	_, _ = file.WriteString("2\n")
+	s.NoError(file.Close())
+
+	s.NoError(<-done)
+	s.Equal([]string{"new 1", "new 2"}, received)
+}
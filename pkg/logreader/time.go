@@ -0,0 +1,58 @@
+package logreader
+
+import (
+	"regexp"
+	"time"
+)
+
+// timeFormats 常见日志的时间格式，按顺序尝试
+var timeFormats = []struct {
+	regex  *regexp.Regexp
+	layout string
+}{
+	// OpenResty 访问日志：[20/May/2024:10:00:00 +0800]
+	{regexp.MustCompile(`\[(\d{2}/[A-Z][a-z]{2}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4})]`), "02/Jan/2006:15:04:05 -0700"},
+	// OpenResty 错误日志：2024/05/20 10:00:00
+	{regexp.MustCompile(`^(\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2})`), "2006/01/02 15:04:05"},
+	// PHP-FPM：[20-May-2024 10:00:00]
+	{regexp.MustCompile(`^\[(\d{2}-[A-Z][a-z]{2}-\d{4} \d{2}:\d{2}:\d{2})`), "02-Jan-2006 15:04:05"},
+	// MySQL：2024-05-20T10:00:00.123456Z
+	{regexp.MustCompile(`^\[?(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2})(?:\.\d+)?(?:Z|[+-]\d{2}:\d{2})`), "2006-01-02T15:04:05"},
+	// 面板、Supervisor、PostgreSQL 等：[2024-05-20 10:00:00]、2024-05-20 10:00:00,123
+	{regexp.MustCompile(`^\[?(\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2})`), "2006-01-02 15:04:05"},
+}
+
+// ParseTime 解析日志行中的时间，没有时区信息的按本地时区处理
+func ParseTime(line string) (time.Time, bool) {
+	for _, format := range timeFormats {
+		match := format.regex.FindStringSubmatch(line)
+		if len(match) < 2 {
+			continue
+		}
+		value := match[1]
+		if format.layout == "2006-01-02 15:04:05" && value[10] == 'T' {
+			value = value[:10] + " " + value[11:]
+		}
+		// MySQL 的时间固定为 UTC
+		location := time.Local
+		if format.layout == "2006-01-02T15:04:05" {
+			if t, err := time.Parse(time.RFC3339Nano, trimBracket(match[0])); err == nil {
+				return t, true
+			}
+			location = time.UTC
+		}
+		if t, err := time.ParseInLocation(format.layout, value, location); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func trimBracket(value string) string {
+	if len(value) > 0 && value[0] == '[' {
+		return value[1:]
+	}
+
+	return value
+}
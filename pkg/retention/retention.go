@@ -0,0 +1,144 @@
+// Package retention 备份保留策略，支持保留最近 N 个以及按天、周、月保留
+package retention
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Policy 保留策略，各规则保留的备份取并集
+type Policy struct {
+	Last    int `json:"last"`    // 保留最近的 N 个
+	Daily   int `json:"daily"`   // 保留最近 N 天每天最新的一个
+	Weekly  int `json:"weekly"`  // 保留最近 N 周每周最新的一个
+	Monthly int `json:"monthly"` // 保留最近 N 个月每月最新的一个
+}
+
+// Item 备份
+type Item struct {
+	Name string    `json:"name"`
+	Time time.Time `json:"time"`
+}
+
+// Decision 备份的处理结果
+type Decision struct {
+	Item
+	Keep    bool     `json:"keep"`
+	Reasons []string `json:"reasons"` // 保留原因，例如 last、daily 2024-05-20
+}
+
+// Parse 解析保留策略，纯数字表示保留最近 N 个，
+// 否则为 last=N,daily=N,weekly=N,monthly=N 格式，未指定的规则为 0
+func Parse(value string) (Policy, error) {
+	value = strings.TrimSpace(value)
+	if n, err := strconv.Atoi(value); err == nil {
+		policy := Policy{Last: n}
+		return policy, policy.Validate()
+	}
+
+	var policy Policy
+	for _, part := range strings.Split(value, ",") {
+		key, raw, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return Policy{}, fmt.Errorf("保留策略 %s 格式错误", part)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return Policy{}, fmt.Errorf("保留策略 %s 格式错误", part)
+		}
+		switch strings.TrimSpace(key) {
+		case "last":
+			policy.Last = n
+		case "daily":
+			policy.Daily = n
+		case "weekly":
+			policy.Weekly = n
+		case "monthly":
+			policy.Monthly = n
+		default:
+			return Policy{}, fmt.Errorf("不支持的保留规则 %s", key)
+		}
+	}
+
+	return policy, policy.Validate()
+}
+
+// String 返回可以被 Parse 解析的字符串，只有 Last 时返回纯数字
+func (p Policy) String() string {
+	if p.Daily == 0 && p.Weekly == 0 && p.Monthly == 0 {
+		return strconv.Itoa(p.Last)
+	}
+
+	return fmt.Sprintf("last=%d,daily=%d,weekly=%d,monthly=%d", p.Last, p.Daily, p.Weekly, p.Monthly)
+}
+
+// Validate 检查策略，不允许负数
+func (p Policy) Validate() error {
+	if p.Last < 0 || p.Daily < 0 || p.Weekly < 0 || p.Monthly < 0 {
+		return errors.New("保留数量不能为负数")
+	}
+
+	return nil
+}
+
+// IsZero 判断是否为空策略，空策略不清理任何备份，兼容保留数量为 0 的定时任务
+func (p Policy) IsZero() bool {
+	return p.Last == 0 && p.Daily == 0 && p.Weekly == 0 && p.Monthly == 0
+}
+
+// Apply 对备份应用保留策略，返回按时间倒序排列的处理结果
+func (p Policy) Apply(items []Item) []Decision {
+	decisions := make([]Decision, len(items))
+	for i, item := range items {
+		decisions[i] = Decision{Item: item}
+	}
+	sort.SliceStable(decisions, func(i, j int) bool {
+		return decisions[i].Time.After(decisions[j].Time)
+	})
+	if p.IsZero() {
+		for i := range decisions {
+			decisions[i].Keep = true
+		}
+		return decisions
+	}
+
+	rules := []struct {
+		name   string
+		count  int
+		bucket func(time.Time) string
+	}{
+		{"last", p.Last, func(t time.Time) string { return strconv.FormatInt(t.UnixNano(), 10) }},
+		{"daily", p.Daily, func(t time.Time) string { return t.Format("2006-01-02") }},
+		{"weekly", p.Weekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}},
+		{"monthly", p.Monthly, func(t time.Time) string { return t.Format("2006-01") }},
+	}
+	for _, rule := range rules {
+		// 每个时间段保留最新的一个，直到达到数量
+		seen := make(map[string]bool)
+		for i := range decisions {
+			if len(seen) >= rule.count {
+				break
+			}
+			bucket := rule.bucket(decisions[i].Time)
+			if seen[bucket] {
+				continue
+			}
+			seen[bucket] = true
+			decisions[i].Keep = true
+			if rule.name == "last" {
+				decisions[i].Reasons = append(decisions[i].Reasons, rule.name)
+			} else {
+				decisions[i].Reasons = append(decisions[i].Reasons, rule.name+" "+bucket)
+			}
+		}
+	}
+
+	return decisions
+}
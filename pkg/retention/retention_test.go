@@ -0,0 +1,100 @@
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RetentionTestSuite struct {
+	suite.Suite
+}
+
+func TestRetentionTestSuite(t *testing.T) {
+	suite.Run(t, &RetentionTestSuite{})
+}
+
+func (s *RetentionTestSuite) TestParse() {
+	policy, err := Parse("5")
+	s.Nil(err)
+	s.Equal(Policy{Last: 5}, policy)
+	s.Equal("5", policy.String())
+
+	policy, err = Parse("last=3, daily=7,weekly=4,monthly=6")
+	s.Nil(err)
+	s.Equal(Policy{Last: 3, Daily: 7, Weekly: 4, Monthly: 6}, policy)
+	s.Equal("last=3,daily=7,weekly=4,monthly=6", policy.String())
+
+	policy, err = Parse("daily=7")
+	s.Nil(err)
+	s.Equal(Policy{Daily: 7}, policy)
+
+	policy, err = Parse("0")
+	s.Nil(err)
+	s.True(policy.IsZero())
+	_, err = Parse("yearly=1")
+	s.Error(err)
+	_, err = Parse("daily=-1")
+	s.Error(err)
+	_, err = Parse("daily")
+	s.Error(err)
+}
+
+func (s *RetentionTestSuite) TestApply() {
+	// 每 12 小时一个备份，共 90 天
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var items []Item
+	for i := 0; i < 180; i++ {
+		t := start.Add(time.Duration(i) * 12 * time.Hour)
+		items = append(items, Item{Name: t.Format("20060102150405"), Time: t})
+	}
+
+	decisions := Policy{Last: 3, Daily: 7, Weekly: 4, Monthly: 3}.Apply(items)
+	s.Len(decisions, len(items))
+	s.Equal("20240330120000", decisions[0].Name)
+
+	kept := make(map[string][]string)
+	for _, decision := range decisions {
+		if decision.Keep {
+			kept[decision.Name] = decision.Reasons
+		}
+	}
+
+	// 最近 3 个，其中最新的一个同时满足所有规则
+	s.Equal([]string{"last", "daily 2024-03-30", "weekly 2024-W13", "monthly 2024-03"}, kept["20240330120000"])
+	s.Equal([]string{"last"}, kept["20240330000000"])
+	s.Equal([]string{"last", "daily 2024-03-29"}, kept["20240329120000"])
+	// 每天保留最新的一个
+	s.Equal([]string{"daily 2024-03-25"}, kept["20240325120000"])
+	s.NotContains(kept, "20240325000000")
+	s.NotContains(kept, "20240323120000")
+	// 每周保留最新的一个，2024-W12 的最后一个备份在周日
+	s.Equal([]string{"daily 2024-03-24", "weekly 2024-W12"}, kept["20240324120000"])
+	s.Equal([]string{"weekly 2024-W10"}, kept["20240310120000"])
+	// 每月保留最新的一个
+	s.Equal([]string{"monthly 2024-02"}, kept["20240229120000"])
+	s.Equal([]string{"monthly 2024-01"}, kept["20240131120000"])
+	s.Len(kept, 3+5+2+2)
+}
+
+func (s *RetentionTestSuite) TestApplyZero() {
+	now := time.Now()
+	decisions := Policy{}.Apply([]Item{
+		{Name: "a", Time: now.Add(-time.Hour)},
+		{Name: "b", Time: now},
+	})
+	s.True(decisions[0].Keep)
+	s.True(decisions[1].Keep)
+}
+
+func (s *RetentionTestSuite) TestApplyFewItems() {
+	now := time.Now()
+	decisions := Policy{Last: 10}.Apply([]Item{
+		{Name: "a", Time: now.Add(-time.Hour)},
+		{Name: "b", Time: now},
+	})
+	s.Equal("b", decisions[0].Name)
+	s.True(decisions[0].Keep)
+	s.True(decisions[1].Keep)
+}
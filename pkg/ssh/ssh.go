@@ -1,6 +1,9 @@
 package ssh
 
 import (
+	"errors"
+	"net"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/ssh"
@@ -21,6 +24,9 @@ type SSHClientConfig struct {
 	User       string
 	Password   string
 	KeyPath    string
+	Key        []byte // 私钥内容，优先于 KeyPath
+	Passphrase string // 私钥密码
+	HostKey    string // 固定的主机公钥 (authorized_keys 格式)，为空时不校验
 	Timeout    time.Duration
 }
 
@@ -50,11 +56,23 @@ func NewSSHClient(conf *SSHClientConfig) (*ssh.Client, error) {
 		User:            conf.User,
 		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
 	}
+	if len(conf.HostKey) > 0 {
+		hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(conf.HostKey))
+		if err != nil {
+			return nil, errors.New("主机公钥格式错误")
+		}
+		config.HostKeyCallback = ssh.FixedHostKey(hostKey)
+		// 只协商固定公钥类型的主机密钥，否则服务器可能提供其他类型的密钥导致校验失败
+		config.HostKeyAlgorithms = []string{hostKey.Type()}
+		if hostKey.Type() == ssh.KeyAlgoRSA {
+			config.HostKeyAlgorithms = []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+		}
+	}
 	switch conf.AuthMethod {
 	case PASSWORD:
 		config.Auth = []ssh.AuthMethod{ssh.Password(conf.Password)}
 	case PUBLICKEY:
-		signer, err := getKey(conf.KeyPath)
+		signer, err := getKey(conf)
 		if err != nil {
 			return nil, err
 		}
@@ -67,11 +85,45 @@ func NewSSHClient(conf *SSHClientConfig) (*ssh.Client, error) {
 	return c, nil
 }
 
-func getKey(keyPath string) (ssh.Signer, error) {
-	key, err := tools.Read(keyPath)
-	if err != nil {
-		return nil, err
+// ScanHostKey 获取主机公钥，返回 authorized_keys 格式的公钥和 SHA256 指纹
+func ScanHostKey(hostAddr string, timeout time.Duration) (string, string, error) {
+	var hostKey ssh.PublicKey
+	config := &ssh.ClientConfig{
+		Timeout: timeout,
+		HostKeyCallback: func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			hostKey = key
+			return nil
+		},
+	}
+
+	// 未提供认证方式，握手在获取主机公钥后会因认证失败而中止
+	c, err := ssh.Dial("tcp", hostAddr, config)
+	if c != nil {
+		_ = c.Close()
+	}
+	if hostKey == nil {
+		if err == nil {
+			err = errors.New("未获取到主机公钥")
+		}
+		return "", "", err
+	}
+
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(hostKey))), ssh.FingerprintSHA256(hostKey), nil
+}
+
+func getKey(conf *SSHClientConfig) (ssh.Signer, error) {
+	key := conf.Key
+	if len(key) == 0 {
+		content, err := tools.Read(conf.KeyPath)
+		if err != nil {
+			return nil, err
+		}
+		key = []byte(content)
+	}
+
+	if len(conf.Passphrase) > 0 {
+		return ssh.ParsePrivateKeyWithPassphrase(key, []byte(conf.Passphrase))
 	}
 
-	return ssh.ParsePrivateKey([]byte(key))
+	return ssh.ParsePrivateKey(key)
 }
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Local 本地目录存储，可用于挂载的网络磁盘
+type Local struct {
+	root string
+}
+
+func NewLocal(root string) (*Local, error) {
+	if !filepath.IsAbs(root) {
+		return nil, errors.New("存储目录必须是绝对路径")
+	}
+
+	return &Local{root: root}, nil
+}
+
+func (s *Local) path(name string) (string, error) {
+	name, err := cleanName(name)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(name)), nil
+}
+
+// Put 先写入临时文件再重命名，避免读取到不完整的备份
+func (s *Local) Put(_ context.Context, name string, reader io.Reader, _ int64) error {
+	file, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(file), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err = io.Copy(temp, reader); err != nil {
+		_ = temp.Close()
+		return err
+	}
+	if err = temp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(temp.Name(), 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(temp.Name(), file)
+}
+
+func (s *Local) Open(_ context.Context, name string) (io.ReadCloser, error) {
+	file, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := os.Open(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotExist
+	}
+
+	return reader, err
+}
+
+func (s *Local) List(_ context.Context, dir string) ([]File, error) {
+	dir = filepath.Join(s.root, filepath.FromSlash(cleanDir(dir)))
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []File{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	files := []File{}
+	for _, entry := range entries {
+		// 跳过隐藏文件，包括上传中的临时文件
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, File{
+			Name:    entry.Name(),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+	}
+
+	return files, nil
+}
+
+func (s *Local) Delete(_ context.Context, name string) error {
+	file, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotExist
+	}
+
+	return err
+}
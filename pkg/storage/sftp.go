@@ -0,0 +1,275 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	cryptossh "golang.org/x/crypto/ssh"
+
+	"panel/pkg/ssh"
+)
+
+// sftpPartSuffix 上传中的文件后缀，上传完成后重命名
+const sftpPartSuffix = ".part"
+
+// sftpRetries 上传失败时的最大重试次数，重试时从已上传的位置继续
+const sftpRetries = 3
+
+// SFTP 通过 SSH 连接的远程服务器目录
+type SFTP struct {
+	config *ssh.SSHClientConfig
+	root   string
+}
+
+func NewSFTP(config Config) (*SFTP, error) {
+	if config.Host == "" || config.User == "" {
+		return nil, errors.New("主机和用户名不能为空")
+	}
+	if !path.IsAbs(config.Path) {
+		return nil, errors.New("远程目录必须是绝对路径")
+	}
+	if config.HostKey == "" {
+		return nil, errors.New("主机公钥不能为空")
+	}
+	if _, _, _, _, err := cryptossh.ParseAuthorizedKey([]byte(config.HostKey)); err != nil {
+		return nil, errors.New("主机公钥格式错误")
+	}
+	if config.Port == 0 {
+		config.Port = 22
+	}
+
+	addr := net.JoinHostPort(config.Host, strconv.Itoa(int(config.Port)))
+	var clientConfig *ssh.SSHClientConfig
+	switch {
+	case config.PrivateKey != "":
+		if err := checkPrivateKey(config.PrivateKey, config.Passphrase); err != nil {
+			return nil, err
+		}
+		clientConfig = ssh.SSHClientConfigPulicKey(addr, config.User, "")
+		clientConfig.Key = []byte(config.PrivateKey)
+		clientConfig.Passphrase = config.Passphrase
+	case config.Password != "":
+		clientConfig = ssh.SSHClientConfigPassword(addr, config.User, config.Password)
+	default:
+		return nil, errors.New("密码和私钥不能同时为空")
+	}
+	clientConfig.HostKey = config.HostKey
+	clientConfig.Timeout = 10 * time.Second
+
+	return &SFTP{config: clientConfig, root: path.Clean(config.Path)}, nil
+}
+
+func checkPrivateKey(key, passphrase string) error {
+	var err error
+	if passphrase != "" {
+		_, err = cryptossh.ParsePrivateKeyWithPassphrase([]byte(key), []byte(passphrase))
+	} else {
+		_, err = cryptossh.ParsePrivateKey([]byte(key))
+	}
+	if err != nil {
+		return errors.New("私钥格式错误或私钥密码错误")
+	}
+
+	return nil
+}
+
+// sftpClient 关闭时同时关闭 SSH 连接
+type sftpClient struct {
+	*sftp.Client
+	conn *cryptossh.Client
+}
+
+func (c *sftpClient) Close() error {
+	_ = c.Client.Close()
+	return c.conn.Close()
+}
+
+func (s *SFTP) connect() (*sftpClient, error) {
+	conn, err := ssh.NewSSHClient(s.config)
+	if err != nil {
+		return nil, err
+	}
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return &sftpClient{Client: client, conn: conn}, nil
+}
+
+func (s *SFTP) path(name string) (string, error) {
+	name, err := cleanName(name)
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(s.root, name), nil
+}
+
+// Put 先上传到 .part 文件再重命名，连接中断时重试并从已上传的位置继续
+//
+// 首次上传时覆盖已有的 .part 文件，它可能是其他备份或中断的旧上传留下的
+func (s *SFTP) Put(ctx context.Context, name string, reader io.Reader, size int64) error {
+	file, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	// 只有可以定位的输入才能续传
+	seeker, _ := reader.(io.Seeker)
+	for attempt := 1; ; attempt++ {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		err = s.put(file, reader, seeker, size, attempt > 1)
+		if err == nil || seeker == nil || attempt >= sftpRetries {
+			return err
+		}
+	}
+}
+
+func (s *SFTP) put(file string, reader io.Reader, seeker io.Seeker, size int64, resume bool) error {
+	client, err := s.connect()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err = client.MkdirAll(path.Dir(file)); err != nil {
+		return err
+	}
+
+	part := file + sftpPartSuffix
+	var offset int64
+	if seeker != nil && resume {
+		if info, err := client.Stat(part); err == nil && info.Size() <= size {
+			offset = info.Size()
+		}
+		if _, err = seeker.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE
+	if offset == 0 {
+		flags |= os.O_TRUNC
+	}
+	remote, err := client.OpenFile(part, flags)
+	if err != nil {
+		return err
+	}
+	if _, err = remote.Seek(offset, io.SeekStart); err != nil {
+		_ = remote.Close()
+		return err
+	}
+	if _, err = remote.ReadFrom(reader); err != nil {
+		_ = remote.Close()
+		return err
+	}
+	if err = remote.Close(); err != nil {
+		return err
+	}
+	if err = client.Chmod(part, 0600); err != nil {
+		return err
+	}
+
+	// 不支持 posix-rename 扩展的服务器在目标存在时无法重命名，先删除目标
+	if err = client.PosixRename(part, file); err != nil {
+		_ = client.Remove(file)
+		return client.Rename(part, file)
+	}
+
+	return nil
+}
+
+// sftpFile 关闭文件时同时关闭连接
+type sftpFile struct {
+	*sftp.File
+	client *sftpClient
+}
+
+func (f *sftpFile) Close() error {
+	_ = f.File.Close()
+	return f.client.Close()
+}
+
+func (s *SFTP) Open(_ context.Context, name string) (io.ReadCloser, error) {
+	file, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := s.connect()
+	if err != nil {
+		return nil, err
+	}
+	reader, err := client.Open(file)
+	if err != nil {
+		_ = client.Close()
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotExist
+		}
+		return nil, err
+	}
+
+	return &sftpFile{File: reader, client: client}, nil
+}
+
+func (s *SFTP) List(_ context.Context, dir string) ([]File, error) {
+	client, err := s.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	entries, err := client.ReadDir(path.Join(s.root, cleanDir(dir)))
+	if errors.Is(err, os.ErrNotExist) {
+		return []File{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	files := []File{}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), sftpPartSuffix) {
+			continue
+		}
+		files = append(files, File{
+			Name:    entry.Name(),
+			Size:    entry.Size(),
+			ModTime: entry.ModTime(),
+		})
+	}
+
+	return files, nil
+}
+
+func (s *SFTP) Delete(_ context.Context, name string) error {
+	file, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	client, err := s.connect()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	err = client.Remove(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotExist
+	}
+
+	return err
+}
@@ -0,0 +1,128 @@
+// Package storage 备份存储，支持本地目录、S3 兼容的对象存储和 SFTP
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// Type 存储类型
+type Type string
+
+const (
+	TypeLocal Type = "local"
+	TypeS3    Type = "s3"
+	TypeSFTP  Type = "sftp"
+)
+
+// Config 存储配置，不同类型使用不同字段
+type Config struct {
+	// 本地目录，SFTP 时为远程目录
+	Path string `json:"path,omitempty"`
+
+	// S3 兼容的对象存储
+	Endpoint  string `json:"endpoint,omitempty"` // 为空时使用 AWS S3
+	Region    string `json:"region,omitempty"`
+	Bucket    string `json:"bucket,omitempty"`
+	Prefix    string `json:"prefix,omitempty"`
+	PathStyle bool   `json:"path_style,omitempty"` // 使用路径风格访问，MinIO 等通常需要开启
+	AccessKey string `json:"access_key,omitempty"`
+	SecretKey string `json:"secret_key,omitempty"`
+
+	// SFTP
+	Host       string `json:"host,omitempty"`
+	Port       uint   `json:"port,omitempty"`
+	User       string `json:"user,omitempty"`
+	Password   string `json:"password,omitempty"`
+	PrivateKey string `json:"private_key,omitempty"`
+	Passphrase string `json:"passphrase,omitempty"` // 私钥密码
+	HostKey    string `json:"host_key,omitempty"`   // 固定的主机公钥 (authorized_keys 格式)，连接时校验
+}
+
+// Mask 列表中代替已配置的密钥类字段，保存时为 Mask 的字段保留原值
+const Mask = "******"
+
+// Masked 返回隐藏密钥类字段的配置
+func (c Config) Masked() Config {
+	for _, field := range c.secrets() {
+		if len(*field) > 0 {
+			*field = Mask
+		}
+	}
+
+	return c
+}
+
+// Unmask 将为 Mask 的密钥类字段还原为 old 中的值
+func (c Config) Unmask(old Config) Config {
+	olds := old.secrets()
+	for i, field := range c.secrets() {
+		if *field == Mask {
+			*field = *olds[i]
+		}
+	}
+
+	return c
+}
+
+// secrets 密钥类字段
+func (c *Config) secrets() []*string {
+	return []*string{&c.SecretKey, &c.Password, &c.PrivateKey, &c.Passphrase}
+}
+
+// File 存储中的文件
+type File struct {
+	Name    string    `json:"name"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// Storage 备份存储，name 为以 / 分隔的相对路径
+type Storage interface {
+	// Put 上传文件，已存在时覆盖
+	Put(ctx context.Context, name string, reader io.Reader, size int64) error
+	// Open 读取文件
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+	// List 列出目录下的文件，不包含子目录
+	List(ctx context.Context, dir string) ([]File, error)
+	// Delete 删除文件
+	Delete(ctx context.Context, name string) error
+}
+
+// ErrNotExist 文件不存在
+var ErrNotExist = errors.New("文件不存在")
+
+// New 根据类型创建存储
+func New(typ Type, config Config) (Storage, error) {
+	switch typ {
+	case TypeLocal:
+		return NewLocal(config.Path)
+	case TypeS3:
+		return NewS3(config)
+	case TypeSFTP:
+		return NewSFTP(config)
+	}
+
+	return nil, fmt.Errorf("不支持的存储类型 %s", typ)
+}
+
+// cleanName 规范化文件名，禁止访问存储根目录之外的路径
+func cleanName(name string) (string, error) {
+	name = path.Clean("/" + strings.ReplaceAll(name, "\\", "/"))
+	name = strings.TrimPrefix(name, "/")
+	if name == "" || name == "." {
+		return "", errors.New("文件名不能为空")
+	}
+
+	return name, nil
+}
+
+// cleanDir 规范化目录，空字符串表示存储根目录，禁止访问存储根目录之外的路径
+func cleanDir(dir string) string {
+	return strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(dir, "\\", "/")), "/")
+}
@@ -0,0 +1,323 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/xml"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/ssh"
+)
+
+type StorageTestSuite struct {
+	suite.Suite
+	server     *httptest.Server
+	sftpServer net.Listener
+	hostKey    string
+}
+
+func TestStorageTestSuite(t *testing.T) {
+	suite.Run(t, &StorageTestSuite{})
+}
+
+func (s *StorageTestSuite) SetupSuite() {
+	s.server = httptest.NewServer(newFakeS3("backup"))
+
+	listener, hostKey, err := newSFTPServer("panel", "secret")
+	s.Require().Nil(err)
+	s.sftpServer = listener
+	s.hostKey = hostKey
+}
+
+func (s *StorageTestSuite) TearDownSuite() {
+	s.server.Close()
+	_ = s.sftpServer.Close()
+}
+
+func (s *StorageTestSuite) TestLocal() {
+	storage, err := New(TypeLocal, Config{Path: s.T().TempDir()})
+	s.Require().Nil(err)
+	s.run(storage)
+
+	_, err = NewLocal("relative")
+	s.Error(err)
+}
+
+func (s *StorageTestSuite) TestS3() {
+	storage, err := New(TypeS3, Config{
+		Endpoint:  s.server.URL,
+		Bucket:    "backup",
+		Prefix:    "/panel/",
+		PathStyle: true,
+		AccessKey: "access",
+		SecretKey: "secret",
+	})
+	s.Require().Nil(err)
+	s.run(storage)
+
+	_, err = New(TypeS3, Config{})
+	s.Error(err)
+}
+
+func (s *StorageTestSuite) TestSFTP() {
+	root := s.T().TempDir()
+	config := s.sftpConfig(root)
+	storage, err := New(TypeSFTP, config)
+	s.Require().Nil(err)
+	s.run(storage)
+
+	// 首次上传时覆盖遗留的 .part 文件，不从中继续
+	s.Require().Nil(os.MkdirAll(filepath.Join(root, "mysql"), 0755))
+	s.Require().Nil(os.WriteFile(filepath.Join(root, "mysql", "d.zip"+sftpPartSuffix), []byte("zz"), 0600))
+	s.Nil(storage.Put(context.Background(), "mysql/d.zip", strings.NewReader("abcd"), 4))
+	data, err := os.ReadFile(filepath.Join(root, "mysql", "d.zip"))
+	s.Nil(err)
+	s.Equal("abcd", string(data))
+	s.NoFileExists(filepath.Join(root, "mysql", "d.zip"+sftpPartSuffix))
+
+	// 主机公钥不匹配时拒绝连接
+	otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+	s.Require().Nil(err)
+	otherPublicKey, err := ssh.NewPublicKey(otherKey)
+	s.Require().Nil(err)
+	config.HostKey = string(ssh.MarshalAuthorizedKey(otherPublicKey))
+	storage, err = New(TypeSFTP, config)
+	s.Require().Nil(err)
+	_, err = storage.List(context.Background(), "website")
+	s.Error(err)
+
+	config.HostKey = ""
+	_, err = New(TypeSFTP, config)
+	s.Error(err)
+}
+
+func (s *StorageTestSuite) TestMask() {
+	config := Config{Bucket: "backup", AccessKey: "id", SecretKey: "secret", Password: ""}
+	masked := config.Masked()
+	s.Equal("backup", masked.Bucket)
+	s.Equal("id", masked.AccessKey)
+	s.Equal(Mask, masked.SecretKey)
+	s.Empty(masked.Password)
+	s.Equal("secret", config.SecretKey)
+
+	masked.Bucket = "other"
+	s.Equal(Config{Bucket: "other", AccessKey: "id", SecretKey: "secret"}, masked.Unmask(config))
+	masked.SecretKey = "new"
+	s.Equal("new", masked.Unmask(config).SecretKey)
+}
+
+func (s *StorageTestSuite) sftpConfig(root string) Config {
+	host, port, _ := net.SplitHostPort(s.sftpServer.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	return Config{
+		Path:     root,
+		Host:     host,
+		Port:     uint(portNumber),
+		User:     "panel",
+		Password: "secret",
+		HostKey:  s.hostKey,
+	}
+}
+
+func (s *StorageTestSuite) run(storage Storage) {
+	ctx := context.Background()
+
+	s.Nil(storage.Put(ctx, "website/a.zip", strings.NewReader("aaa"), 3))
+	s.Nil(storage.Put(ctx, "website/b.zip", strings.NewReader("bbbb"), 4))
+	s.Nil(storage.Put(ctx, "mysql/c.zip", strings.NewReader("c"), 1))
+	// 路径穿越会被限制在存储根目录内
+	s.Nil(storage.Put(ctx, "../website/a.zip", strings.NewReader("new"), 3))
+
+	files, err := storage.List(ctx, "website")
+	s.Require().Nil(err)
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	s.Require().Len(files, 2)
+	s.Equal("a.zip", files[0].Name)
+	s.Equal(int64(3), files[0].Size)
+	s.Equal("b.zip", files[1].Name)
+	files, err = storage.List(ctx, "../website")
+	s.Nil(err)
+	s.Len(files, 2)
+
+	reader, err := storage.Open(ctx, "website/a.zip")
+	s.Require().Nil(err)
+	data, _ := io.ReadAll(reader)
+	_ = reader.Close()
+	s.Equal("new", string(data))
+
+	_, err = storage.Open(ctx, "website/none.zip")
+	s.ErrorIs(err, ErrNotExist)
+
+	s.Nil(storage.Delete(ctx, "website/a.zip"))
+	files, err = storage.List(ctx, "website")
+	s.Nil(err)
+	s.Len(files, 1)
+
+	files, err = storage.List(ctx, "postgresql")
+	s.Nil(err)
+	s.Empty(files)
+}
+
+// fakeS3 最小化的 S3 兼容服务，只支持测试用到的路径风格接口
+type fakeS3 struct {
+	bucket  string
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newFakeS3(bucket string) *fakeS3 {
+	return &fakeS3{bucket: bucket, objects: make(map[string][]byte)}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != f.bucket {
+		f.error(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		f.list(w, r.URL.Query().Get("prefix"), r.URL.Query().Get("delimiter"))
+	case r.Method == http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[key] = data
+		w.Header().Set("ETag", `"etag"`)
+	case r.Method == http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			f.error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		_, _ = io.Copy(w, bytes.NewReader(data))
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		f.error(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, prefix, delimiter string) {
+	type content struct {
+		Key          string
+		Size         int64
+		LastModified string
+	}
+	result := struct {
+		XMLName     xml.Name `xml:"ListBucketResult"`
+		Name        string
+		Prefix      string
+		IsTruncated bool
+		Contents    []content
+	}{Name: f.bucket, Prefix: prefix}
+
+	for key, data := range f.objects {
+		rest, ok := strings.CutPrefix(key, prefix)
+		if !ok || (delimiter != "" && strings.Contains(rest, delimiter)) {
+			continue
+		}
+		result.Contents = append(result.Contents, content{
+			Key:          key,
+			Size:         int64(len(data)),
+			LastModified: time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(result)
+}
+
+func (f *fakeS3) error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte("<Error><Code>" + code + "</Code></Error>"))
+}
+
+// newSFTPServer 启动只接受密码认证的 SFTP 服务，返回监听器和 authorized_keys 格式的主机公钥
+func newSFTPServer(user, password string) (net.Listener, string, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, "", err
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if conn.User() == user && string(pass) == password {
+				return nil, nil
+			}
+			return nil, io.ErrUnexpectedEOF
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, "", err
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSFTP(conn, config)
+		}
+	}()
+
+	return listener, string(ssh.MarshalAuthorizedKey(signer.PublicKey())), nil
+}
+
+func serveSFTP(conn net.Conn, config *ssh.ServerConfig) {
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go func() {
+			for request := range requests {
+				ok := request.Type == "subsystem" && string(request.Payload[4:]) == "sftp"
+				_ = request.Reply(ok, nil)
+				if ok {
+					server, err := sftp.NewServer(channel)
+					if err == nil {
+						_ = server.Serve()
+					}
+					_ = channel.Close()
+				}
+			}
+		}()
+	}
+}
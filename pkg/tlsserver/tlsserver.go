@@ -0,0 +1,205 @@
+// Package tlsserver 面板 HTTPS 服务，支持证书热更新和 HTTP 跳转 HTTPS
+package tlsserver
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Config HTTPS 服务配置
+type Config struct {
+	Addr     string
+	CertFile string
+	KeyFile  string
+	Redirect bool // 同一端口收到 HTTP 请求时跳转到 HTTPS
+}
+
+// Serve 启动 HTTPS 服务，证书文件更新后无需重启即可生效
+func Serve(config Config, handler http.Handler) error {
+	listener, err := net.Listen("tcp", config.Addr)
+	if err != nil {
+		return err
+	}
+
+	return serve(listener, config, handler)
+}
+
+func serve(listener net.Listener, config Config, handler http.Handler) error {
+	loader := NewCertLoader(config.CertFile, config.KeyFile)
+	if _, err := loader.GetCertificate(nil); err != nil {
+		return err
+	}
+
+	if config.Redirect {
+		listener = newRedirectListener(listener)
+	}
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 30 * time.Second,
+		TLSConfig: &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: loader.GetCertificate,
+		},
+	}
+
+	return server.ServeTLS(listener, "", "")
+}
+
+// CertLoader 按需加载证书，文件修改后自动重新加载
+type CertLoader struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+func NewCertLoader(certFile, keyFile string) *CertLoader {
+	return &CertLoader{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+}
+
+// GetCertificate 获取当前证书，新证书加载失败时继续使用旧证书
+func (l *CertLoader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	modTime := l.modTime
+	for _, file := range []string{l.certFile, l.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			if l.cert != nil {
+				return l.cert, nil
+			}
+			return nil, err
+		}
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+	if l.cert != nil && !modTime.After(l.modTime) {
+		return l.cert, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(l.certFile, l.keyFile)
+	if err != nil {
+		if l.cert != nil {
+			return l.cert, nil
+		}
+		return nil, err
+	}
+	l.cert = &cert
+	l.modTime = modTime
+
+	return l.cert, nil
+}
+
+// redirectListener 识别连接的第一个字节，TLS 连接交给 HTTPS 服务，其余按 HTTP 请求处理并跳转到 HTTPS
+type redirectListener struct {
+	net.Listener
+	conns  chan net.Conn
+	errs   chan error
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newRedirectListener(listener net.Listener) *redirectListener {
+	l := &redirectListener{
+		Listener: listener,
+		conns:    make(chan net.Conn),
+		errs:     make(chan error, 1),
+		closed:   make(chan struct{}),
+	}
+	go l.accept()
+
+	return l
+}
+
+func (l *redirectListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case err := <-l.errs:
+		return nil, err
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *redirectListener) Close() error {
+	l.once.Do(func() {
+		close(l.closed)
+	})
+
+	return l.Listener.Close()
+}
+
+func (l *redirectListener) accept() {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			l.errs <- err
+			return
+		}
+		go l.classify(conn)
+	}
+}
+
+func (l *redirectListener) classify(conn net.Conn) {
+	_ = conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	reader := bufio.NewReader(conn)
+	first, err := reader.Peek(1)
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+
+	// 0x16 为 TLS 握手记录
+	if first[0] == 0x16 {
+		select {
+		case l.conns <- &peekedConn{Conn: conn, reader: reader}:
+		case <-l.closed:
+			_ = conn.Close()
+		}
+		return
+	}
+
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+	request, err := http.ReadRequest(reader)
+	if err != nil {
+		return
+	}
+	response := &http.Response{
+		StatusCode: http.StatusPermanentRedirect,
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Location": {"https://" + request.Host + request.URL.RequestURI()}},
+		Close:      true,
+	}
+	_ = response.Write(conn)
+}
+
+// peekedConn 读取时先返回已预读的数据
+type peekedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *peekedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
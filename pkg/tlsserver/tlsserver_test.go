@@ -0,0 +1,108 @@
+package tlsserver
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"panel/pkg/acme"
+)
+
+type TLSServerTestSuite struct {
+	suite.Suite
+	dir      string
+	ca       acme.CA
+	listener net.Listener
+}
+
+func TestTLSServerTestSuite(t *testing.T) {
+	suite.Run(t, &TLSServerTestSuite{})
+}
+
+func (s *TLSServerTestSuite) SetupSuite() {
+	s.dir = s.T().TempDir()
+	ca, err := acme.GenerateCA("HaoZi", acme.KeyEC256, 1)
+	s.Require().Nil(err)
+	s.ca = ca
+	s.writeCert("haozi.dev")
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().Nil(err)
+	s.listener = listener
+	go func() {
+		_ = serve(listener, Config{
+			CertFile: filepath.Join(s.dir, "ssl.crt"),
+			KeyFile:  filepath.Join(s.dir, "ssl.key"),
+			Redirect: true,
+		}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		}))
+	}()
+}
+
+func (s *TLSServerTestSuite) TearDownSuite() {
+	_ = s.listener.Close()
+}
+
+func (s *TLSServerTestSuite) writeCert(domain string) {
+	cert, key, _, err := s.ca.Issue(acme.IssueRequest{
+		Domains: []string{domain},
+		Usage:   acme.UsageServer,
+		Days:    1,
+		KeyType: acme.KeyEC256,
+	})
+	s.Require().Nil(err)
+	s.Require().Nil(os.WriteFile(filepath.Join(s.dir, "ssl.crt"), cert, 0644))
+	s.Require().Nil(os.WriteFile(filepath.Join(s.dir, "ssl.key"), key, 0600))
+}
+
+func (s *TLSServerTestSuite) peerCert() *x509.Certificate {
+	conn, err := tls.Dial("tcp", s.listener.Addr().String(), &tls.Config{InsecureSkipVerify: true})
+	s.Require().Nil(err)
+	defer conn.Close()
+
+	return conn.ConnectionState().PeerCertificates[0]
+}
+
+func (s *TLSServerTestSuite) TestHTTPS() {
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	resp, err := client.Get("https://" + s.listener.Addr().String() + "/")
+	s.Require().Nil(err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	s.Equal("ok", string(body))
+}
+
+func (s *TLSServerTestSuite) TestRedirect() {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get("http://" + s.listener.Addr().String() + "/login?a=1")
+	s.Require().Nil(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusPermanentRedirect, resp.StatusCode)
+	s.Equal("https://"+s.listener.Addr().String()+"/login?a=1", resp.Header.Get("Location"))
+}
+
+func (s *TLSServerTestSuite) TestReload() {
+	s.Contains(s.peerCert().DNSNames, "haozi.dev")
+
+	s.writeCert("panel.haozi.dev")
+	future := time.Now().Add(time.Minute)
+	s.Require().Nil(os.Chtimes(filepath.Join(s.dir, "ssl.crt"), future, future))
+	s.Contains(s.peerCert().DNSNames, "panel.haozi.dev")
+
+	// 证书文件损坏时继续使用旧证书
+	s.Require().Nil(os.WriteFile(filepath.Join(s.dir, "ssl.crt"), []byte("invalid"), 0644))
+	future = future.Add(time.Minute)
+	s.Require().Nil(os.Chtimes(filepath.Join(s.dir, "ssl.crt"), future, future))
+	s.Contains(s.peerCert().DNSNames, "panel.haozi.dev")
+}
@@ -42,11 +42,14 @@ func Api() {
 			r.Get("backupList", websiteController.BackupList)
 			r.Put("uploadBackup", websiteController.UploadBackup)
 			r.Delete("deleteBackup", websiteController.DeleteBackup)
+			r.Post("snapshots/prune", websiteController.PruneSnapshots)
 		})
 		r.Prefix("websites").Middleware(middleware.Jwt(), middleware.MustInstall()).Group(func(r route.Router) {
 			websiteController := controllers.NewWebsiteController()
 			r.Get("/", websiteController.List)
 			r.Post("/", websiteController.Add)
+			r.Post("batch", websiteController.Batch)
+			r.Post("import", websiteController.Import)
 			r.Delete("{id}", websiteController.Delete)
 			r.Get("{id}/config", websiteController.GetConfig)
 			r.Post("{id}/config", websiteController.SaveConfig)
@@ -54,8 +57,37 @@ func Api() {
 			r.Post("{id}/updateRemark", websiteController.UpdateRemark)
 			r.Post("{id}/createBackup", websiteController.CreateBackup)
 			r.Post("{id}/restoreBackup", websiteController.RestoreBackup)
+			r.Get("{id}/snapshots", websiteController.Snapshots)
+			r.Post("{id}/snapshots", websiteController.CreateSnapshot)
+			r.Post("{id}/snapshots/{snapshot}/restore", websiteController.RestoreSnapshot)
+			r.Delete("{id}/snapshots/{snapshot}", websiteController.DeleteSnapshot)
 			r.Post("{id}/resetConfig", websiteController.ResetConfig)
 			r.Post("{id}/status", websiteController.Status)
+			r.Post("{id}/htaccess/convert", websiteController.ConvertHtaccess)
+			r.Post("{id}/rewrite", websiteController.SaveRewrite)
+			r.Post("{id}/export", websiteController.Export)
+			r.Get("{id}/phpPool", websiteController.GetPhpPool)
+			r.Post("{id}/phpPool", websiteController.SavePhpPool)
+			r.Delete("{id}/phpPool", websiteController.DeletePhpPool)
+			r.Get("{id}/inspectCert", websiteController.InspectCert)
+
+			deployController := controllers.NewDeployController()
+			r.Get("{id}/deploy", deployController.GetConfig)
+			r.Post("{id}/deploy", deployController.SaveConfig)
+			r.Post("{id}/deploy/token", deployController.ResetToken)
+			r.Post("{id}/deploy/trigger", deployController.Trigger)
+			r.Get("{id}/deploy/releases", deployController.Releases)
+			r.Post("{id}/deploy/rollback", deployController.Rollback)
+		})
+		r.Prefix("webhook").Middleware(middleware.MustInstall()).Group(func(r route.Router) {
+			deployController := controllers.NewDeployController()
+			r.Post("deploy/{token}", deployController.Webhook)
+		})
+		r.Prefix("cert").Group(func(r route.Router) {
+			// 根证书和吊销列表需要能被客户端直接获取，作为 CRL 分发地址使用
+			certController := controllers.NewCertController()
+			r.Get("cas/{id}/root", certController.CARoot)
+			r.Get("cas/{id}/crl", certController.CACRL)
 		})
 		r.Prefix("cert").Middleware(middleware.Jwt()).Group(func(r route.Router) {
 			certController := controllers.NewCertController()
@@ -67,6 +99,8 @@ func Api() {
 			r.Put("users/{id}", certController.UserUpdate)
 			r.Get("users/{id}", certController.UserShow)
 			r.Delete("users/{id}", certController.UserDestroy)
+			r.Post("users/{id}/deactivate", certController.UserDeactivate)
+			r.Get("users/{id}/account", certController.UserAccount)
 			r.Get("dns", certController.DNSList)
 			r.Post("dns", certController.DNSStore)
 			r.Put("dns/{id}", certController.DNSUpdate)
@@ -77,11 +111,48 @@ func Api() {
 			r.Put("certs/{id}", certController.CertUpdate)
 			r.Get("certs/{id}", certController.CertShow)
 			r.Delete("certs/{id}", certController.CertDestroy)
+			r.Get("certs/{id}/deployments", certController.Deployments)
+			r.Delete("certs/{id}/deployments/{website_id}", certController.Undeploy)
+			r.Get("certs/{id}/targets", certController.TargetList)
+			r.Post("certs/{id}/targets", certController.TargetStore)
+			r.Put("targets/{id}", certController.TargetUpdate)
+			r.Delete("targets/{id}", certController.TargetDestroy)
+			r.Post("targets/{id}/deploy", certController.TargetDeploy)
+			r.Post("certs/{id}/revoke", certController.Revoke)
+			r.Get("certs/{id}/inspect", certController.Inspect)
+			r.Get("certs/{id}/task", certController.TaskStatus)
+			r.Post("csr", certController.CSRStore)
+			r.Get("certs/{id}/csr", certController.CSR)
+			r.Post("certs/{id}/import", certController.Import)
+			r.Post("certs/{id}/download", certController.Download)
+			r.Get("cas", certController.CAList)
+			r.Post("cas", certController.CAStore)
+			r.Delete("cas/{id}", certController.CADestroy)
+			r.Post("cas/{id}/issue", certController.CAIssue)
+			r.Get("cas/{id}/crl/pem", certController.CACRLPEM)
 			r.Post("obtain", certController.Obtain)
 			r.Post("renew", certController.Renew)
 			r.Post("manualDNS", certController.ManualDNS)
 			r.Post("deploy", certController.Deploy)
 		})
+		r.Prefix("backup").Middleware(middleware.Jwt()).Group(func(r route.Router) {
+			backupController := controllers.NewBackupController()
+			r.Get("storages", backupController.StorageList)
+			r.Post("storages", backupController.StorageStore)
+			r.Post("storages/hostKey", backupController.StorageHostKey)
+			r.Get("storages/{id}", backupController.StorageShow)
+			r.Put("storages/{id}", backupController.StorageUpdate)
+			r.Delete("storages/{id}", backupController.StorageDestroy)
+			r.Post("prune", backupController.Prune)
+			r.Get("catalog", backupController.Catalog)
+			r.Post("catalog/{id}/verify", backupController.Verify)
+			r.Get("encryption", backupController.Encryption)
+			r.Post("encryption", backupController.UpdateEncryption)
+			r.Get("keys", backupController.KeyList)
+			r.Post("keys", backupController.KeyStore)
+			r.Delete("keys/{id}", backupController.KeyDestroy)
+			r.Get("keys/{id}/identity", backupController.KeyIdentity)
+		})
 		r.Prefix("plugin").Middleware(middleware.Jwt()).Group(func(r route.Router) {
 			pluginController := controllers.NewPluginController()
 			r.Get("list", pluginController.List)
@@ -194,10 +265,19 @@ func Api() {
 			r.Post("info", sshController.UpdateInfo)
 			r.Get("session", sshController.Session)
 		})
+		r.Prefix("log").Middleware(middleware.Jwt()).Group(func(r route.Router) {
+			logController := controllers.NewLogController()
+			r.Get("sources", logController.Sources)
+			r.Get("read", logController.Read)
+			r.Get("stream", logController.Stream)
+		})
 		r.Prefix("setting").Middleware(middleware.Jwt()).Group(func(r route.Router) {
 			settingController := controllers.NewSettingController()
 			r.Get("list", settingController.List)
 			r.Post("update", settingController.Update)
+			r.Get("https", settingController.HTTPS)
+			r.Post("https", settingController.UpdateHTTPS)
+			r.Delete("https", settingController.DeleteHTTPS)
 		})
 	})
 
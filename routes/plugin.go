@@ -295,6 +295,11 @@ func Plugin() {
 			route.Get("load", redisController.Load)
 			route.Get("config", redisController.GetConfig)
 			route.Post("config", redisController.SaveConfig)
+			route.Get("backups", redisController.BackupList)
+			route.Post("backups", redisController.CreateBackup)
+			route.Put("backups", redisController.UploadBackup)
+			route.Delete("backups", redisController.DeleteBackup)
+			route.Post("backups/restore", redisController.RestoreBackup)
 		})
 		r.Prefix("s3fs").Group(func(route route.Router) {
 			s3fsController := plugins.NewS3fsController()